@@ -3,10 +3,16 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"go4/geom"
+	"go4/geom/meshio"
 	"go4/vis"
 	"go4/vis/gui"
 	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -18,6 +24,25 @@ type scenarioEntry struct {
 }
 
 func main() {
+	modelPath := flag.String("model", "", "mesh file to add as the first scenario ("+strings.Join(meshio.Extensions(), ", ")+")")
+	flag.Parse()
+
+	var model *geom.Mesh
+	if *modelPath != "" {
+		loaded, warnings, err := meshio.LoadWithWarnings(*modelPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load model: %v\n", err)
+			os.Exit(1)
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		// The camera orbits the origin, so center the model there
+		center := loaded.BoundingBox().Center()
+		loaded.Translate(geom.NewVectorFromVertices(center, geom.NewVertex(0, 0, 0)))
+		model = loaded
+	}
+
 	config := vis.DefaultApplicationConfig()
 	config.LoadTestScene = false
 	config.Title = "3D Visualization Developer Panel"
@@ -29,18 +54,19 @@ func main() {
 		panic(err)
 	}
 
-	setupGUI(app, config)
+	setupGUI(app, config, model, filepath.Base(*modelPath))
 	app.Run()
 }
 
-func setupGUI(app *vis.Application, appConfig vis.ApplicationConfig) {
-	ui := newDevPanelUI(app, appConfig)
+func setupGUI(app *vis.Application, appConfig vis.ApplicationConfig, model *geom.Mesh, modelName string) {
+	ui := newDevPanelUI(app, appConfig, model, modelName)
 	app.SetUpdateFunction(ui.update)
 }
 
 const (
 	tabRendererID   = "renderer"
 	tabNavigationID = "navigation"
+	tabPrimitivesID = "primitives"
 
 	margin          = float32(20)
 	tabHeight       = float32(72)
@@ -68,9 +94,13 @@ type devPanelUI struct {
 	navigationPanel   *gui.NavigationPanel
 	navigationPanelUI gui.Panel
 
+	primitiveSelector   *gui.PrimitiveSelector
+	primitiveSelectorUI gui.Panel
+
 	tabPanel            gui.Panel
 	rendererTabButton   gui.Button
 	navigationTabButton gui.Button
+	primitivesTabButton gui.Button
 	activeTab           string
 
 	scenarios       []scenarioEntry
@@ -83,7 +113,7 @@ type devPanelUI struct {
 	rightPanelWidth  float32
 }
 
-func newDevPanelUI(app *vis.Application, appConfig vis.ApplicationConfig) *devPanelUI {
+func newDevPanelUI(app *vis.Application, appConfig vis.ApplicationConfig, model *geom.Mesh, modelName string) *devPanelUI {
 	renderer := app.GetRenderer()
 
 	ui := &devPanelUI{
@@ -105,6 +135,9 @@ func newDevPanelUI(app *vis.Application, appConfig vis.ApplicationConfig) *devPa
 	ui.gui.AddElement(ui.infoPanelPanel)
 
 	ui.scenarios = ui.buildScenarios()
+	if model != nil {
+		ui.scenarios = append([]scenarioEntry{ui.modelScenario(model, modelName)}, ui.scenarios...)
+	}
 
 	ui.scenarioPanel = gui.NewScenarioPanel(gui.ScenarioPanelConfig{
 		X: margin,
@@ -125,6 +158,12 @@ func newDevPanelUI(app *vis.Application, appConfig vis.ApplicationConfig) *devPa
 	}, gui.NavigationCallbacks{})
 	ui.navigationPanelUI = ui.navigationPanel.GetPanel()
 
+	ui.primitiveSelector = gui.NewPrimitiveSelector(gui.PrimitiveSelectorConfig{
+		X: margin,
+		Y: margin + tabHeight + sectionSpacing,
+	}, ui.onPrimitiveChanged)
+	ui.primitiveSelectorUI = ui.primitiveSelector.GetPanel()
+
 	tabConfig := gui.DefaultPanelConfig()
 	tabConfig.X = margin
 	tabConfig.Y = margin
@@ -139,7 +178,7 @@ func newDevPanelUI(app *vis.Application, appConfig vis.ApplicationConfig) *devPa
 	ui.rendererTabButton = gui.NewButton(gui.ButtonConfig{
 		X:           tabConfig.X + 10,
 		Y:           tabConfig.Y + 16,
-		Width:       155,
+		Width:       100,
 		Height:      36,
 		Text:        "Renderer",
 		NormalColor: rl.NewColor(55, 55, 55, 255),
@@ -148,9 +187,9 @@ func newDevPanelUI(app *vis.Application, appConfig vis.ApplicationConfig) *devPa
 		FontSize:    16,
 	})
 	ui.navigationTabButton = gui.NewButton(gui.ButtonConfig{
-		X:           tabConfig.X + 175,
+		X:           tabConfig.X + 120,
 		Y:           tabConfig.Y + 16,
-		Width:       155,
+		Width:       100,
 		Height:      36,
 		Text:        "Navigation",
 		NormalColor: rl.NewColor(55, 55, 55, 255),
@@ -158,9 +197,21 @@ func newDevPanelUI(app *vis.Application, appConfig vis.ApplicationConfig) *devPa
 		TextColor:   rl.White,
 		FontSize:    16,
 	})
+	ui.primitivesTabButton = gui.NewButton(gui.ButtonConfig{
+		X:           tabConfig.X + 230,
+		Y:           tabConfig.Y + 16,
+		Width:       100,
+		Height:      36,
+		Text:        "Primitives",
+		NormalColor: rl.NewColor(55, 55, 55, 255),
+		HoverColor:  rl.NewColor(80, 80, 80, 255),
+		TextColor:   rl.White,
+		FontSize:    16,
+	})
 
 	ui.tabPanel.AddElement(ui.rendererTabButton)
 	ui.tabPanel.AddElement(ui.navigationTabButton)
+	ui.tabPanel.AddElement(ui.primitivesTabButton)
 
 	ui.activeTab = ""
 	ui.lastScreenWidth = rl.GetScreenWidth()
@@ -169,6 +220,10 @@ func newDevPanelUI(app *vis.Application, appConfig vis.ApplicationConfig) *devPa
 	ui.activateTab(tabRendererID)
 	ui.layout(ui.lastScreenWidth, ui.lastScreenHeight)
 
+	if model != nil {
+		ui.setScenario(0)
+	}
+
 	return ui
 }
 
@@ -188,6 +243,9 @@ func (ui *devPanelUI) update(deltaTime time.Duration) {
 	if ui.navigationTabButton.IsClicked() {
 		ui.activateTab(tabNavigationID)
 	}
+	if ui.primitivesTabButton.IsClicked() {
+		ui.activateTab(tabPrimitivesID)
+	}
 
 	ui.infoPanel.SetFPS(rl.GetFPS())
 	ui.infoPanel.SetCameraInfo(
@@ -196,6 +254,7 @@ func (ui *devPanelUI) update(deltaTime time.Duration) {
 		ui.camera.GetDistanceToScreen(),
 	)
 	ui.infoPanel.SetSceneCount(len(ui.app.GetScenes()))
+	ui.updateLODInfo()
 
 	ui.scenarioPanel.Update()
 
@@ -217,6 +276,8 @@ func (ui *devPanelUI) update(deltaTime time.Duration) {
 				ui.camera.ScaleLinear(-amount)
 			},
 		})
+	case tabPrimitivesID:
+		ui.primitiveSelector.HandleInput()
 	}
 
 	if ui.autoUpdate != nil {
@@ -244,6 +305,7 @@ func (ui *devPanelUI) layout(width, height int) {
 	contentY := margin + tabBounds.Height + sectionSpacing
 	ui.rendererPanelUI.SetPosition(rightX, contentY)
 	ui.navigationPanelUI.SetPosition(rightX, contentY)
+	ui.primitiveSelectorUI.SetPosition(rightX, contentY)
 }
 
 func (ui *devPanelUI) activateTab(id string) {
@@ -256,6 +318,8 @@ func (ui *devPanelUI) activateTab(id string) {
 		ui.gui.RemoveElement(ui.rendererPanelUI)
 	case tabNavigationID:
 		ui.gui.RemoveElement(ui.navigationPanelUI)
+	case tabPrimitivesID:
+		ui.gui.RemoveElement(ui.primitiveSelectorUI)
 	}
 
 	switch id {
@@ -263,6 +327,8 @@ func (ui *devPanelUI) activateTab(id string) {
 		ui.gui.AddElement(ui.rendererPanelUI)
 	case tabNavigationID:
 		ui.gui.AddElement(ui.navigationPanelUI)
+	case tabPrimitivesID:
+		ui.gui.AddElement(ui.primitiveSelectorUI)
 	default:
 		return
 	}
@@ -280,12 +346,17 @@ func (ui *devPanelUI) updateTabStyles() {
 	inactiveNormal := rl.NewColor(55, 55, 55, 255)
 	inactiveHover := rl.NewColor(80, 80, 80, 255)
 
-	if ui.activeTab == tabRendererID {
-		ui.rendererTabButton.SetColors(activeNormal, activeHover)
-		ui.navigationTabButton.SetColors(inactiveNormal, inactiveHover)
-	} else {
-		ui.rendererTabButton.SetColors(inactiveNormal, inactiveHover)
-		ui.navigationTabButton.SetColors(activeNormal, activeHover)
+	tabs := map[string]gui.Button{
+		tabRendererID:   ui.rendererTabButton,
+		tabNavigationID: ui.navigationTabButton,
+		tabPrimitivesID: ui.primitivesTabButton,
+	}
+	for id, button := range tabs {
+		if id == ui.activeTab {
+			button.SetColors(activeNormal, activeHover)
+		} else {
+			button.SetColors(inactiveNormal, inactiveHover)
+		}
 	}
 }
 
@@ -293,6 +364,14 @@ func (ui *devPanelUI) onScenarioSelected(index int) {
 	ui.setScenario(index)
 }
 
+// onPrimitiveChanged shows the selected primitive in place of the current scenario
+func (ui *devPanelUI) onPrimitiveChanged(primitive gui.PrimitiveType, params gui.PrimitiveParams) {
+	mesh := gui.NewPrimitiveMesh(primitive, params)
+	ui.autoUpdate = nil
+	ui.setSceneMesh(mesh)
+	ui.infoPanel.SetActiveScenario("Primitive: " + primitive.String())
+}
+
 func (ui *devPanelUI) onRendererConfigChanged(data gui.RendererConfigData) {
 	ui.applyRendererConfig(data)
 }
@@ -348,6 +427,75 @@ func (ui *devPanelUI) buildScenarios() []scenarioEntry {
 				}
 			},
 		},
+		{
+			data: gui.Scenario{
+				Name:        "Subdivided Cube",
+				Description: "Cube cage smoothed by three levels of Catmull-Clark subdivision.",
+			},
+			setup: func() {
+				ui.resetCamera()
+				cube := geom.CreateCube(260)
+				config := geom.DefaultSubdivisionConfig()
+				config.Scheme = geom.SubdivisionCatmullClark
+				config.Levels = 3
+				if err := cube.Subdivide(config); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to subdivide cube: %v\n", err)
+				}
+				ui.setSceneMesh(cube)
+				ui.autoUpdate = nil
+			},
+		},
+		{
+			data: gui.Scenario{
+				Name:        "Cube Minus Sphere",
+				Description: "Constructive solid geometry: a centered sphere carved out of a cube, leaving its edges and corners.",
+			},
+			setup: func() {
+				ui.resetCamera()
+				cube := geom.CreateCube(260)
+				carved, err := cube.Difference(geom.CreateIcosphere(170, 3))
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to subtract sphere: %v\n", err)
+					carved = cube
+				}
+				ui.setSceneMesh(carved)
+				ui.autoUpdate = func(delta float64) {
+					ui.camera.RotatePolar(delta * 0.4)
+				}
+			},
+		},
+		{
+			data: gui.Scenario{
+				Name:        "LOD Sphere",
+				Description: "Dense icosphere with decimated levels of detail, switched while zooming out.",
+			},
+			setup: func() {
+				ui.resetCamera()
+				ui.setSceneMesh(geom.CreateIcosphere(240, 4))
+				ui.setSceneLODs(vis.DefaultLODChainConfig())
+				ui.autoUpdate = func(delta float64) {
+					zoom := -math.Sin(ui.motionTime) * delta * 230
+					ui.camera.ScaleLinear(zoom)
+				}
+			},
+		},
+		{
+			data: gui.Scenario{
+				Name:        "Smooth Torus",
+				Description: "Torus with vertex normals under the default lights; cycle the shading in the renderer tab.",
+			},
+			setup: func() {
+				ui.resetCamera()
+				torus := geom.CreateTorus(170, 70, 32, 16)
+				if _, err := torus.ComputeVertexNormals(geom.DefaultVertexNormalConfig()); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to compute vertex normals: %v\n", err)
+				}
+				ui.setSceneMesh(torus)
+				ui.autoUpdate = func(delta float64) {
+					ui.camera.RotatePolar(delta * 0.4)
+				}
+			},
+		},
 		{
 			data: gui.Scenario{
 				Name:        "Breathing Cube",
@@ -365,6 +513,28 @@ func (ui *devPanelUI) buildScenarios() []scenarioEntry {
 	}
 }
 
+// modelScenario shows a mesh loaded from the command line
+func (ui *devPanelUI) modelScenario(model *geom.Mesh, name string) scenarioEntry {
+	var lods []*geom.Mesh
+	return scenarioEntry{
+		data: gui.Scenario{
+			Name:        "Model: " + name,
+			Description: fmt.Sprintf("Mesh loaded from file with %d vertices and %d faces.", model.VertexNumber(), model.FaceNumber()),
+		},
+		setup: func() {
+			ui.setCamera(vis.FramedCameraConfig(ui.config.Camera, model.BoundingSphere().Radius()))
+			ui.setSceneMesh(model)
+			// Decimating a large model takes a while, so the chain is built once
+			if lods == nil {
+				lods = ui.setSceneLODs(vis.DefaultLODChainConfig())
+			} else {
+				_ = ui.scene.SetMeshLODs(0, lods)
+			}
+			ui.autoUpdate = nil
+		},
+	}
+}
+
 func (ui *devPanelUI) extractScenarioData() []gui.Scenario {
 	data := make([]gui.Scenario, len(ui.scenarios))
 	for i, entry := range ui.scenarios {
@@ -386,7 +556,11 @@ func (ui *devPanelUI) setScenario(index int) {
 }
 
 func (ui *devPanelUI) resetCamera() {
-	newCam, err := vis.NewCamera(ui.config.Camera)
+	ui.setCamera(ui.config.Camera)
+}
+
+func (ui *devPanelUI) setCamera(config vis.CameraConfig) {
+	newCam, err := vis.NewCamera(config)
 	if err != nil {
 		return
 	}
@@ -401,6 +575,36 @@ func (ui *devPanelUI) setSceneMesh(mesh *geom.Mesh) {
 	}
 }
 
+// setSceneLODs builds a level of detail chain for the scene mesh and returns it
+func (ui *devPanelUI) setSceneLODs(config vis.LODChainConfig) []*geom.Mesh {
+	if ui.scene.MeshCount() == 0 {
+		return nil
+	}
+	lods, err := vis.BuildLODChain(ui.scene.GetMeshes()[0], config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to build levels of detail: %v\n", err)
+		return nil
+	}
+	_ = ui.scene.SetMeshLODs(0, lods)
+	return lods
+}
+
+// updateLODInfo shows the level of detail drawn for the scene mesh
+func (ui *devPanelUI) updateLODInfo() {
+	meshes := ui.scene.GetMeshes()
+	if len(meshes) == 0 {
+		ui.infoPanel.SetLODInfo(0, 0, 0)
+		return
+	}
+	lods := ui.scene.GetMeshLODs(0)
+	level := ui.renderer.SelectLOD(ui.scene, 0)
+	faces := meshes[0].FaceNumber()
+	if level > 0 {
+		faces = lods[level-1].FaceNumber()
+	}
+	ui.infoPanel.SetLODInfo(level, len(lods), faces)
+}
+
 func (ui *devPanelUI) toRendererConfigData() gui.RendererConfigData {
 	config := ui.app.GetRendererConfig()
 	faceColor := config.FaceColor
@@ -416,6 +620,8 @@ func (ui *devPanelUI) toRendererConfigData() gui.RendererConfigData {
 		DrawFaces:          config.DrawFaces,
 		DrawEdges:          config.DrawEdges,
 		UseBackfaceCulling: config.UseBackfaceCulling,
+		DepthSort:          gui.DepthSortType(config.DepthSort),
+		Shading:            gui.ShadingType(config.Shading),
 	}
 }
 
@@ -430,6 +636,9 @@ func (ui *devPanelUI) applyRendererConfig(data gui.RendererConfigData) {
 	config.DrawFaces = data.DrawFaces
 	config.DrawEdges = data.DrawEdges
 	config.UseBackfaceCulling = data.UseBackfaceCulling
+	// The gui mirrors list the depth sort and shading modes in the same order
+	config.DepthSort = vis.DepthSortMode(data.DepthSort)
+	config.Shading = vis.ShadingMode(data.Shading)
 
 	ui.app.SetRendererConfig(config)
 }
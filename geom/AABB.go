@@ -0,0 +1,121 @@
+// AABB.go
+package geom
+
+import "math"
+
+// AABB is an axis-aligned bounding box. The empty box has min > max on every
+// axis, so extending it by a point yields a box containing exactly that point.
+type AABB struct {
+	myMin Vertex
+	myMax Vertex
+}
+
+// NewAABB creates the box spanned by two opposite corners given in any order
+func NewAABB(a, b Vertex) AABB {
+	box := EmptyAABB()
+	box.Extend(a)
+	box.Extend(b)
+	return box
+}
+
+// EmptyAABB returns a box that contains nothing
+func EmptyAABB() AABB {
+	inf := math.Inf(1)
+	return AABB{NewVertex(inf, inf, inf), NewVertex(-inf, -inf, -inf)}
+}
+
+func (b AABB) Min() Vertex {
+	return b.myMin
+}
+
+func (b AABB) Max() Vertex {
+	return b.myMax
+}
+
+// IsEmpty reports whether the box contains no points
+func (b AABB) IsEmpty() bool {
+	return b.myMin.myCoords.X > b.myMax.myCoords.X ||
+		b.myMin.myCoords.Y > b.myMax.myCoords.Y ||
+		b.myMin.myCoords.Z > b.myMax.myCoords.Z
+}
+
+// Extend grows the box to contain v
+func (b *AABB) Extend(v Vertex) {
+	lower, upper, c := &b.myMin.myCoords, &b.myMax.myCoords, v.myCoords
+	if c.X < lower.X {
+		lower.X = c.X
+	}
+	if c.Y < lower.Y {
+		lower.Y = c.Y
+	}
+	if c.Z < lower.Z {
+		lower.Z = c.Z
+	}
+	if c.X > upper.X {
+		upper.X = c.X
+	}
+	if c.Y > upper.Y {
+		upper.Y = c.Y
+	}
+	if c.Z > upper.Z {
+		upper.Z = c.Z
+	}
+}
+
+// Union grows the box to contain other
+func (b *AABB) Union(other AABB) {
+	if other.IsEmpty() {
+		return
+	}
+	b.Extend(other.myMin)
+	b.Extend(other.myMax)
+}
+
+// United returns the smallest box containing both boxes
+func (b AABB) United(other AABB) AABB {
+	res := b
+	res.Union(other)
+	return res
+}
+
+// Center returns the midpoint of the box
+func (b AABB) Center() Vertex {
+	return NewVertex(
+		(b.myMin.myCoords.X+b.myMax.myCoords.X)/2,
+		(b.myMin.myCoords.Y+b.myMax.myCoords.Y)/2,
+		(b.myMin.myCoords.Z+b.myMax.myCoords.Z)/2,
+	)
+}
+
+// Size returns the extent along each axis; zero for an empty box
+func (b AABB) Size() Vector {
+	if b.IsEmpty() {
+		return Vector{}
+	}
+	return NewVectorFromVertices(b.myMin, b.myMax)
+}
+
+// SurfaceArea returns the area of the six sides of the box
+func (b AABB) SurfaceArea() float64 {
+	size := b.Size()
+	return 2 * (size.X()*size.Y() + size.Y()*size.Z() + size.Z()*size.X())
+}
+
+// Contains reports whether v lies inside the box or on its boundary
+func (b AABB) Contains(v Vertex) bool {
+	return v.myCoords.X >= b.myMin.myCoords.X && v.myCoords.X <= b.myMax.myCoords.X &&
+		v.myCoords.Y >= b.myMin.myCoords.Y && v.myCoords.Y <= b.myMax.myCoords.Y &&
+		v.myCoords.Z >= b.myMin.myCoords.Z && v.myCoords.Z <= b.myMax.myCoords.Z
+}
+
+// Overlaps reports whether the boxes share at least one point
+func (b AABB) Overlaps(other AABB) bool {
+	return b.myMin.myCoords.X <= other.myMax.myCoords.X && other.myMin.myCoords.X <= b.myMax.myCoords.X &&
+		b.myMin.myCoords.Y <= other.myMax.myCoords.Y && other.myMin.myCoords.Y <= b.myMax.myCoords.Y &&
+		b.myMin.myCoords.Z <= other.myMax.myCoords.Z && other.myMin.myCoords.Z <= b.myMax.myCoords.Z
+}
+
+// Equals compares the corners using DefaultTolerance
+func (b AABB) Equals(other AABB) bool {
+	return b.myMin.myCoords.Equals(other.myMin.myCoords) && b.myMax.myCoords.Equals(other.myMax.myCoords)
+}
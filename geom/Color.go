@@ -0,0 +1,20 @@
+// Color.go
+package geom
+
+import "math"
+
+// Color is an RGBA color with channels in the [0, 1] range
+type Color struct {
+	R, G, B, A float64
+}
+
+func NewColor(r, g, b, a float64) Color {
+	return Color{r, g, b, a}
+}
+
+func (c Color) Equals(other Color) bool {
+	return math.Abs(c.R-other.R) < DefaultTolerance &&
+		math.Abs(c.G-other.G) < DefaultTolerance &&
+		math.Abs(c.B-other.B) < DefaultTolerance &&
+		math.Abs(c.A-other.A) < DefaultTolerance
+}
@@ -0,0 +1,484 @@
+// HalfEdgeMesh.go
+package geom
+
+import (
+	"slices"
+	"sort"
+)
+
+// NoHalfEdge marks a missing half-edge, e.g. the twin of a boundary half-edge
+const NoHalfEdge = -1
+
+// Edge is an undirected mesh edge with V0 < V1 and the faces that use it in ascending order
+type Edge struct {
+	V0, V1 int
+	Faces  []int
+}
+
+// HalfEdgeMesh is the adjacency structure of a triangle mesh. Face f owns the
+// half-edges 3f, 3f+1 and 3f+2, which run from its first, second and third
+// vertex to the next one in winding order. Twins are linked only across
+// manifold edges (exactly two faces with opposite winding); boundary,
+// non-manifold and inconsistently oriented edges have no twin.
+//
+// Navigation methods take indices that must be valid, like slice indexing.
+type HalfEdgeMesh struct {
+	mySource *Mesh
+
+	myTwins      []int // per half-edge
+	myHalfEdges  []int // per half-edge: index of its undirected edge
+	myEdges      []Edge
+	myEdgeLookup map[[2]int]int
+
+	myVertexFaces [][]int // per vertex: incident faces in ascending order
+	myVertexEdges [][]int // per vertex: incident edges in ascending order
+}
+
+// NewHalfEdgeMesh builds the adjacency of m. The mesh is copied, so later
+// changes to m do not affect the structure.
+func NewHalfEdgeMesh(m *Mesh) *HalfEdgeMesh {
+	h := &HalfEdgeMesh{
+		mySource:      m.Clone(),
+		myTwins:       make([]int, 3*len(m.myFaces)),
+		myHalfEdges:   make([]int, 3*len(m.myFaces)),
+		myEdgeLookup:  make(map[[2]int]int),
+		myVertexFaces: make([][]int, len(m.myVertices)),
+		myVertexEdges: make([][]int, len(m.myVertices)),
+	}
+
+	for f, face := range m.myFaces {
+		for k := 0; k < 3; k++ {
+			a, b := face.myVertexIndices[k], face.myVertexIndices[(k+1)%3]
+			key := [2]int{min(a, b), max(a, b)}
+			edge, ok := h.myEdgeLookup[key]
+			if !ok {
+				edge = len(h.myEdges)
+				h.myEdgeLookup[key] = edge
+				h.myEdges = append(h.myEdges, Edge{V0: key[0], V1: key[1]})
+				h.myVertexEdges[key[0]] = append(h.myVertexEdges[key[0]], edge)
+				if key[1] != key[0] {
+					h.myVertexEdges[key[1]] = append(h.myVertexEdges[key[1]], edge)
+				}
+			}
+			faces := h.myEdges[edge].Faces
+			if len(faces) == 0 || faces[len(faces)-1] != f {
+				h.myEdges[edge].Faces = append(faces, f)
+			}
+			h.myHalfEdges[3*f+k] = edge
+			h.myTwins[3*f+k] = NoHalfEdge
+		}
+		for _, v := range uniqueIndices(face.myVertexIndices) {
+			h.myVertexFaces[v] = append(h.myVertexFaces[v], f)
+		}
+	}
+
+	for _, edges := range h.myVertexEdges {
+		sort.Ints(edges)
+	}
+
+	// Link twins across edges shared by exactly two faces with opposite winding
+	for _, edge := range h.myEdges {
+		if len(edge.Faces) != 2 || edge.Faces[0] == edge.Faces[1] {
+			continue
+		}
+		first := h.faceHalfEdge(edge.Faces[0], edge.V0, edge.V1)
+		second := h.faceHalfEdge(edge.Faces[1], edge.V0, edge.V1)
+		if first == NoHalfEdge || second == NoHalfEdge || h.Origin(first) == h.Origin(second) {
+			continue
+		}
+		h.myTwins[first] = second
+		h.myTwins[second] = first
+	}
+
+	return h
+}
+
+func uniqueIndices(indices [3]int) []int {
+	unique := []int{indices[0]}
+	for _, v := range indices[1:] {
+		if v != unique[0] && (len(unique) < 2 || v != unique[1]) {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// faceHalfEdge returns the half-edge of face f between a and b in either direction
+func (h *HalfEdgeMesh) faceHalfEdge(f, a, b int) int {
+	for k := 0; k < 3; k++ {
+		edge := 3*f + k
+		origin, target := h.Origin(edge), h.Target(edge)
+		if (origin == a && target == b) || (origin == b && target == a) {
+			return edge
+		}
+	}
+	return NoHalfEdge
+}
+
+// ToMesh returns a copy of the mesh the structure was built from, including
+// per-vertex data, materials and groups
+func (h *HalfEdgeMesh) ToMesh() *Mesh {
+	return h.mySource.Clone()
+}
+
+func (h *HalfEdgeMesh) VertexNumber() int {
+	return len(h.mySource.myVertices)
+}
+
+func (h *HalfEdgeMesh) FaceNumber() int {
+	return len(h.mySource.myFaces)
+}
+
+func (h *HalfEdgeMesh) HalfEdgeNumber() int {
+	return len(h.myTwins)
+}
+
+func (h *HalfEdgeMesh) EdgeNumber() int {
+	return len(h.myEdges)
+}
+
+// Origin returns the vertex the half-edge starts at
+func (h *HalfEdgeMesh) Origin(halfEdge int) int {
+	return h.mySource.myFaces[halfEdge/3].myVertexIndices[halfEdge%3]
+}
+
+// Target returns the vertex the half-edge points to
+func (h *HalfEdgeMesh) Target(halfEdge int) int {
+	return h.Origin(h.Next(halfEdge))
+}
+
+// Next returns the following half-edge of the same face
+func (h *HalfEdgeMesh) Next(halfEdge int) int {
+	return halfEdge - halfEdge%3 + (halfEdge+1)%3
+}
+
+// Prev returns the preceding half-edge of the same face
+func (h *HalfEdgeMesh) Prev(halfEdge int) int {
+	return halfEdge - halfEdge%3 + (halfEdge+2)%3
+}
+
+// Twin returns the opposite half-edge of the neighbouring face, or NoHalfEdge
+func (h *HalfEdgeMesh) Twin(halfEdge int) int {
+	return h.myTwins[halfEdge]
+}
+
+// Face returns the face that owns the half-edge
+func (h *HalfEdgeMesh) Face(halfEdge int) int {
+	return halfEdge / 3
+}
+
+// EdgeOf returns the undirected edge of a half-edge
+func (h *HalfEdgeMesh) EdgeOf(halfEdge int) int {
+	return h.myHalfEdges[halfEdge]
+}
+
+// FaceHalfEdges returns the three half-edges of a face in winding order
+func (h *HalfEdgeMesh) FaceHalfEdges(face int) [3]int {
+	return [3]int{3 * face, 3*face + 1, 3*face + 2}
+}
+
+// FaceNeighbors returns the faces across the three edges of a face, in the
+// order of FaceHalfEdges; -1 where the edge has no twin
+func (h *HalfEdgeMesh) FaceNeighbors(face int) [3]int {
+	var neighbors [3]int
+	for k, halfEdge := range h.FaceHalfEdges(face) {
+		neighbors[k] = -1
+		if twin := h.myTwins[halfEdge]; twin != NoHalfEdge {
+			neighbors[k] = h.Face(twin)
+		}
+	}
+	return neighbors
+}
+
+// Edge returns an undirected edge
+func (h *HalfEdgeMesh) Edge(edge int) Edge {
+	e := h.myEdges[edge]
+	e.Faces = append([]int(nil), e.Faces...)
+	return e
+}
+
+// FindEdge returns the index of the edge between two vertices
+func (h *HalfEdgeMesh) FindEdge(a, b int) (int, bool) {
+	edge, ok := h.myEdgeLookup[[2]int{min(a, b), max(a, b)}]
+	return edge, ok
+}
+
+// EdgeFaces returns the faces using the edge between two vertices, in ascending order
+func (h *HalfEdgeMesh) EdgeFaces(a, b int) []int {
+	edge, ok := h.FindEdge(a, b)
+	if !ok {
+		return nil
+	}
+	return append([]int(nil), h.myEdges[edge].Faces...)
+}
+
+// EdgeValence returns the number of faces using an edge
+func (h *HalfEdgeMesh) EdgeValence(edge int) int {
+	return len(h.myEdges[edge].Faces)
+}
+
+// IsBoundaryEdge reports whether exactly one face uses the edge
+func (h *HalfEdgeMesh) IsBoundaryEdge(edge int) bool {
+	return len(h.myEdges[edge].Faces) == 1
+}
+
+// IsBoundaryVertex reports whether the vertex lies on a boundary edge
+func (h *HalfEdgeMesh) IsBoundaryVertex(vertex int) bool {
+	for _, edge := range h.myVertexEdges[vertex] {
+		if h.IsBoundaryEdge(edge) {
+			return true
+		}
+	}
+	return false
+}
+
+// VertexFaces returns the faces around a vertex. Around manifold vertices they
+// are ordered by circulating in winding direction; otherwise they are sorted by index.
+func (h *HalfEdgeMesh) VertexFaces(vertex int) []int {
+	if fan, ok := h.circulate(vertex); ok {
+		faces := make([]int, len(fan))
+		for i, halfEdge := range fan {
+			faces[i] = h.Face(halfEdge)
+		}
+		return faces
+	}
+	return append([]int(nil), h.myVertexFaces[vertex]...)
+}
+
+// VertexNeighbors returns the one-ring of a vertex, ordered like VertexFaces
+func (h *HalfEdgeMesh) VertexNeighbors(vertex int) []int {
+	if fan, ok := h.circulate(vertex); ok && len(fan) > 0 {
+		neighbors := make([]int, 0, len(fan)+1)
+		for _, halfEdge := range fan {
+			neighbors = append(neighbors, h.Target(halfEdge))
+		}
+		last := fan[len(fan)-1]
+		if h.myTwins[h.Prev(last)] == NoHalfEdge {
+			// Open fan: the last face's incoming edge closes the ring
+			neighbors = append(neighbors, h.Origin(h.Prev(last)))
+		}
+		return neighbors
+	}
+
+	neighbors := make([]int, 0, len(h.myVertexEdges[vertex]))
+	for _, edge := range h.myVertexEdges[vertex] {
+		e := h.myEdges[edge]
+		if e.V0 == vertex {
+			neighbors = append(neighbors, e.V1)
+		} else {
+			neighbors = append(neighbors, e.V0)
+		}
+	}
+	sort.Ints(neighbors)
+	return neighbors
+}
+
+// VertexValence returns the number of edges at a vertex
+func (h *HalfEdgeMesh) VertexValence(vertex int) int {
+	return len(h.myVertexEdges[vertex])
+}
+
+// circulate returns the outgoing half-edges of a vertex in one fan, starting at
+// a boundary when there is one. It fails when the faces around the vertex do
+// not form a single fan connected by twins.
+func (h *HalfEdgeMesh) circulate(vertex int) ([]int, bool) {
+	faces := h.myVertexFaces[vertex]
+	if len(faces) == 0 {
+		return nil, true
+	}
+
+	start := NoHalfEdge
+	for _, halfEdge := range h.FaceHalfEdges(faces[0]) {
+		if h.Origin(halfEdge) == vertex {
+			start = halfEdge
+		}
+	}
+	if start == NoHalfEdge {
+		return nil, false
+	}
+
+	// Rewind to the boundary, if any: the previous outgoing half-edge is next(twin(h))
+	for current := start; ; {
+		twin := h.myTwins[current]
+		if twin == NoHalfEdge {
+			start = current
+			break
+		}
+		current = h.Next(twin)
+		if current == start {
+			break
+		}
+	}
+
+	var fan []int
+	for current := start; ; {
+		fan = append(fan, current)
+		twin := h.myTwins[h.Prev(current)]
+		if twin == NoHalfEdge || twin == start {
+			break
+		}
+		current = twin
+		if len(fan) > len(faces) {
+			return nil, false
+		}
+	}
+
+	return fan, len(fan) == len(faces)
+}
+
+// BoundaryEdges returns the edges used by exactly one face
+func (h *HalfEdgeMesh) BoundaryEdges() []int {
+	var edges []int
+	for i := range h.myEdges {
+		if h.IsBoundaryEdge(i) {
+			edges = append(edges, i)
+		}
+	}
+	return edges
+}
+
+// BoundaryLoops returns the closed vertex loops formed by boundary edges,
+// following the winding of the adjacent faces. A boundary passing through a
+// non-manifold vertex more than once is split into simple loops there.
+func (h *HalfEdgeMesh) BoundaryLoops() [][]int {
+	outgoing := make(map[int][]int)
+	var boundary []int
+	for f := range h.mySource.myFaces {
+		for _, halfEdge := range h.FaceHalfEdges(f) {
+			if h.IsBoundaryEdge(h.myHalfEdges[halfEdge]) {
+				outgoing[h.Origin(halfEdge)] = append(outgoing[h.Origin(halfEdge)], halfEdge)
+				boundary = append(boundary, halfEdge)
+			}
+		}
+	}
+
+	used := make(map[int]bool)
+	var loops [][]int
+	for _, start := range boundary {
+		if used[start] {
+			continue
+		}
+
+		var walk []int
+		for current := start; current != NoHalfEdge && !used[current]; {
+			used[current] = true
+
+			// Returning to a vertex of the walk closes a simple loop
+			origin := h.Origin(current)
+			if i := slices.Index(walk, origin); i >= 0 {
+				loops = append(loops, slices.Clone(walk[i:]))
+				walk = walk[:i]
+			}
+			walk = append(walk, origin)
+
+			next := NoHalfEdge
+			for _, candidate := range outgoing[h.Target(current)] {
+				if !used[candidate] {
+					next = candidate
+					break
+				}
+			}
+			current = next
+		}
+		if len(walk) > 0 {
+			loops = append(loops, walk)
+		}
+	}
+	return loops
+}
+
+// IsClosed reports whether every edge is shared by exactly two faces
+func (h *HalfEdgeMesh) IsClosed() bool {
+	for _, edge := range h.myEdges {
+		if len(edge.Faces) != 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// NonManifoldEdges returns the edges used by more than two faces
+func (h *HalfEdgeMesh) NonManifoldEdges() []int {
+	var edges []int
+	for i, edge := range h.myEdges {
+		if len(edge.Faces) > 2 {
+			edges = append(edges, i)
+		}
+	}
+	return edges
+}
+
+// InconsistentEdges returns the edges shared by two faces that traverse it in the same direction
+func (h *HalfEdgeMesh) InconsistentEdges() []int {
+	var edges []int
+	for i, edge := range h.myEdges {
+		if len(edge.Faces) != 2 {
+			continue
+		}
+		first := h.faceHalfEdge(edge.Faces[0], edge.V0, edge.V1)
+		second := h.faceHalfEdge(edge.Faces[1], edge.V0, edge.V1)
+		if first != NoHalfEdge && second != NoHalfEdge && h.Origin(first) == h.Origin(second) {
+			edges = append(edges, i)
+		}
+	}
+	return edges
+}
+
+// NonManifoldVertices returns the vertices whose faces form more than one fan
+// ("bow-tie" vertices) or that lie on a non-manifold edge
+func (h *HalfEdgeMesh) NonManifoldVertices() []int {
+	var vertices []int
+	for v, faces := range h.myVertexFaces {
+		if len(faces) == 0 {
+			continue
+		}
+
+		manifoldEdges := true
+		for _, edge := range h.myVertexEdges[v] {
+			if len(h.myEdges[edge].Faces) > 2 {
+				manifoldEdges = false
+			}
+		}
+		if !manifoldEdges || h.fanCount(v) > 1 {
+			vertices = append(vertices, v)
+		}
+	}
+	return vertices
+}
+
+// fanCount counts the groups of faces around a vertex that are connected
+// through edges at the vertex shared by exactly two faces, regardless of orientation
+func (h *HalfEdgeMesh) fanCount(vertex int) int {
+	faces := h.myVertexFaces[vertex]
+	parent := make(map[int]int, len(faces))
+	for _, f := range faces {
+		parent[f] = f
+	}
+	var find func(int) int
+	find = func(f int) int {
+		for parent[f] != f {
+			parent[f] = parent[parent[f]]
+			f = parent[f]
+		}
+		return f
+	}
+
+	groups := len(faces)
+	for _, edge := range h.myVertexEdges[vertex] {
+		shared := h.myEdges[edge].Faces
+		if len(shared) != 2 {
+			continue
+		}
+		a, b := find(shared[0]), find(shared[1])
+		if a != b {
+			parent[a] = b
+			groups--
+		}
+	}
+	return groups
+}
+
+// IsManifold reports whether every edge has at most two faces and every vertex a single fan
+func (h *HalfEdgeMesh) IsManifold() bool {
+	return len(h.NonManifoldEdges()) == 0 && len(h.NonManifoldVertices()) == 0
+}
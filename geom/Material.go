@@ -0,0 +1,134 @@
+// Material.go
+package geom
+
+import "fmt"
+
+const (
+	// NoMaterial is the material index of faces without an assigned material
+	NoMaterial = -1
+
+	// NoGroup is the group index of faces that do not belong to a named group
+	NoGroup = -1
+)
+
+// Material describes surface appearance in the spirit of Wavefront MTL
+type Material struct {
+	Name       string
+	Ambient    Color   // Ka
+	Diffuse    Color   // Kd, alpha holds the dissolve value (d)
+	Specular   Color   // Ks
+	Shininess  float64 // Ns
+	DiffuseMap string  // map_Kd texture path, relative to the material library
+}
+
+// NewMaterial creates a material with the MTL defaults
+func NewMaterial(name string) Material {
+	return Material{
+		Name:     name,
+		Ambient:  NewColor(0.2, 0.2, 0.2, 1),
+		Diffuse:  NewColor(0.8, 0.8, 0.8, 1),
+		Specular: NewColor(1, 1, 1, 1),
+	}
+}
+
+func (m *Mesh) MaterialNumber() int {
+	return len(m.myMaterials)
+}
+
+// AddMaterial appends a material and returns its index
+func (m *Mesh) AddMaterial(material Material) int {
+	m.myMaterials = append(m.myMaterials, material)
+	return len(m.myMaterials) - 1
+}
+
+// MaterialIndex returns the index of the material with the given name or NoMaterial
+func (m *Mesh) MaterialIndex(name string) int {
+	for i, material := range m.myMaterials {
+		if material.Name == name {
+			return i
+		}
+	}
+	return NoMaterial
+}
+
+func (m *Mesh) Material(materialIndex int) (Material, error) {
+	if materialIndex < 0 || materialIndex >= len(m.myMaterials) {
+		return Material{}, fmt.Errorf("material index out of bounds: %d (mesh has %d materials)", materialIndex, len(m.myMaterials))
+	}
+	return m.myMaterials[materialIndex], nil
+}
+
+// Materials returns a copy of the mesh materials
+func (m *Mesh) Materials() []Material {
+	result := make([]Material, len(m.myMaterials))
+	copy(result, m.myMaterials)
+	return result
+}
+
+func (m *Mesh) SetMaterial(materialIndex int, material Material) error {
+	if materialIndex < 0 || materialIndex >= len(m.myMaterials) {
+		return fmt.Errorf("material index out of bounds: %d (mesh has %d materials)", materialIndex, len(m.myMaterials))
+	}
+	m.myMaterials[materialIndex] = material
+	return nil
+}
+
+// SetFaceMaterial assigns a material to the face; NoMaterial clears the assignment
+func (m *Mesh) SetFaceMaterial(faceIndex, materialIndex int) error {
+	if faceIndex < 0 || faceIndex >= len(m.myFaces) {
+		return fmt.Errorf("face index out of bounds: %d (mesh has %d faces)", faceIndex, len(m.myFaces))
+	}
+	if materialIndex != NoMaterial && (materialIndex < 0 || materialIndex >= len(m.myMaterials)) {
+		return fmt.Errorf("material index out of bounds: %d (mesh has %d materials)", materialIndex, len(m.myMaterials))
+	}
+	m.myFaces[faceIndex].myMaterial = materialIndex
+	return nil
+}
+
+func (m *Mesh) FaceMaterial(faceIndex int) (int, error) {
+	if faceIndex < 0 || faceIndex >= len(m.myFaces) {
+		return NoMaterial, fmt.Errorf("face index out of bounds: %d (mesh has %d faces)", faceIndex, len(m.myFaces))
+	}
+	return m.myFaces[faceIndex].myMaterial, nil
+}
+
+func (m *Mesh) GroupNumber() int {
+	return len(m.myGroups)
+}
+
+// AddGroup returns the index of the named face group, creating it if needed
+func (m *Mesh) AddGroup(name string) int {
+	for i, group := range m.myGroups {
+		if group == name {
+			return i
+		}
+	}
+	m.myGroups = append(m.myGroups, name)
+	return len(m.myGroups) - 1
+}
+
+func (m *Mesh) GroupName(groupIndex int) (string, error) {
+	if groupIndex < 0 || groupIndex >= len(m.myGroups) {
+		return "", fmt.Errorf("group index out of bounds: %d (mesh has %d groups)", groupIndex, len(m.myGroups))
+	}
+	return m.myGroups[groupIndex], nil
+}
+
+// SetFaceGroup assigns the face to a group; NoGroup clears the assignment
+func (m *Mesh) SetFaceGroup(faceIndex, groupIndex int) error {
+	if faceIndex < 0 || faceIndex >= len(m.myFaces) {
+		return fmt.Errorf("face index out of bounds: %d (mesh has %d faces)", faceIndex, len(m.myFaces))
+	}
+	if groupIndex != NoGroup && (groupIndex < 0 || groupIndex >= len(m.myGroups)) {
+		return fmt.Errorf("group index out of bounds: %d (mesh has %d groups)", groupIndex, len(m.myGroups))
+	}
+	m.myFaces[faceIndex].myGroup = groupIndex
+	return nil
+}
+
+func (m *Mesh) FaceGroup(faceIndex int) (int, error) {
+	if faceIndex < 0 || faceIndex >= len(m.myFaces) {
+		return NoGroup, fmt.Errorf("face index out of bounds: %d (mesh has %d faces)", faceIndex, len(m.myFaces))
+	}
+	return m.myFaces[faceIndex].myGroup, nil
+}
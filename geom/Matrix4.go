@@ -0,0 +1,377 @@
+// Matrix4.go
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// Matrix4 is a 4x4 transform matrix. Points and vectors are treated as
+// columns, so a.Multiplied(b) applies b first and a second.
+type Matrix4 struct {
+	myValues [4][4]float64 // [row][column]
+}
+
+// NewMatrix4 creates a matrix from 16 values in row-major order
+func NewMatrix4(values [16]float64) Matrix4 {
+	var m Matrix4
+	for row := 0; row < 4; row++ {
+		for column := 0; column < 4; column++ {
+			m.myValues[row][column] = values[row*4+column]
+		}
+	}
+	return m
+}
+
+// NewMatrix4ColumnMajor creates a matrix from 16 values in column-major order, the layout used by OpenGL and glTF
+func NewMatrix4ColumnMajor(values [16]float64) Matrix4 {
+	m := NewMatrix4(values)
+	m.Transpose()
+	return m
+}
+
+// IdentityMatrix4 returns the identity transform
+func IdentityMatrix4() Matrix4 {
+	var m Matrix4
+	for i := 0; i < 4; i++ {
+		m.myValues[i][i] = 1
+	}
+	return m
+}
+
+// NewTranslationMatrix returns a transform that moves points by offset
+func NewTranslationMatrix(offset Vector) Matrix4 {
+	m := IdentityMatrix4()
+	m.myValues[0][3] = offset.X()
+	m.myValues[1][3] = offset.Y()
+	m.myValues[2][3] = offset.Z()
+	return m
+}
+
+// NewScaleMatrix returns a transform that scales along the coordinate axes
+func NewScaleMatrix(x, y, z float64) Matrix4 {
+	m := IdentityMatrix4()
+	m.myValues[0][0] = x
+	m.myValues[1][1] = y
+	m.myValues[2][2] = z
+	return m
+}
+
+// NewRotationMatrix returns a rotation by angle radians around axis (right-hand rule)
+func NewRotationMatrix(axis Vector, angle float64) Matrix4 {
+	return NewQuaternionFromAxisAngle(axis, angle).Matrix()
+}
+
+// ComposeMatrix4 returns translation * rotation * scale, i.e. the transform
+// that scales first, then rotates and finally translates
+func ComposeMatrix4(translation Vector, rotation Quaternion, scale Vector) Matrix4 {
+	m := NewTranslationMatrix(translation)
+	m.Multiply(rotation.Matrix())
+	m.Multiply(NewScaleMatrix(scale.X(), scale.Y(), scale.Z()))
+	return m
+}
+
+// NewLookAtMatrix returns a right-handed view matrix for a camera at eye looking
+// at target. In view space the camera looks down -Z with +Y up, as in OpenGL.
+func NewLookAtMatrix(eye, target Vertex, up Vector) (Matrix4, error) {
+	forward := NewVectorFromVertices(eye, target)
+	if forward.Length() < DefaultTolerance {
+		return Matrix4{}, fmt.Errorf("look-at target coincides with eye")
+	}
+	forward.Normalize()
+
+	right := forward.Cross(up)
+	if right.Length() < DefaultTolerance {
+		return Matrix4{}, fmt.Errorf("look-at up vector %v is parallel to the view direction", up)
+	}
+	right.Normalize()
+	trueUp := right.Cross(forward)
+
+	position := NewVectorFromVertex(eye)
+	return NewMatrix4([16]float64{
+		right.X(), right.Y(), right.Z(), -right.Dot(position),
+		trueUp.X(), trueUp.Y(), trueUp.Z(), -trueUp.Dot(position),
+		-forward.X(), -forward.Y(), -forward.Z(), forward.Dot(position),
+		0, 0, 0, 1,
+	}), nil
+}
+
+// NewPerspectiveMatrix returns an OpenGL-style projection mapping the view
+// frustum to clip space with depth in [-1, 1]. fovY is the vertical field of view in radians.
+func NewPerspectiveMatrix(fovY, aspect, near, far float64) (Matrix4, error) {
+	if fovY <= 0 || fovY >= math.Pi {
+		return Matrix4{}, fmt.Errorf("field of view must be in (0, π), got %f", fovY)
+	}
+	if aspect <= 0 {
+		return Matrix4{}, fmt.Errorf("aspect ratio must be positive, got %f", aspect)
+	}
+	if near <= 0 || far <= near {
+		return Matrix4{}, fmt.Errorf("clip planes must satisfy 0 < near < far, got %f and %f", near, far)
+	}
+
+	f := 1 / math.Tan(fovY/2)
+	return NewMatrix4([16]float64{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, (far + near) / (near - far), 2 * far * near / (near - far),
+		0, 0, -1, 0,
+	}), nil
+}
+
+// NewOrthographicMatrix returns an OpenGL-style projection mapping the given
+// view-space box to clip space with depth in [-1, 1]
+func NewOrthographicMatrix(left, right, bottom, top, near, far float64) (Matrix4, error) {
+	if left == right || bottom == top || near == far {
+		return Matrix4{}, fmt.Errorf("orthographic volume must not be empty")
+	}
+
+	return NewMatrix4([16]float64{
+		2 / (right - left), 0, 0, -(right + left) / (right - left),
+		0, 2 / (top - bottom), 0, -(top + bottom) / (top - bottom),
+		0, 0, -2 / (far - near), -(far + near) / (far - near),
+		0, 0, 0, 1,
+	}), nil
+}
+
+// At returns the element at the given row and column
+func (m Matrix4) At(row, column int) float64 {
+	return m.myValues[row][column]
+}
+
+// Set changes the element at the given row and column
+func (m *Matrix4) Set(row, column int, value float64) {
+	m.myValues[row][column] = value
+}
+
+// RowMajor returns the elements in row-major order
+func (m Matrix4) RowMajor() [16]float64 {
+	var values [16]float64
+	for row := 0; row < 4; row++ {
+		for column := 0; column < 4; column++ {
+			values[row*4+column] = m.myValues[row][column]
+		}
+	}
+	return values
+}
+
+// ColumnMajor returns the elements in column-major order
+func (m Matrix4) ColumnMajor() [16]float64 {
+	return m.Transposed().RowMajor()
+}
+
+// Equals compares element-wise using DefaultTolerance
+func (m Matrix4) Equals(other Matrix4) bool {
+	for row := 0; row < 4; row++ {
+		for column := 0; column < 4; column++ {
+			if math.Abs(m.myValues[row][column]-other.myValues[row][column]) >= DefaultTolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Multiply sets m to m * other
+func (m *Matrix4) Multiply(other Matrix4) {
+	*m = m.Multiplied(other)
+}
+
+// Multiplied returns m * other
+func (m Matrix4) Multiplied(other Matrix4) Matrix4 {
+	var res Matrix4
+	for row := 0; row < 4; row++ {
+		for column := 0; column < 4; column++ {
+			sum := 0.0
+			for k := 0; k < 4; k++ {
+				sum += m.myValues[row][k] * other.myValues[k][column]
+			}
+			res.myValues[row][column] = sum
+		}
+	}
+	return res
+}
+
+// Transpose swaps rows and columns in place
+func (m *Matrix4) Transpose() {
+	for row := 0; row < 4; row++ {
+		for column := row + 1; column < 4; column++ {
+			m.myValues[row][column], m.myValues[column][row] = m.myValues[column][row], m.myValues[row][column]
+		}
+	}
+}
+
+// Transposed returns the transposed matrix
+func (m Matrix4) Transposed() Matrix4 {
+	res := m
+	res.Transpose()
+	return res
+}
+
+// Determinant returns the determinant of the full 4x4 matrix
+func (m Matrix4) Determinant() float64 {
+	det := 0.0
+	for column := 0; column < 4; column++ {
+		det += m.myValues[0][column] * m.cofactor(0, column)
+	}
+	return det
+}
+
+// Determinant3 returns the determinant of the upper-left 3x3 block; it is
+// negative when the linear part mirrors, i.e. flips triangle winding
+func (m Matrix4) Determinant3() float64 {
+	a := m.myValues
+	return a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) -
+		a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) +
+		a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+}
+
+// cofactor returns the signed minor of the element at row, column
+func (m Matrix4) cofactor(row, column int) float64 {
+	var minor [3][3]float64
+	r := 0
+	for i := 0; i < 4; i++ {
+		if i == row {
+			continue
+		}
+		c := 0
+		for j := 0; j < 4; j++ {
+			if j == column {
+				continue
+			}
+			minor[r][c] = m.myValues[i][j]
+			c++
+		}
+		r++
+	}
+
+	det := minor[0][0]*(minor[1][1]*minor[2][2]-minor[1][2]*minor[2][1]) -
+		minor[0][1]*(minor[1][0]*minor[2][2]-minor[1][2]*minor[2][0]) +
+		minor[0][2]*(minor[1][0]*minor[2][1]-minor[1][1]*minor[2][0])
+	if (row+column)%2 == 1 {
+		return -det
+	}
+	return det
+}
+
+// Invert replaces m with its inverse; singular matrices are left unchanged and reported
+func (m *Matrix4) Invert() error {
+	inverse, err := m.Inverted()
+	if err != nil {
+		return err
+	}
+	*m = inverse
+	return nil
+}
+
+// Inverted returns the inverse matrix
+func (m Matrix4) Inverted() (Matrix4, error) {
+	// Only an exactly zero determinant makes the matrix singular: an absolute
+	// threshold would reject small but valid scales, and there is no scale
+	// free one for affine matrices, whose translation can be of any size
+	det := m.Determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Matrix4{}, fmt.Errorf("matrix is singular (determinant %g)", det)
+	}
+
+	// The inverse is the transposed cofactor matrix divided by the determinant
+	var res Matrix4
+	for row := 0; row < 4; row++ {
+		for column := 0; column < 4; column++ {
+			res.myValues[column][row] = m.cofactor(row, column) / det
+			if value := res.myValues[column][row]; math.IsNaN(value) || math.IsInf(value, 0) {
+				return Matrix4{}, fmt.Errorf("matrix is singular (determinant %g)", det)
+			}
+		}
+	}
+	return res, nil
+}
+
+// TransformVertex applies the full transform to a point, including the
+// perspective divide when the resulting w is not 1
+func (m Matrix4) TransformVertex(v Vertex) Vertex {
+	x, y, z := v.X(), v.Y(), v.Z()
+	a := m.myValues
+	rx := a[0][0]*x + a[0][1]*y + a[0][2]*z + a[0][3]
+	ry := a[1][0]*x + a[1][1]*y + a[1][2]*z + a[1][3]
+	rz := a[2][0]*x + a[2][1]*y + a[2][2]*z + a[2][3]
+	w := a[3][0]*x + a[3][1]*y + a[3][2]*z + a[3][3]
+	if w != 1 && w != 0 {
+		rx, ry, rz = rx/w, ry/w, rz/w
+	}
+	return NewVertex(rx, ry, rz)
+}
+
+// TransformVector applies the linear part of the transform to a direction; translation is ignored
+func (m Matrix4) TransformVector(v Vector) Vector {
+	x, y, z := v.X(), v.Y(), v.Z()
+	a := m.myValues
+	return NewVector(
+		a[0][0]*x+a[0][1]*y+a[0][2]*z,
+		a[1][0]*x+a[1][1]*y+a[1][2]*z,
+		a[2][0]*x+a[2][1]*y+a[2][2]*z,
+	)
+}
+
+// NormalMatrix returns the inverse transpose of the upper-left 3x3 block, which
+// keeps normals perpendicular to transformed surfaces under non-uniform scale.
+// It is computed from cofactors, so it exists even for singular transforms, and
+// is scaled by |det| rather than det to keep the normal's orientation.
+func (m Matrix4) NormalMatrix() Matrix4 {
+	a := m.myValues
+	res := IdentityMatrix4()
+	for row := 0; row < 3; row++ {
+		for column := 0; column < 3; column++ {
+			r1, r2 := (row+1)%3, (row+2)%3
+			c1, c2 := (column+1)%3, (column+2)%3
+			res.myValues[row][column] = a[r1][c1]*a[r2][c2] - a[r1][c2]*a[r2][c1]
+		}
+	}
+	if m.Determinant3() < 0 {
+		for row := 0; row < 3; row++ {
+			for column := 0; column < 3; column++ {
+				res.myValues[row][column] = -res.myValues[row][column]
+			}
+		}
+	}
+	return res
+}
+
+// TransformNormal transforms a surface normal with the normal matrix and normalizes the result
+func (m Matrix4) TransformNormal(n Vector) Vector {
+	res := m.NormalMatrix().TransformVector(n)
+	res.Normalize()
+	return res
+}
+
+// Translation returns the translation part of an affine transform
+func (m Matrix4) Translation() Vector {
+	return NewVector(m.myValues[0][3], m.myValues[1][3], m.myValues[2][3])
+}
+
+// Decompose splits an affine transform without shear into translation,
+// rotation and scale so that ComposeMatrix4 rebuilds it. A mirroring
+// transform is reported as a negative X scale.
+func (m Matrix4) Decompose() (translation Vector, rotation Quaternion, scale Vector) {
+	a := m.myValues
+	columns := [3]Vector{
+		NewVector(a[0][0], a[1][0], a[2][0]),
+		NewVector(a[0][1], a[1][1], a[2][1]),
+		NewVector(a[0][2], a[1][2], a[2][2]),
+	}
+	sx, sy, sz := columns[0].Length(), columns[1].Length(), columns[2].Length()
+	if m.Determinant3() < 0 {
+		sx = -sx
+	}
+
+	rotationMatrix := IdentityMatrix4()
+	for i, s := range [3]float64{sx, sy, sz} {
+		if s == 0 {
+			continue
+		}
+		rotationMatrix.myValues[0][i] = columns[i].X() / s
+		rotationMatrix.myValues[1][i] = columns[i].Y() / s
+		rotationMatrix.myValues[2][i] = columns[i].Z() / s
+	}
+
+	return m.Translation(), NewQuaternionFromMatrix(rotationMatrix), NewVector(sx, sy, sz)
+}
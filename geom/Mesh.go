@@ -1,11 +1,16 @@
 // Mesh.go
 package geom
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 type Triangle struct {
 	myVertexIndices [3]int
 	myNormal        Vector
+	myMaterial      int
+	myGroup         int
 }
 
 func (t *Triangle) SetNormal(normal Vector) {
@@ -15,6 +20,44 @@ func (t *Triangle) SetNormal(normal Vector) {
 type Mesh struct {
 	myVertices []Vertex
 	myFaces    []Triangle
+
+	// Optional per-vertex data: either nil or exactly one entry per vertex
+	myNormals []Vector
+	myUVs     []Vertex2d
+	myColors  []Color
+
+	myAttributes []VertexAttribute
+
+	myMaterials []Material
+	myGroups    []string
+
+	// Scalar types of the source file, nil for meshes that were not read from one
+	myStorage *vertexStorage
+}
+
+// vertexStorage records the scalar types that positions, normals and colors
+// were read with, so that writers can store them the same way
+type vertexStorage struct {
+	position, normal, color ScalarType
+}
+
+// Clone returns a deep copy of the mesh
+func (m *Mesh) Clone() *Mesh {
+	res := &Mesh{
+		myVertices:  slices.Clone(m.myVertices),
+		myFaces:     slices.Clone(m.myFaces),
+		myNormals:   slices.Clone(m.myNormals),
+		myUVs:       slices.Clone(m.myUVs),
+		myColors:    slices.Clone(m.myColors),
+		myMaterials: slices.Clone(m.myMaterials),
+		myGroups:    slices.Clone(m.myGroups),
+		myStorage:   m.myStorage,
+	}
+	for _, attribute := range m.myAttributes {
+		attribute.Values = slices.Clone(attribute.Values)
+		res.myAttributes = append(res.myAttributes, attribute)
+	}
+	return res
 }
 
 func (m *Mesh) VertexNumber() int {
@@ -27,6 +70,18 @@ func (m *Mesh) FaceNumber() int {
 
 func (m *Mesh) AddVertex(v Vertex) int {
 	m.myVertices = append(m.myVertices, v)
+	if m.myNormals != nil {
+		m.myNormals = append(m.myNormals, Vector{})
+	}
+	if m.myUVs != nil {
+		m.myUVs = append(m.myUVs, Vertex2d{})
+	}
+	if m.myColors != nil {
+		m.myColors = append(m.myColors, Color{})
+	}
+	for i := range m.myAttributes {
+		m.myAttributes[i].Values = append(m.myAttributes[i].Values, 0)
+	}
 	return len(m.myVertices) - 1
 }
 
@@ -56,6 +111,8 @@ func (m *Mesh) AddFace(v1, v2, v3 int) (int, error) {
 	face := Triangle{
 		myVertexIndices: [3]int{v1, v2, v3},
 		myNormal:        normal,
+		myMaterial:      NoMaterial,
+		myGroup:         NoGroup,
 	}
 	m.myFaces = append(m.myFaces, face)
 
@@ -94,3 +151,92 @@ func (m *Mesh) Normal(faceIndex int) (Vector, error) {
 	}
 	return m.myFaces[faceIndex].myNormal, nil
 }
+
+// HasVertexNormals reports whether the mesh stores per-vertex normals
+func (m *Mesh) HasVertexNormals() bool {
+	return m.myNormals != nil
+}
+
+// SetVertexNormal stores a normal for the vertex, enabling per-vertex normals on first use
+func (m *Mesh) SetVertexNormal(vertexIndex int, normal Vector) error {
+	if vertexIndex < 0 || vertexIndex >= len(m.myVertices) {
+		return fmt.Errorf("vertex index out of bounds: %d (mesh has %d vertices)", vertexIndex, len(m.myVertices))
+	}
+	if m.myNormals == nil {
+		m.myNormals = make([]Vector, len(m.myVertices))
+	}
+	m.myNormals[vertexIndex] = normal
+	return nil
+}
+
+func (m *Mesh) VertexNormal(vertexIndex int) (Vector, error) {
+	if vertexIndex < 0 || vertexIndex >= len(m.myVertices) {
+		return Vector{}, fmt.Errorf("vertex index out of bounds: %d (mesh has %d vertices)", vertexIndex, len(m.myVertices))
+	}
+	if m.myNormals == nil {
+		return Vector{}, fmt.Errorf("mesh has no vertex normals")
+	}
+	return m.myNormals[vertexIndex], nil
+}
+
+// HasVertexUVs reports whether the mesh stores per-vertex texture coordinates
+func (m *Mesh) HasVertexUVs() bool {
+	return m.myUVs != nil
+}
+
+// SetVertexUV stores texture coordinates for the vertex, enabling UVs on first use
+func (m *Mesh) SetVertexUV(vertexIndex int, uv Vertex2d) error {
+	if vertexIndex < 0 || vertexIndex >= len(m.myVertices) {
+		return fmt.Errorf("vertex index out of bounds: %d (mesh has %d vertices)", vertexIndex, len(m.myVertices))
+	}
+	if m.myUVs == nil {
+		m.myUVs = make([]Vertex2d, len(m.myVertices))
+	}
+	m.myUVs[vertexIndex] = uv
+	return nil
+}
+
+func (m *Mesh) VertexUV(vertexIndex int) (Vertex2d, error) {
+	if vertexIndex < 0 || vertexIndex >= len(m.myVertices) {
+		return Vertex2d{}, fmt.Errorf("vertex index out of bounds: %d (mesh has %d vertices)", vertexIndex, len(m.myVertices))
+	}
+	if m.myUVs == nil {
+		return Vertex2d{}, fmt.Errorf("mesh has no vertex UVs")
+	}
+	return m.myUVs[vertexIndex], nil
+}
+
+// HasVertexColors reports whether the mesh stores per-vertex colors
+func (m *Mesh) HasVertexColors() bool {
+	return m.myColors != nil
+}
+
+// SetVertexColor stores a color for the vertex, enabling per-vertex colors on first use
+func (m *Mesh) SetVertexColor(vertexIndex int, color Color) error {
+	if vertexIndex < 0 || vertexIndex >= len(m.myVertices) {
+		return fmt.Errorf("vertex index out of bounds: %d (mesh has %d vertices)", vertexIndex, len(m.myVertices))
+	}
+	if m.myColors == nil {
+		m.myColors = make([]Color, len(m.myVertices))
+	}
+	m.myColors[vertexIndex] = color
+	return nil
+}
+
+func (m *Mesh) VertexColor(vertexIndex int) (Color, error) {
+	if vertexIndex < 0 || vertexIndex >= len(m.myVertices) {
+		return Color{}, fmt.Errorf("vertex index out of bounds: %d (mesh has %d vertices)", vertexIndex, len(m.myVertices))
+	}
+	if m.myColors == nil {
+		return Color{}, fmt.Errorf("mesh has no vertex colors")
+	}
+	return m.myColors[vertexIndex], nil
+}
+
+// FaceIndices returns the mesh vertex indices of the face corners
+func (m *Mesh) FaceIndices(faceIndex int) ([3]int, error) {
+	if faceIndex < 0 || faceIndex >= len(m.myFaces) {
+		return [3]int{}, fmt.Errorf("face index out of bounds: %d (mesh has %d faces)", faceIndex, len(m.myFaces))
+	}
+	return m.myFaces[faceIndex].myVertexIndices, nil
+}
@@ -0,0 +1,65 @@
+// Plane.go
+package geom
+
+// Plane is the set of points p with normal·p = offset. The normal is stored
+// normalized, so distances are in world units; the normal points to the front.
+type Plane struct {
+	myNormal Vector
+	myOffset float64
+}
+
+// NewPlane creates the plane normal·p = offset. A zero normal gives a plane
+// that every point lies on.
+func NewPlane(normal Vector, offset float64) Plane {
+	length := normal.Length()
+	if length == 0 {
+		return Plane{}
+	}
+	return Plane{normal.Multiplied(1 / length), offset / length}
+}
+
+// NewPlaneThroughPoint creates the plane through point with the given normal
+func NewPlaneThroughPoint(point Vertex, normal Vector) Plane {
+	normal.Normalize()
+	return Plane{normal, normal.Dot(NewVectorFromVertex(point))}
+}
+
+func (p Plane) Normal() Vector {
+	return p.myNormal
+}
+
+func (p Plane) Offset() float64 {
+	return p.myOffset
+}
+
+// Distance returns the signed distance of v from the plane, positive in front
+func (p Plane) Distance(v Vertex) float64 {
+	return p.myNormal.Dot(NewVectorFromVertex(v)) - p.myOffset
+}
+
+// Transformed returns the plane carried along by an affine transform, keeping
+// the images of points in front of it in front
+func (p Plane) Transformed(matrix Matrix4) Plane {
+	point := Vertex{p.myNormal.Multiplied(p.myOffset).myCoords}
+	return NewPlaneThroughPoint(matrix.TransformVertex(point), matrix.TransformNormal(p.myNormal))
+}
+
+// IsAABBBehind reports whether the whole box lies behind the plane. Empty
+// boxes are always behind.
+func (p Plane) IsAABBBehind(box AABB) bool {
+	if box.IsEmpty() {
+		return true
+	}
+	// The corner farthest along the normal decides
+	corner := box.myMin.myCoords
+	if p.myNormal.X() > 0 {
+		corner.X = box.myMax.myCoords.X
+	}
+	if p.myNormal.Y() > 0 {
+		corner.Y = box.myMax.myCoords.Y
+	}
+	if p.myNormal.Z() > 0 {
+		corner.Z = box.myMax.myCoords.Z
+	}
+	return p.Distance(Vertex{corner}) < 0
+}
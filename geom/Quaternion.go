@@ -0,0 +1,240 @@
+// Quaternion.go
+package geom
+
+import "math"
+
+// Quaternion represents a rotation as x*i + y*j + z*k + w. Rotations use unit
+// quaternions; q and -q describe the same rotation.
+type Quaternion struct {
+	myX, myY, myZ, myW float64
+}
+
+func NewQuaternion(x, y, z, w float64) Quaternion {
+	return Quaternion{x, y, z, w}
+}
+
+// IdentityQuaternion returns the rotation that leaves vectors unchanged
+func IdentityQuaternion() Quaternion {
+	return Quaternion{0, 0, 0, 1}
+}
+
+// NewQuaternionFromAxisAngle returns a rotation by angle radians around axis (right-hand rule).
+// A zero axis gives the identity.
+func NewQuaternionFromAxisAngle(axis Vector, angle float64) Quaternion {
+	if axis.Length() == 0 {
+		return IdentityQuaternion()
+	}
+	axis.Normalize()
+	s := math.Sin(angle / 2)
+	return Quaternion{axis.X() * s, axis.Y() * s, axis.Z() * s, math.Cos(angle / 2)}
+}
+
+// NewQuaternionFromEuler returns the rotation that turns around X by x, then
+// around Y by y and finally around Z by z, all in radians and about the fixed
+// world axes (R = Rz * Ry * Rx)
+func NewQuaternionFromEuler(x, y, z float64) Quaternion {
+	qx := NewQuaternionFromAxisAngle(NewVector(1, 0, 0), x)
+	qy := NewQuaternionFromAxisAngle(NewVector(0, 1, 0), y)
+	qz := NewQuaternionFromAxisAngle(NewVector(0, 0, 1), z)
+	return qz.Multiplied(qy).Multiplied(qx)
+}
+
+// NewQuaternionFromMatrix extracts the rotation of a matrix whose upper-left 3x3 block is orthonormal
+func NewQuaternionFromMatrix(m Matrix4) Quaternion {
+	a := m.myValues
+	var q Quaternion
+	trace := a[0][0] + a[1][1] + a[2][2]
+
+	// Pick the largest component as the divisor for numerical stability
+	switch {
+	case trace > 0:
+		s := 2 * math.Sqrt(trace+1)
+		q = Quaternion{(a[2][1] - a[1][2]) / s, (a[0][2] - a[2][0]) / s, (a[1][0] - a[0][1]) / s, s / 4}
+	case a[0][0] > a[1][1] && a[0][0] > a[2][2]:
+		s := 2 * math.Sqrt(1+a[0][0]-a[1][1]-a[2][2])
+		q = Quaternion{s / 4, (a[0][1] + a[1][0]) / s, (a[0][2] + a[2][0]) / s, (a[2][1] - a[1][2]) / s}
+	case a[1][1] > a[2][2]:
+		s := 2 * math.Sqrt(1+a[1][1]-a[0][0]-a[2][2])
+		q = Quaternion{(a[0][1] + a[1][0]) / s, s / 4, (a[1][2] + a[2][1]) / s, (a[0][2] - a[2][0]) / s}
+	default:
+		s := 2 * math.Sqrt(1+a[2][2]-a[0][0]-a[1][1])
+		q = Quaternion{(a[0][2] + a[2][0]) / s, (a[1][2] + a[2][1]) / s, s / 4, (a[1][0] - a[0][1]) / s}
+	}
+
+	q.Normalize()
+	return q
+}
+
+func (q Quaternion) X() float64 {
+	return q.myX
+}
+
+func (q Quaternion) Y() float64 {
+	return q.myY
+}
+
+func (q Quaternion) Z() float64 {
+	return q.myZ
+}
+
+func (q Quaternion) W() float64 {
+	return q.myW
+}
+
+// Equals compares component-wise using DefaultTolerance
+func (q Quaternion) Equals(other Quaternion) bool {
+	return math.Abs(q.myX-other.myX) < DefaultTolerance &&
+		math.Abs(q.myY-other.myY) < DefaultTolerance &&
+		math.Abs(q.myZ-other.myZ) < DefaultTolerance &&
+		math.Abs(q.myW-other.myW) < DefaultTolerance
+}
+
+// EqualsRotation reports whether both quaternions describe the same rotation, accepting q == -other
+func (q Quaternion) EqualsRotation(other Quaternion) bool {
+	return q.Equals(other) || q.Equals(Quaternion{-other.myX, -other.myY, -other.myZ, -other.myW})
+}
+
+func (q Quaternion) Dot(other Quaternion) float64 {
+	return q.myX*other.myX + q.myY*other.myY + q.myZ*other.myZ + q.myW*other.myW
+}
+
+func (q Quaternion) Length() float64 {
+	return math.Sqrt(q.Dot(q))
+}
+
+func (q *Quaternion) Normalize() {
+	length := q.Length()
+	if length == 0 {
+		return
+	}
+	q.myX /= length
+	q.myY /= length
+	q.myZ /= length
+	q.myW /= length
+}
+
+func (q Quaternion) Normalized() Quaternion {
+	res := q
+	res.Normalize()
+	return res
+}
+
+// Multiply sets q to q * other, the rotation that applies other first and then q
+func (q *Quaternion) Multiply(other Quaternion) {
+	*q = q.Multiplied(other)
+}
+
+// Multiplied returns q * other, the rotation that applies other first and then q
+func (q Quaternion) Multiplied(other Quaternion) Quaternion {
+	return Quaternion{
+		q.myW*other.myX + q.myX*other.myW + q.myY*other.myZ - q.myZ*other.myY,
+		q.myW*other.myY - q.myX*other.myZ + q.myY*other.myW + q.myZ*other.myX,
+		q.myW*other.myZ + q.myX*other.myY - q.myY*other.myX + q.myZ*other.myW,
+		q.myW*other.myW - q.myX*other.myX - q.myY*other.myY - q.myZ*other.myZ,
+	}
+}
+
+// Conjugated returns the conjugate, which is the inverse rotation for unit quaternions
+func (q Quaternion) Conjugated() Quaternion {
+	return Quaternion{-q.myX, -q.myY, -q.myZ, q.myW}
+}
+
+// Inverted returns the multiplicative inverse; a zero quaternion is returned unchanged
+func (q Quaternion) Inverted() Quaternion {
+	lengthSquared := q.Dot(q)
+	if lengthSquared == 0 {
+		return q
+	}
+	c := q.Conjugated()
+	return Quaternion{c.myX / lengthSquared, c.myY / lengthSquared, c.myZ / lengthSquared, c.myW / lengthSquared}
+}
+
+// AxisAngle returns the rotation axis (unit length) and angle in [0, 2π].
+// The identity rotation reports the X axis and a zero angle.
+func (q Quaternion) AxisAngle() (Vector, float64) {
+	n := q.Normalized()
+	angle := 2 * math.Acos(math.Max(-1, math.Min(1, n.myW)))
+	s := math.Sqrt(1 - n.myW*n.myW)
+	if s < DefaultTolerance {
+		return NewVector(1, 0, 0), 0
+	}
+	return NewVector(n.myX/s, n.myY/s, n.myZ/s), angle
+}
+
+// Euler returns the angles accepted by NewQuaternionFromEuler. y is in [-π/2, π/2];
+// at the gimbal lock (y = ±π/2) x is reported as zero.
+func (q Quaternion) Euler() (x, y, z float64) {
+	m := q.Matrix().myValues
+	sinY := math.Max(-1, math.Min(1, -m[2][0]))
+	y = math.Asin(sinY)
+	if math.Abs(sinY) > 1-DefaultTolerance {
+		return 0, y, math.Atan2(-m[0][1], m[1][1])
+	}
+	return math.Atan2(m[2][1], m[2][2]), y, math.Atan2(m[1][0], m[0][0])
+}
+
+// Matrix returns the rotation as a transform matrix; q need not be normalized
+func (q Quaternion) Matrix() Matrix4 {
+	lengthSquared := q.Dot(q)
+	if lengthSquared == 0 {
+		return IdentityMatrix4()
+	}
+	s := 2 / lengthSquared
+	x, y, z, w := q.myX, q.myY, q.myZ, q.myW
+
+	return NewMatrix4([16]float64{
+		1 - s*(y*y+z*z), s * (x*y - z*w), s * (x*z + y*w), 0,
+		s * (x*y + z*w), 1 - s*(x*x+z*z), s * (y*z - x*w), 0,
+		s * (x*z - y*w), s * (y*z + x*w), 1 - s*(x*x+y*y), 0,
+		0, 0, 0, 1,
+	})
+}
+
+// RotateVector rotates a direction by the unit quaternion q
+func (q Quaternion) RotateVector(v Vector) Vector {
+	// v' = v + 2w(u x v) + 2u x (u x v), with u the vector part of q
+	u := NewVector(q.myX, q.myY, q.myZ)
+	t := u.Cross(v)
+	t.Scale(2)
+	res := v.Added(t.Multiplied(q.myW))
+	res.Add(u.Cross(t))
+	return res
+}
+
+// RotateVertex rotates a point around the origin by the unit quaternion q
+func (q Quaternion) RotateVertex(v Vertex) Vertex {
+	rotated := q.RotateVector(NewVectorFromVertex(v))
+	return NewVertex(rotated.X(), rotated.Y(), rotated.Z())
+}
+
+// Slerp interpolates along the shortest arc from q (t = 0) to other (t = 1)
+func (q Quaternion) Slerp(other Quaternion, t float64) Quaternion {
+	from := q.Normalized()
+	to := other.Normalized()
+
+	cosine := from.Dot(to)
+	if cosine < 0 {
+		to = Quaternion{-to.myX, -to.myY, -to.myZ, -to.myW}
+		cosine = -cosine
+	}
+
+	var a, b float64
+	if cosine > 1-1e-6 {
+		// Nearly identical rotations: fall back to normalized linear interpolation
+		a, b = 1-t, t
+	} else {
+		theta := math.Acos(cosine)
+		sine := math.Sin(theta)
+		a = math.Sin((1-t)*theta) / sine
+		b = math.Sin(t*theta) / sine
+	}
+
+	res := Quaternion{
+		a*from.myX + b*to.myX,
+		a*from.myY + b*to.myY,
+		a*from.myZ + b*to.myZ,
+		a*from.myW + b*to.myW,
+	}
+	res.Normalize()
+	return res
+}
@@ -0,0 +1,109 @@
+// Ray.go
+package geom
+
+import "math"
+
+// Ray is a half-line starting at an origin. The direction is stored normalized,
+// so distances along the ray are in world units.
+type Ray struct {
+	myOrigin    Vertex
+	myDirection Vector
+}
+
+// NewRay creates a ray; a zero direction produces a ray that hits nothing
+func NewRay(origin Vertex, direction Vector) Ray {
+	direction.Normalize()
+	return Ray{origin, direction}
+}
+
+// NewRayThrough creates the ray from origin through target
+func NewRayThrough(origin, target Vertex) Ray {
+	return NewRay(origin, NewVectorFromVertices(origin, target))
+}
+
+func (r Ray) Origin() Vertex {
+	return r.myOrigin
+}
+
+func (r Ray) Direction() Vector {
+	return r.myDirection
+}
+
+// At returns the point at the given distance along the ray
+func (r Ray) At(distance float64) Vertex {
+	return NewVertex(
+		r.myOrigin.myCoords.X+r.myDirection.myCoords.X*distance,
+		r.myOrigin.myCoords.Y+r.myDirection.myCoords.Y*distance,
+		r.myOrigin.myCoords.Z+r.myDirection.myCoords.Z*distance,
+	)
+}
+
+// IntersectTriangle tests the ray against the triangle (v0, v1, v2) from both
+// sides with the Möller–Trumbore algorithm. On a hit it returns the distance
+// along the ray and the barycentric coordinates u and v of the hit point,
+// which equals (1-u-v)*v0 + u*v1 + v*v2. Rays parallel to the triangle plane
+// and hits behind the origin are reported as misses.
+func (r Ray) IntersectTriangle(v0, v1, v2 Vertex) (distance, u, v float64, hit bool) {
+	edge1 := NewVectorFromVertices(v0, v1)
+	edge2 := NewVectorFromVertices(v0, v2)
+	p := r.myDirection.Cross(edge2)
+	det := edge1.Dot(p)
+
+	// Scale-aware parallelism test: det is |edge1 x edge2| times the cosine to the normal
+	if math.Abs(det) <= DefaultTolerance*edge1.Length()*edge2.Length() {
+		return 0, 0, 0, false
+	}
+	inverse := 1 / det
+
+	s := NewVectorFromVertices(v0, r.myOrigin)
+	u = s.Dot(p) * inverse
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+
+	q := s.Cross(edge1)
+	v = r.myDirection.Dot(q) * inverse
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+
+	distance = edge2.Dot(q) * inverse
+	if distance < 0 {
+		return 0, 0, 0, false
+	}
+	return distance, u, v, true
+}
+
+// IntersectAABB returns the distances at which the ray enters and leaves the
+// box (slab test). The entry distance is 0 when the origin lies inside.
+func (r Ray) IntersectAABB(box AABB) (enter, exit float64, hit bool) {
+	if box.IsEmpty() {
+		return 0, 0, false
+	}
+
+	enter, exit = 0, math.Inf(1)
+	origin := [3]float64{r.myOrigin.myCoords.X, r.myOrigin.myCoords.Y, r.myOrigin.myCoords.Z}
+	direction := [3]float64{r.myDirection.myCoords.X, r.myDirection.myCoords.Y, r.myDirection.myCoords.Z}
+	lower := [3]float64{box.myMin.myCoords.X, box.myMin.myCoords.Y, box.myMin.myCoords.Z}
+	upper := [3]float64{box.myMax.myCoords.X, box.myMax.myCoords.Y, box.myMax.myCoords.Z}
+
+	for axis := 0; axis < 3; axis++ {
+		if direction[axis] == 0 {
+			if origin[axis] < lower[axis] || origin[axis] > upper[axis] {
+				return 0, 0, false
+			}
+			continue
+		}
+		t1 := (lower[axis] - origin[axis]) / direction[axis]
+		t2 := (upper[axis] - origin[axis]) / direction[axis]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		enter = math.Max(enter, t1)
+		exit = math.Min(exit, t2)
+		if enter > exit {
+			return 0, 0, false
+		}
+	}
+	return enter, exit, true
+}
@@ -0,0 +1,48 @@
+// Sphere.go
+package geom
+
+import "math"
+
+// Sphere is a ball given by its center and radius. A negative radius marks the empty sphere.
+type Sphere struct {
+	myCenter Vertex
+	myRadius float64
+}
+
+func NewSphere(center Vertex, radius float64) Sphere {
+	return Sphere{center, radius}
+}
+
+// EmptySphere returns a sphere that contains nothing
+func EmptySphere() Sphere {
+	return Sphere{myRadius: -1}
+}
+
+func (s Sphere) Center() Vertex {
+	return s.myCenter
+}
+
+func (s Sphere) Radius() float64 {
+	return s.myRadius
+}
+
+func (s Sphere) IsEmpty() bool {
+	return s.myRadius < 0
+}
+
+// Contains reports whether v lies inside the sphere, allowing DefaultTolerance relative to the radius
+func (s Sphere) Contains(v Vertex) bool {
+	if s.IsEmpty() {
+		return false
+	}
+	return s.myCenter.Distance(v) <= s.myRadius+DefaultTolerance*math.Max(1, s.myRadius)
+}
+
+// BoundingBox returns the box enclosing the sphere
+func (s Sphere) BoundingBox() AABB {
+	if s.IsEmpty() {
+		return EmptyAABB()
+	}
+	c, r := s.myCenter.myCoords, s.myRadius
+	return AABB{NewVertex(c.X-r, c.Y-r, c.Z-r), NewVertex(c.X+r, c.Y+r, c.Z+r)}
+}
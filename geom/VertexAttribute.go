@@ -0,0 +1,101 @@
+// VertexAttribute.go
+package geom
+
+import "fmt"
+
+// ScalarType records how an attribute value was stored in its source file
+type ScalarType int
+
+const (
+	ScalarFloat64 ScalarType = iota
+	ScalarFloat32
+	ScalarInt8
+	ScalarUint8
+	ScalarInt16
+	ScalarUint16
+	ScalarInt32
+	ScalarUint32
+)
+
+// String returns the string representation of the scalar type
+func (st ScalarType) String() string {
+	switch st {
+	case ScalarFloat64:
+		return "float64"
+	case ScalarFloat32:
+		return "float32"
+	case ScalarInt8:
+		return "int8"
+	case ScalarUint8:
+		return "uint8"
+	case ScalarInt16:
+		return "int16"
+	case ScalarUint16:
+		return "uint16"
+	case ScalarInt32:
+		return "int32"
+	case ScalarUint32:
+		return "uint32"
+	default:
+		return "unknown"
+	}
+}
+
+// VertexAttribute is a named per-vertex scalar array that the mesh carries
+// without interpreting, e.g. scanner confidence values
+type VertexAttribute struct {
+	Name   string
+	Type   ScalarType
+	Values []float64 // one value per vertex; every ScalarType fits a float64 exactly
+}
+
+// SetVertexAttribute adds or replaces a named attribute; values must hold one entry per vertex
+func (m *Mesh) SetVertexAttribute(name string, scalarType ScalarType, values []float64) error {
+	if len(values) != len(m.myVertices) {
+		return fmt.Errorf("attribute %q has %d values (mesh has %d vertices)", name, len(values), len(m.myVertices))
+	}
+
+	attribute := VertexAttribute{
+		Name:   name,
+		Type:   scalarType,
+		Values: append([]float64(nil), values...),
+	}
+	for i := range m.myAttributes {
+		if m.myAttributes[i].Name == name {
+			m.myAttributes[i] = attribute
+			return nil
+		}
+	}
+	m.myAttributes = append(m.myAttributes, attribute)
+	return nil
+}
+
+// VertexAttribute returns a copy of the named attribute
+func (m *Mesh) VertexAttribute(name string) (VertexAttribute, bool) {
+	for _, attribute := range m.myAttributes {
+		if attribute.Name == name {
+			attribute.Values = append([]float64(nil), attribute.Values...)
+			return attribute, true
+		}
+	}
+	return VertexAttribute{}, false
+}
+
+// VertexAttributeNames returns attribute names in insertion order
+func (m *Mesh) VertexAttributeNames() []string {
+	names := make([]string, len(m.myAttributes))
+	for i, attribute := range m.myAttributes {
+		names[i] = attribute.Name
+	}
+	return names
+}
+
+// RemoveVertexAttribute drops the named attribute if present
+func (m *Mesh) RemoveVertexAttribute(name string) {
+	for i, attribute := range m.myAttributes {
+		if attribute.Name == name {
+			m.myAttributes = append(m.myAttributes[:i], m.myAttributes[i+1:]...)
+			return
+		}
+	}
+}
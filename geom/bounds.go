@@ -0,0 +1,152 @@
+// bounds.go
+package geom
+
+import (
+	"math"
+	"math/rand"
+)
+
+// BoundingBox returns the axis-aligned box of all mesh vertices
+func (m *Mesh) BoundingBox() AABB {
+	box := EmptyAABB()
+	for _, v := range m.myVertices {
+		box.Extend(v)
+	}
+	return box
+}
+
+// BoundingSphere returns the smallest sphere enclosing all mesh vertices
+func (m *Mesh) BoundingSphere() Sphere {
+	return MinimalBoundingSphere(m.myVertices)
+}
+
+// MinimalBoundingSphere returns the smallest sphere enclosing the points using
+// Welzl's algorithm in its iterative move-to-front form. The points are
+// shuffled with a fixed seed, so the expected running time is linear and the
+// result is deterministic.
+func MinimalBoundingSphere(points []Vertex) Sphere {
+	shuffled := make([]Vertex, len(points))
+	copy(shuffled, points)
+	random := rand.New(rand.NewSource(1))
+	random.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	sphere := EmptySphere()
+	for i, p := range shuffled {
+		if sphere.Contains(p) {
+			continue
+		}
+		sphere = NewSphere(p, 0)
+		for j, q := range shuffled[:i] {
+			if sphere.Contains(q) {
+				continue
+			}
+			sphere = sphereFromTwo(p, q)
+			for k, r := range shuffled[:j] {
+				if sphere.Contains(r) {
+					continue
+				}
+				sphere = sphereFromThree(p, q, r)
+				for _, s := range shuffled[:k] {
+					if !sphere.Contains(s) {
+						sphere = sphereFromFour(p, q, r, s)
+					}
+				}
+			}
+		}
+	}
+	return sphere
+}
+
+func sphereFromTwo(a, b Vertex) Sphere {
+	center := NewVertex(
+		(a.myCoords.X+b.myCoords.X)/2,
+		(a.myCoords.Y+b.myCoords.Y)/2,
+		(a.myCoords.Z+b.myCoords.Z)/2,
+	)
+	return NewSphere(center, a.Distance(b)/2)
+}
+
+// sphereFromThree returns the smallest sphere with all three points on its boundary,
+// or the sphere over the farthest pair when the points are collinear
+func sphereFromThree(a, b, c Vertex) Sphere {
+	u := NewVectorFromVertices(a, b)
+	v := NewVectorFromVertices(a, c)
+	w := u.Cross(v)
+
+	if w.Length() <= 1e-12*u.Length()*v.Length() {
+		return largestSphere(sphereFromTwo(a, b), sphereFromTwo(a, c), sphereFromTwo(b, c))
+	}
+
+	// Circumcenter relative to a: (|u|² (v × w) + |v|² (w × u)) / (2 |w|²)
+	offset := v.Cross(w)
+	offset.Scale(u.Dot(u))
+	second := w.Cross(u)
+	second.Scale(v.Dot(v))
+	offset.Add(second)
+	offset.Scale(1 / (2 * w.Dot(w)))
+
+	return NewSphere(offsetVertex(a, offset), offset.Length())
+}
+
+// sphereFromFour returns the sphere through all four points, or the smallest
+// sphere over a subset that still encloses all of them when they are coplanar
+func sphereFromFour(a, b, c, d Vertex) Sphere {
+	u := NewVectorFromVertices(a, b)
+	v := NewVectorFromVertices(a, c)
+	t := NewVectorFromVertices(a, d)
+	det := u.Dot(v.Cross(t))
+
+	if math.Abs(det) <= 1e-12*u.Length()*v.Length()*t.Length() {
+		points := []Vertex{a, b, c, d}
+		best := EmptySphere()
+		candidates := []Sphere{
+			sphereFromThree(a, b, c), sphereFromThree(a, b, d), sphereFromThree(a, c, d), sphereFromThree(b, c, d),
+			sphereFromTwo(a, b), sphereFromTwo(a, c), sphereFromTwo(a, d),
+			sphereFromTwo(b, c), sphereFromTwo(b, d), sphereFromTwo(c, d),
+		}
+		for _, candidate := range candidates {
+			if (best.IsEmpty() || candidate.myRadius < best.myRadius) && containsAll(candidate, points) {
+				best = candidate
+			}
+		}
+		return best
+	}
+
+	// Solve 2 (p - a) · x = |p - a|² for p in {b, c, d}
+	offset := v.Cross(t)
+	offset.Scale(u.Dot(u))
+	second := t.Cross(u)
+	second.Scale(v.Dot(v))
+	third := u.Cross(v)
+	third.Scale(t.Dot(t))
+	offset.Add(second)
+	offset.Add(third)
+	offset.Scale(1 / (2 * det))
+
+	return NewSphere(offsetVertex(a, offset), offset.Length())
+}
+
+func largestSphere(spheres ...Sphere) Sphere {
+	best := EmptySphere()
+	for _, s := range spheres {
+		if s.myRadius > best.myRadius {
+			best = s
+		}
+	}
+	return best
+}
+
+func containsAll(s Sphere, points []Vertex) bool {
+	for _, p := range points {
+		if !s.Contains(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func offsetVertex(v Vertex, offset Vector) Vertex {
+	return NewVertex(v.myCoords.X+offset.X(), v.myCoords.Y+offset.Y(), v.myCoords.Z+offset.Z())
+}
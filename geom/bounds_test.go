@@ -0,0 +1,149 @@
+package geom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMesh_BoundingBox(t *testing.T) {
+	cube := CreateCube(4)
+	cube.Translate(NewVector(10, 0, -1))
+
+	box := cube.BoundingBox()
+	expected := NewAABB(NewVertex(8, -2, -3), NewVertex(12, 2, 1))
+	if !box.Equals(expected) {
+		t.Errorf("Expected %v, got %v", expected, box)
+	}
+	assertVectorNear(t, "center", NewVector(10, 0, -1), NewVectorFromVertex(box.Center()))
+	if !box.Contains(NewVertex(12, 2, 1)) || box.Contains(NewVertex(12.1, 0, 0)) {
+		t.Error("Expected boundary points inside and outside points outside")
+	}
+	if !(&Mesh{}).BoundingBox().IsEmpty() {
+		t.Error("Expected empty mesh to have an empty box")
+	}
+}
+
+func TestMesh_BoundingSphere(t *testing.T) {
+	sphere := CreateCube(2).BoundingSphere()
+	assertVectorNear(t, "cube sphere center", NewVector(0, 0, 0), NewVectorFromVertex(sphere.Center()))
+	if math.Abs(sphere.Radius()-math.Sqrt(3)) > 1e-9 {
+		t.Errorf("Expected radius √3, got %f", sphere.Radius())
+	}
+
+	// An obtuse triangle is bounded by the sphere over its longest side
+	triangle := MinimalBoundingSphere([]Vertex{NewVertex(-1, 0, 0), NewVertex(1, 0, 0), NewVertex(0, 0.1, 0)})
+	if math.Abs(triangle.Radius()-1) > 1e-9 {
+		t.Errorf("Expected radius 1 for obtuse triangle, got %f", triangle.Radius())
+	}
+}
+
+func TestMinimalBoundingSphere_RandomPoints(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	points := make([]Vertex, 500)
+	for i := range points {
+		points[i] = NewVertex(random.NormFloat64(), random.NormFloat64()*2, random.Float64())
+	}
+
+	sphere := MinimalBoundingSphere(points)
+	center := sphere.Center()
+	onBoundary := 0
+	for _, p := range points {
+		if !sphere.Contains(p) {
+			t.Fatalf("Point %v lies outside the sphere %v", p, sphere)
+		}
+		if math.Abs(center.Distance(p)-sphere.Radius()) < 1e-9 {
+			onBoundary++
+		}
+	}
+	// A minimal sphere touches at least two points
+	if onBoundary < 2 {
+		t.Errorf("Expected at least 2 points on the boundary, got %d", onBoundary)
+	}
+	if MinimalBoundingSphere(points) != sphere {
+		t.Error("Expected deterministic result")
+	}
+}
+
+func TestMesh_MassPropertiesOfCube(t *testing.T) {
+	cube := CreateCube(2)
+	cube.Translate(NewVector(1, 2, 3))
+
+	properties, err := cube.MassProperties()
+	if err != nil {
+		t.Fatalf("MassProperties failed: %v", err)
+	}
+	if math.Abs(properties.Volume-8) > 1e-9 {
+		t.Errorf("Expected volume 8, got %f", properties.Volume)
+	}
+	if math.Abs(properties.SurfaceArea-24) > 1e-9 {
+		t.Errorf("Expected area 24, got %f", properties.SurfaceArea)
+	}
+	assertVectorNear(t, "centroid", NewVector(1, 2, 3), NewVectorFromVertex(properties.Centroid))
+
+	// Solid cube: I = m (a² + a²) / 12 on the diagonal, no products of inertia
+	expected := 8.0 * (4 + 4) / 12
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			want := 0.0
+			if i == j {
+				want = expected
+			}
+			if math.Abs(properties.Inertia[i][j]-want) > 1e-9 {
+				t.Errorf("Inertia[%d][%d]: expected %f, got %f", i, j, want, properties.Inertia[i][j])
+			}
+		}
+	}
+}
+
+func TestMesh_SignedVolumeOfTetrahedron(t *testing.T) {
+	tetra := CreateTetrahedron(2)
+	if math.Abs(tetra.SignedVolume()-2.0/3) > 1e-9 {
+		t.Errorf("Expected volume 2/3, got %f", tetra.SignedVolume())
+	}
+
+	tetra.Scale(-1, 1, 1)
+	if math.Abs(tetra.SignedVolume()-2.0/3) > 1e-9 {
+		t.Errorf("Expected mirrored tetrahedron to keep a positive volume, got %f", tetra.SignedVolume())
+	}
+}
+
+func TestMesh_VolumeFarFromOrigin(t *testing.T) {
+	sphere := CreateUVSphere(1, 32, 16)
+	volume := sphere.SignedVolume()
+	sphere.Translate(NewVector(1e6, 1e6, 1e6))
+	if math.Abs(sphere.SignedVolume()-volume) > 1e-6 {
+		t.Errorf("Expected volume %v far from the origin, got %v", volume, sphere.SignedVolume())
+	}
+	properties, err := sphere.MassProperties()
+	if err != nil {
+		t.Fatalf("MassProperties failed: %v", err)
+	}
+	if math.Abs(properties.Volume-volume) > 1e-6 {
+		t.Errorf("Expected mass volume %v far from the origin, got %v", volume, properties.Volume)
+	}
+	assertVectorNear(t, "far centroid", NewVector(1e6, 1e6, 1e6), NewVectorFromVertex(properties.Centroid))
+
+	// A 0.5 mm cube in metres
+	tiny := CreateCube(5e-4)
+	properties, err = tiny.MassProperties()
+	if err != nil {
+		t.Fatalf("MassProperties of a small cube failed: %v", err)
+	}
+	if math.Abs(properties.Volume-1.25e-10) > 1e-20 {
+		t.Errorf("Expected volume 1.25e-10, got %v", properties.Volume)
+	}
+}
+
+func TestMesh_CentroidOfOpenMesh(t *testing.T) {
+	mesh := &Mesh{}
+	v1 := mesh.AddVertex(NewVertex(0, 0, 5))
+	v2 := mesh.AddVertex(NewVertex(3, 0, 5))
+	v3 := mesh.AddVertex(NewVertex(0, 3, 5))
+	_, _ = mesh.AddFace(v1, v2, v3)
+
+	if _, err := mesh.MassProperties(); err == nil {
+		t.Error("Expected error for a mesh without volume, got nil")
+	}
+	assertVectorNear(t, "surface centroid", NewVector(1, 1, 5), NewVectorFromVertex(mesh.Centroid()))
+}
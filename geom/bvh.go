@@ -0,0 +1,400 @@
+// bvh.go
+package geom
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// BVHConfig holds configuration for building a bounding volume hierarchy
+type BVHConfig struct {
+	MaxLeafFaces      int // Faces at or below this count always form a leaf
+	Buckets           int // Number of SAH bins evaluated per axis
+	ParallelThreshold int // Subtrees with at least this many faces are built in their own goroutine
+}
+
+// DefaultBVHConfig returns a default BVH configuration
+func DefaultBVHConfig() BVHConfig {
+	return BVHConfig{
+		MaxLeafFaces:      4,
+		Buckets:           16,
+		ParallelThreshold: 4096,
+	}
+}
+
+// BVH is a bounding volume hierarchy over the faces of a mesh. Nodes are
+// stored flattened in depth-first order: an interior node's first child
+// directly follows it. The BVH references the mesh and must be rebuilt
+// after the mesh geometry changes.
+type BVH struct {
+	myMesh  *Mesh
+	myNodes []bvhNode
+	myFaces []int
+}
+
+type bvhNode struct {
+	bounds AABB
+	offset int // Leaf: first entry in myFaces; interior: index of the second child
+	count  int // Faces in a leaf, 0 for interior nodes
+	axis   int // Split axis of an interior node
+}
+
+// ClosestPoint is the result of a closest-point query
+type ClosestPoint struct {
+	Face        int
+	Point       Vertex
+	Distance    float64
+	Barycentric [3]float64
+}
+
+// NewBVH builds a hierarchy over all faces of the mesh using the surface area heuristic
+func NewBVH(mesh *Mesh, config BVHConfig) (*BVH, error) {
+	if config.MaxLeafFaces < 1 {
+		return nil, fmt.Errorf("max leaf faces must be positive, got %d", config.MaxLeafFaces)
+	}
+	if config.Buckets < 2 {
+		return nil, fmt.Errorf("SAH buckets must be at least 2, got %d", config.Buckets)
+	}
+	if config.ParallelThreshold < 1 {
+		return nil, fmt.Errorf("parallel threshold must be positive, got %d", config.ParallelThreshold)
+	}
+
+	builder := &bvhBuilder{
+		config:    config,
+		bounds:    make([]AABB, len(mesh.myFaces)),
+		centroids: make([]Vertex, len(mesh.myFaces)),
+	}
+	order := make([]int, len(mesh.myFaces))
+	for f, face := range mesh.myFaces {
+		v1, v2, v3 := mesh.faceVertices(face)
+		box := NewAABB(v1, v2)
+		box.Extend(v3)
+		builder.bounds[f] = box
+		builder.centroids[f] = box.Center()
+		order[f] = f
+	}
+
+	bvh := &BVH{myMesh: mesh, myFaces: order}
+	if len(order) > 0 {
+		root := builder.build(order, 0)
+		bvh.myNodes = make([]bvhNode, 0, root.size)
+		bvh.flatten(root)
+	}
+	return bvh, nil
+}
+
+// NewBVHWithDefaults builds a hierarchy with default settings
+func NewBVHWithDefaults(mesh *Mesh) *BVH {
+	bvh, _ := NewBVH(mesh, DefaultBVHConfig())
+	return bvh
+}
+
+// NodeNumber returns the number of nodes in the hierarchy
+func (b *BVH) NodeNumber() int {
+	return len(b.myNodes)
+}
+
+// Bounds returns the box enclosing the whole mesh
+func (b *BVH) Bounds() AABB {
+	if len(b.myNodes) == 0 {
+		return EmptyAABB()
+	}
+	return b.myNodes[0].bounds
+}
+
+type bvhBuildNode struct {
+	bounds      AABB
+	left, right *bvhBuildNode
+	start       int // Position of the leaf faces in the order slice
+	count       int
+	axis        int
+	size        int // Nodes in this subtree
+}
+
+type bvhBuilder struct {
+	config    BVHConfig
+	bounds    []AABB
+	centroids []Vertex
+}
+
+// build creates the subtree over faces, a window of the order slice starting at start
+func (b *bvhBuilder) build(faces []int, start int) *bvhBuildNode {
+	node := &bvhBuildNode{bounds: EmptyAABB(), start: start, count: len(faces), size: 1}
+	centroidBounds := EmptyAABB()
+	for _, f := range faces {
+		node.bounds.Union(b.bounds[f])
+		centroidBounds.Extend(b.centroids[f])
+	}
+	if len(faces) <= b.config.MaxLeafFaces {
+		return node
+	}
+
+	mid, axis := b.split(faces, node.bounds, centroidBounds)
+	node.axis = axis
+	node.count = 0
+
+	if len(faces) >= b.config.ParallelThreshold {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			node.left = b.build(faces[:mid], start)
+		}()
+		node.right = b.build(faces[mid:], start+mid)
+		wg.Wait()
+	} else {
+		node.left = b.build(faces[:mid], start)
+		node.right = b.build(faces[mid:], start+mid)
+	}
+
+	node.size += node.left.size + node.right.size
+	return node
+}
+
+// split partitions faces in place with the cheapest binned SAH split and
+// returns the partition point and axis. Faces whose centroids cannot be
+// separated are split in half.
+func (b *bvhBuilder) split(faces []int, bounds, centroidBounds AABB) (int, int) {
+	type bucket struct {
+		count  int
+		bounds AABB
+	}
+
+	// Small nodes do not need more bins than faces
+	buckets := min(b.config.Buckets, len(faces))
+	lower := [3]float64{centroidBounds.myMin.myCoords.X, centroidBounds.myMin.myCoords.Y, centroidBounds.myMin.myCoords.Z}
+	size := centroidBounds.Size()
+	extent := [3]float64{size.X(), size.Y(), size.Z()}
+	bucketOf := func(f, axis int) int {
+		c := b.centroids[f].myCoords
+		value := [3]float64{c.X, c.Y, c.Z}[axis]
+		index := int(float64(buckets) * (value - lower[axis]) / extent[axis])
+		return min(max(index, 0), buckets-1)
+	}
+
+	bestCost, bestAxis, bestSplit := math.Inf(1), -1, 0
+	parentArea := bounds.SurfaceArea()
+	for axis := 0; axis < 3; axis++ {
+		if extent[axis] <= 0 {
+			continue
+		}
+
+		bins := make([]bucket, buckets)
+		for i := range bins {
+			bins[i].bounds = EmptyAABB()
+		}
+		for _, f := range faces {
+			bin := &bins[bucketOf(f, axis)]
+			bin.count++
+			bin.bounds.Union(b.bounds[f])
+		}
+
+		// Sweep from the right to know the cost of every right-hand side
+		rightArea := make([]float64, buckets)
+		rightCount := make([]int, buckets)
+		box, count := EmptyAABB(), 0
+		for i := buckets - 1; i > 0; i-- {
+			box.Union(bins[i].bounds)
+			count += bins[i].count
+			rightArea[i], rightCount[i] = box.SurfaceArea(), count
+		}
+
+		box, count = EmptyAABB(), 0
+		for i := 0; i < buckets-1; i++ {
+			box.Union(bins[i].bounds)
+			count += bins[i].count
+			if count == 0 || rightCount[i+1] == 0 {
+				continue
+			}
+			cost := 1 + (box.SurfaceArea()*float64(count)+rightArea[i+1]*float64(rightCount[i+1]))/parentArea
+			if cost < bestCost {
+				bestCost, bestAxis, bestSplit = cost, axis, i
+			}
+		}
+	}
+
+	if bestAxis < 0 {
+		return len(faces) / 2, 0
+	}
+
+	mid := 0
+	for i, f := range faces {
+		if bucketOf(f, bestAxis) <= bestSplit {
+			faces[i], faces[mid] = faces[mid], faces[i]
+			mid++
+		}
+	}
+	return mid, bestAxis
+}
+
+func (b *BVH) flatten(node *bvhBuildNode) int {
+	index := len(b.myNodes)
+	b.myNodes = append(b.myNodes, bvhNode{bounds: node.bounds, offset: node.start, count: node.count, axis: node.axis})
+	if node.left != nil {
+		b.flatten(node.left)
+		b.myNodes[index].offset = b.flatten(node.right)
+	}
+	return index
+}
+
+// Intersect returns the nearest hit of the ray, with the same tie-breaking as Mesh.Intersect
+func (b *BVH) Intersect(ray Ray) (Hit, bool) {
+	var nearest Hit
+	found := false
+	b.traverseRay(ray, func(f int) bool {
+		hit, ok := b.myMesh.intersectFace(ray, f)
+		if ok && (!found || hit.Distance < nearest.Distance || (hit.Distance == nearest.Distance && f < nearest.Face)) {
+			nearest = hit
+			found = true
+		}
+		return false
+	}, func() float64 {
+		if found {
+			return nearest.Distance
+		}
+		return math.Inf(1)
+	})
+	return nearest, found
+}
+
+// IntersectAny returns some hit closer than maxDistance and stops at the first
+// one found; useful for shadow and visibility rays
+func (b *BVH) IntersectAny(ray Ray, maxDistance float64) (Hit, bool) {
+	var found Hit
+	ok := false
+	b.traverseRay(ray, func(f int) bool {
+		hit, hitOK := b.myMesh.intersectFace(ray, f)
+		if hitOK && hit.Distance <= maxDistance {
+			found, ok = hit, true
+			return true
+		}
+		return false
+	}, func() float64 {
+		return maxDistance
+	})
+	return found, ok
+}
+
+// traverseRay visits leaf faces front to back; visit returns true to stop, and
+// limit bounds the distance beyond which nodes are skipped
+func (b *BVH) traverseRay(ray Ray, visit func(f int) bool, limit func() float64) {
+	if len(b.myNodes) == 0 {
+		return
+	}
+
+	direction := [3]float64{ray.myDirection.myCoords.X, ray.myDirection.myCoords.Y, ray.myDirection.myCoords.Z}
+	stack := make([]int, 1, 64)
+	stack[0] = 0
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &b.myNodes[index]
+
+		enter, _, hit := ray.IntersectAABB(node.bounds)
+		if !hit || enter > limit() {
+			continue
+		}
+
+		if node.count > 0 {
+			for _, f := range b.myFaces[node.offset : node.offset+node.count] {
+				if visit(f) {
+					return
+				}
+			}
+			continue
+		}
+
+		// Push the far child first so the near one is visited next
+		near, far := index+1, node.offset
+		if direction[node.axis] < 0 {
+			near, far = far, near
+		}
+		stack = append(stack, far, near)
+	}
+}
+
+// Overlapping returns the faces intersecting the box in ascending order
+func (b *BVH) Overlapping(box AABB) []int {
+	var faces []int
+	if len(b.myNodes) == 0 {
+		return faces
+	}
+
+	stack := []int{0}
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &b.myNodes[index]
+		if !node.bounds.Overlaps(box) {
+			continue
+		}
+
+		if node.count > 0 {
+			for _, f := range b.myFaces[node.offset : node.offset+node.count] {
+				v1, v2, v3 := b.myMesh.faceVertices(b.myMesh.myFaces[f])
+				if TriangleOverlapsAABB(v1, v2, v3, box) {
+					faces = append(faces, f)
+				}
+			}
+			continue
+		}
+		stack = append(stack, node.offset, index+1)
+	}
+
+	sort.Ints(faces)
+	return faces
+}
+
+// ClosestPoint returns the point on the mesh surface nearest to p; ties go to the lower face index
+func (b *BVH) ClosestPoint(p Vertex) (ClosestPoint, bool) {
+	var best ClosestPoint
+	found := false
+	if len(b.myNodes) == 0 {
+		return best, false
+	}
+
+	bestSquared := math.Inf(1)
+	stack := []int{0}
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &b.myNodes[index]
+		if squaredDistanceToAABB(p, node.bounds) > bestSquared {
+			continue
+		}
+
+		if node.count > 0 {
+			for _, f := range b.myFaces[node.offset : node.offset+node.count] {
+				v1, v2, v3 := b.myMesh.faceVertices(b.myMesh.myFaces[f])
+				point, weights := ClosestPointOnTriangle(p, v1, v2, v3)
+				offset := point.myCoords.Subtracted(p.myCoords)
+				squared := offset.X*offset.X + offset.Y*offset.Y + offset.Z*offset.Z
+				if squared < bestSquared || (squared == bestSquared && f < best.Face) {
+					bestSquared = squared
+					best = ClosestPoint{Face: f, Point: point, Barycentric: weights}
+					found = true
+				}
+			}
+			continue
+		}
+
+		// Visit the nearer child first
+		first, second := index+1, node.offset
+		if squaredDistanceToAABB(p, b.myNodes[first].bounds) > squaredDistanceToAABB(p, b.myNodes[second].bounds) {
+			first, second = second, first
+		}
+		stack = append(stack, second, first)
+	}
+
+	best.Distance = math.Sqrt(bestSquared)
+	return best, found
+}
+
+func squaredDistanceToAABB(p Vertex, box AABB) float64 {
+	dx := math.Max(0, math.Max(box.myMin.myCoords.X-p.myCoords.X, p.myCoords.X-box.myMax.myCoords.X))
+	dy := math.Max(0, math.Max(box.myMin.myCoords.Y-p.myCoords.Y, p.myCoords.Y-box.myMax.myCoords.Y))
+	dz := math.Max(0, math.Max(box.myMin.myCoords.Z-p.myCoords.Z, p.myCoords.Z-box.myMax.myCoords.Z))
+	return dx*dx + dy*dy + dz*dz
+}
@@ -0,0 +1,252 @@
+package geom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// createWavyGrid builds a height field of 2*n*n triangles over [-1, 1]²
+func createWavyGrid(n int) *Mesh {
+	mesh := &Mesh{}
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= n; j++ {
+			x := -1 + 2*float64(i)/float64(n)
+			y := -1 + 2*float64(j)/float64(n)
+			mesh.AddVertex(NewVertex(x, y, 0.2*math.Sin(5*x)*math.Cos(4*y)))
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a := i*(n+1) + j
+			b := a + n + 1
+			_, _ = mesh.AddFace(a, b, a+1)
+			_, _ = mesh.AddFace(b, b+1, a+1)
+		}
+	}
+	return mesh
+}
+
+// createTriangleSoup builds overlapping random triangles, a hard case for any hierarchy
+func createTriangleSoup(count int, random *rand.Rand) *Mesh {
+	mesh := &Mesh{}
+	for i := 0; i < count; i++ {
+		center := NewVertex(random.Float64()*4-2, random.Float64()*4-2, random.Float64()*4-2)
+		var indices [3]int
+		for k := range indices {
+			indices[k] = mesh.AddVertex(NewVertex(
+				center.X()+random.Float64()*0.6-0.3,
+				center.Y()+random.Float64()*0.6-0.3,
+				center.Z()+random.Float64()*0.6-0.3,
+			))
+		}
+		_, _ = mesh.AddFace(indices[0], indices[1], indices[2])
+	}
+	return mesh
+}
+
+func randomRay(random *rand.Rand) Ray {
+	origin := NewVertex(random.Float64()*6-3, random.Float64()*6-3, random.Float64()*6-3)
+	target := NewVertex(random.Float64()*2-1, random.Float64()*2-1, random.Float64()*0.4-0.2)
+	return NewRayThrough(origin, target)
+}
+
+func TestBVH_MatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	meshes := map[string]*Mesh{
+		"grid": createWavyGrid(40),
+		"soup": createTriangleSoup(2000, random),
+	}
+
+	for name, mesh := range meshes {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultBVHConfig()
+			config.ParallelThreshold = 256
+			bvh, err := NewBVH(mesh, config)
+			if err != nil {
+				t.Fatalf("NewBVH failed: %v", err)
+			}
+
+			for i := 0; i < 300; i++ {
+				ray := randomRay(random)
+				want, wantOK := mesh.Intersect(ray)
+				got, gotOK := bvh.Intersect(ray)
+				if wantOK != gotOK || (wantOK && (want.Face != got.Face || want.Distance != got.Distance)) {
+					t.Fatalf("Ray %d: brute force gave %v %+v, BVH gave %v %+v", i, wantOK, want, gotOK, got)
+				}
+
+				anyHit, anyOK := bvh.IntersectAny(ray, math.Inf(1))
+				if anyOK != wantOK || (anyOK && anyHit.Distance < want.Distance) {
+					t.Fatalf("Ray %d: any-hit disagrees with nearest hit", i)
+				}
+			}
+		})
+	}
+}
+
+func TestBVH_IntersectAnyRespectsMaxDistance(t *testing.T) {
+	cube := CreateCube(2)
+	bvh := NewBVHWithDefaults(cube)
+	ray := NewRay(NewVertex(0, 0.3, 10), NewVector(0, 0, -1))
+
+	if _, ok := bvh.IntersectAny(ray, 8); ok {
+		t.Error("Expected no hit before distance 8")
+	}
+	if hit, ok := bvh.IntersectAny(ray, 9.5); !ok || hit.Distance > 9.5 {
+		t.Errorf("Expected hit within 9.5, got %+v, %v", hit, ok)
+	}
+}
+
+func TestBVH_Overlapping(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	mesh := createTriangleSoup(1500, random)
+	bvh := NewBVHWithDefaults(mesh)
+
+	for i := 0; i < 50; i++ {
+		corner := NewVertex(random.Float64()*4-2, random.Float64()*4-2, random.Float64()*4-2)
+		box := NewAABB(corner, NewVertex(corner.X()+0.5, corner.Y()+0.7, corner.Z()+0.3))
+
+		var expected []int
+		for f := 0; f < mesh.FaceNumber(); f++ {
+			v1, _ := mesh.VertexInFace(f, 0)
+			v2, _ := mesh.VertexInFace(f, 1)
+			v3, _ := mesh.VertexInFace(f, 2)
+			if TriangleOverlapsAABB(v1, v2, v3, box) {
+				expected = append(expected, f)
+			}
+		}
+
+		got := bvh.Overlapping(box)
+		if len(got) != len(expected) {
+			t.Fatalf("Box %d: expected %d faces, got %d", i, len(expected), len(got))
+		}
+		for k := range got {
+			if got[k] != expected[k] {
+				t.Fatalf("Box %d: expected faces %v, got %v", i, expected, got)
+			}
+		}
+	}
+}
+
+func TestTriangleOverlapsAABB(t *testing.T) {
+	box := NewAABB(NewVertex(0, 0, 0), NewVertex(1, 1, 1))
+	cases := []struct {
+		name     string
+		a, b, c  Vertex
+		expected bool
+	}{
+		{"inside", NewVertex(0.2, 0.2, 0.5), NewVertex(0.8, 0.2, 0.5), NewVertex(0.5, 0.8, 0.5), true},
+		{"crossing", NewVertex(-1, 0.5, 0.5), NewVertex(2, 0.5, 0.5), NewVertex(0.5, 0.5, 3), true},
+		{"bounds overlap only", NewVertex(0.9, 2, 0.5), NewVertex(2, 0.9, 0.5), NewVertex(2, 2, 0.5), false},
+		{"plane misses", NewVertex(-1, -1, 2.5), NewVertex(2, -1, 2.5), NewVertex(-1, 2, 2.5), false},
+	}
+	for _, test := range cases {
+		if got := TriangleOverlapsAABB(test.a, test.b, test.c, box); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestBVH_ClosestPoint(t *testing.T) {
+	random := rand.New(rand.NewSource(11))
+	mesh := createWavyGrid(30)
+	bvh := NewBVHWithDefaults(mesh)
+
+	for i := 0; i < 100; i++ {
+		p := NewVertex(random.Float64()*3-1.5, random.Float64()*3-1.5, random.Float64()*2-1)
+
+		bestDistance := math.Inf(1)
+		for f := 0; f < mesh.FaceNumber(); f++ {
+			v1, _ := mesh.VertexInFace(f, 0)
+			v2, _ := mesh.VertexInFace(f, 1)
+			v3, _ := mesh.VertexInFace(f, 2)
+			point, _ := ClosestPointOnTriangle(p, v1, v2, v3)
+			bestDistance = math.Min(bestDistance, point.Distance(p))
+		}
+
+		got, ok := bvh.ClosestPoint(p)
+		if !ok || math.Abs(got.Distance-bestDistance) > 1e-12 {
+			t.Fatalf("Point %d: expected distance %f, got %f", i, bestDistance, got.Distance)
+		}
+	}
+}
+
+func TestClosestPointOnTriangle(t *testing.T) {
+	a, b, c := NewVertex(0, 0, 0), NewVertex(2, 0, 0), NewVertex(0, 2, 0)
+	cases := []struct {
+		name     string
+		p        Vertex
+		expected Vector
+	}{
+		{"above interior", NewVertex(0.5, 0.5, 3), NewVector(0.5, 0.5, 0)},
+		{"vertex region", NewVertex(-1, -1, 0), NewVector(0, 0, 0)},
+		{"edge region", NewVertex(1, -1, 1), NewVector(1, 0, 0)},
+		{"hypotenuse", NewVertex(2, 2, 0), NewVector(1, 1, 0)},
+	}
+	for _, test := range cases {
+		point, weights := ClosestPointOnTriangle(test.p, a, b, c)
+		assertVectorNear(t, test.name, test.expected, NewVectorFromVertex(point))
+		if math.Abs(weights[0]+weights[1]+weights[2]-1) > 1e-12 {
+			t.Errorf("%s: expected weights summing to 1, got %v", test.name, weights)
+		}
+	}
+}
+
+func TestBVH_EmptyMesh(t *testing.T) {
+	bvh := NewBVHWithDefaults(&Mesh{})
+	if _, ok := bvh.Intersect(NewRay(NewVertex(0, 0, 0), NewVector(1, 0, 0))); ok {
+		t.Error("Expected no hit on an empty mesh")
+	}
+	if _, ok := bvh.ClosestPoint(NewVertex(0, 0, 0)); ok {
+		t.Error("Expected no closest point on an empty mesh")
+	}
+	if _, err := NewBVH(&Mesh{}, BVHConfig{}); err == nil {
+		t.Error("Expected error for zero configuration, got nil")
+	}
+}
+
+func benchmarkRays(count int) []Ray {
+	random := rand.New(rand.NewSource(1))
+	rays := make([]Ray, count)
+	for i := range rays {
+		rays[i] = randomRay(random)
+	}
+	return rays
+}
+
+func BenchmarkMeshIntersect_BruteForce(b *testing.B) {
+	mesh := createWavyGrid(200)
+	rays := benchmarkRays(256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mesh.Intersect(rays[i%len(rays)])
+	}
+}
+
+func BenchmarkBVHIntersect(b *testing.B) {
+	mesh := createWavyGrid(200)
+	bvh := NewBVHWithDefaults(mesh)
+	rays := benchmarkRays(256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bvh.Intersect(rays[i%len(rays)])
+	}
+}
+
+func BenchmarkBVHBuild(b *testing.B) {
+	mesh := createWavyGrid(200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewBVHWithDefaults(mesh)
+	}
+}
+
+func BenchmarkBVHBuild_Sequential(b *testing.B) {
+	mesh := createWavyGrid(200)
+	config := DefaultBVHConfig()
+	config.ParallelThreshold = math.MaxInt
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = NewBVH(mesh, config)
+	}
+}
@@ -0,0 +1,476 @@
+// csg.go
+package geom
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// csgOperation selects the boolean combination computed by Mesh.csg
+type csgOperation int
+
+const (
+	csgUnion csgOperation = iota
+	csgIntersection
+	csgDifference
+)
+
+// csgRelativeTolerance is the thickness of a splitting plane relative to the
+// diagonal of both inputs; points closer than that lie on the plane
+const csgRelativeTolerance = 1e-7
+
+// Union returns a new closed mesh enclosing the space inside m or other.
+//
+// Union, Intersection and Difference need closed, consistently oriented
+// inputs, which are left unchanged; vertices split at the same position count
+// as one. The result keeps the face materials and
+// groups of both inputs but no per-vertex normals, UVs, colors or attributes.
+func (m *Mesh) Union(other *Mesh) (*Mesh, error) {
+	return m.csg(other, csgUnion)
+}
+
+// Intersection returns a new closed mesh enclosing the space inside both m and other
+func (m *Mesh) Intersection(other *Mesh) (*Mesh, error) {
+	return m.csg(other, csgIntersection)
+}
+
+// Difference returns a new closed mesh enclosing the space inside m but not inside other
+func (m *Mesh) Difference(other *Mesh) (*Mesh, error) {
+	return m.csg(other, csgDifference)
+}
+
+// csg combines both meshes with binary space partitioning trees: each tree
+// clips away the polygons of the other that lie on the discarded side
+func (m *Mesh) csg(other *Mesh, operation csgOperation) (*Mesh, error) {
+	if other == nil {
+		return nil, fmt.Errorf("CSG operand must not be nil")
+	}
+	// Vertices split at seams, e.g. for normals or UVs, share their position,
+	// so the operands are welded to see one surface
+	operands := []*Mesh{m.Clone(), other.Clone()}
+	for i, operand := range operands {
+		_, _ = operand.Weld(0)
+		topology := NewHalfEdgeMesh(operand)
+		if !topology.IsClosed() || len(topology.InconsistentEdges()) > 0 {
+			return nil, fmt.Errorf("CSG operand %d is not a closed, consistently oriented mesh", i+1)
+		}
+	}
+
+	result := &Mesh{}
+	bounds := m.BoundingBox().United(other.BoundingBox())
+	if bounds.IsEmpty() {
+		return result, nil
+	}
+	tolerance := csgRelativeTolerance * bounds.Size().Length()
+
+	a := newBSPNode(result.csgPolygons(operands[0]), tolerance)
+	b := newBSPNode(result.csgPolygons(operands[1]), tolerance)
+	switch operation {
+	case csgUnion:
+		a.clipTo(b, tolerance)
+		b.clipTo(a, tolerance)
+		b.invert()
+		b.clipTo(a, tolerance)
+		b.invert()
+		a.build(b.allPolygons(nil), tolerance)
+	case csgIntersection:
+		a.invert()
+		b.clipTo(a, tolerance)
+		b.invert()
+		a.clipTo(b, tolerance)
+		b.clipTo(a, tolerance)
+		a.build(b.allPolygons(nil), tolerance)
+		a.invert()
+	case csgDifference:
+		a.invert()
+		a.clipTo(b, tolerance)
+		b.clipTo(a, tolerance)
+		b.invert()
+		b.clipTo(a, tolerance)
+		b.invert()
+		a.build(b.allPolygons(nil), tolerance)
+		a.invert()
+	}
+
+	result.addCSGPolygons(a.allPolygons(nil), tolerance)
+	return result, nil
+}
+
+// csgPlane is the plane of points p with normal·p = offset
+type csgPlane struct {
+	normal Vector
+	offset float64
+}
+
+// distance returns the signed distance of the point from the plane
+func (p csgPlane) distance(point Vector) float64 {
+	return p.normal.Dot(point) - p.offset
+}
+
+func (p csgPlane) flipped() csgPlane {
+	return csgPlane{normal: p.normal.Multiplied(-1), offset: -p.offset}
+}
+
+// csgPolygon is a convex polygon lying on the plane of the face it was cut from
+type csgPolygon struct {
+	points   []Vector
+	plane    csgPlane
+	material int
+	group    int
+}
+
+func (p csgPolygon) flipped() csgPolygon {
+	points := slices.Clone(p.points)
+	slices.Reverse(points)
+	return csgPolygon{points: points, plane: p.plane.flipped(), material: p.material, group: p.group}
+}
+
+// csgPolygons converts the faces of source into polygons, appending its
+// materials and groups to m
+func (m *Mesh) csgPolygons(source *Mesh) []csgPolygon {
+	materialOffset := len(m.myMaterials)
+	m.myMaterials = append(m.myMaterials, source.myMaterials...)
+	groups := make([]int, len(source.myGroups))
+	for i, name := range source.myGroups {
+		groups[i] = m.AddGroup(name)
+	}
+
+	polygons := make([]csgPolygon, 0, len(source.myFaces))
+	for _, face := range source.myFaces {
+		key := face.myVertexIndices
+		slices.Sort(key[:])
+		v1, v2, v3 := source.faceVertices(face)
+		if isDegenerateTriangle(key, v1, v2, v3) {
+			continue
+		}
+
+		polygon := csgPolygon{
+			points:   []Vector{NewVectorFromVertex(v1), NewVectorFromVertex(v2), NewVectorFromVertex(v3)},
+			material: face.myMaterial,
+			group:    face.myGroup,
+		}
+		polygon.plane.normal = ComputeNormal(v1, v2, v3)
+		polygon.plane.offset = polygon.plane.normal.Dot(polygon.points[0])
+		if polygon.material != NoMaterial {
+			polygon.material += materialOffset
+		}
+		if polygon.group != NoGroup {
+			polygon.group = groups[polygon.group]
+		}
+		polygons = append(polygons, polygon)
+	}
+	return polygons
+}
+
+// Sides of a splitting plane, combined bitwise for polygons
+const (
+	csgCoplanar = 0
+	csgFront    = 1
+	csgBack     = 2
+	csgSpanning = csgFront | csgBack
+)
+
+// split sorts the polygon into the lists by its side of the plane, cutting it
+// in two when it spans the plane. Coplanar polygons go to coplanarFront when
+// they face the same way as the plane.
+func (p csgPlane) split(polygon csgPolygon, tolerance float64, coplanarFront, coplanarBack, front, back *[]csgPolygon) {
+	sides := make([]int, len(polygon.points))
+	side := csgCoplanar
+	for i, point := range polygon.points {
+		switch distance := p.distance(point); {
+		case distance < -tolerance:
+			sides[i] = csgBack
+		case distance > tolerance:
+			sides[i] = csgFront
+		}
+		side |= sides[i]
+	}
+
+	switch side {
+	case csgCoplanar:
+		if p.normal.Dot(polygon.plane.normal) > 0 {
+			*coplanarFront = append(*coplanarFront, polygon)
+		} else {
+			*coplanarBack = append(*coplanarBack, polygon)
+		}
+	case csgFront:
+		*front = append(*front, polygon)
+	case csgBack:
+		*back = append(*back, polygon)
+	case csgSpanning:
+		var frontPoints, backPoints []Vector
+		for i, point := range polygon.points {
+			j := (i + 1) % len(polygon.points)
+			if sides[i] != csgBack {
+				frontPoints = append(frontPoints, point)
+			}
+			if sides[i] != csgFront {
+				backPoints = append(backPoints, point)
+			}
+			if sides[i]|sides[j] == csgSpanning {
+				next := polygon.points[j]
+				direction := next.Subtracted(point)
+				t := -p.distance(point) / p.normal.Dot(direction)
+				crossing := direction.Multiplied(t)
+				crossing.Add(point)
+				frontPoints = append(frontPoints, crossing)
+				backPoints = append(backPoints, crossing)
+			}
+		}
+		if len(frontPoints) >= 3 {
+			*front = append(*front, csgPolygon{points: frontPoints, plane: polygon.plane, material: polygon.material, group: polygon.group})
+		}
+		if len(backPoints) >= 3 {
+			*back = append(*back, csgPolygon{points: backPoints, plane: polygon.plane, material: polygon.material, group: polygon.group})
+		}
+	}
+}
+
+// bspNode is a node of a solid BSP tree: the front of its plane is outside,
+// the back inside. A node without a plane is an empty leaf.
+type bspNode struct {
+	plane    *csgPlane
+	front    *bspNode
+	back     *bspNode
+	polygons []csgPolygon // lying on the plane
+}
+
+func newBSPNode(polygons []csgPolygon, tolerance float64) *bspNode {
+	node := &bspNode{}
+	node.build(polygons, tolerance)
+	return node
+}
+
+// build adds the polygons to the tree, splitting them by the planes on the way
+func (n *bspNode) build(polygons []csgPolygon, tolerance float64) {
+	if len(polygons) == 0 {
+		return
+	}
+	if n.plane == nil {
+		plane := polygons[0].plane
+		n.plane = &plane
+	}
+
+	var front, back []csgPolygon
+	for _, polygon := range polygons {
+		n.plane.split(polygon, tolerance, &n.polygons, &n.polygons, &front, &back)
+	}
+	if len(front) > 0 {
+		if n.front == nil {
+			n.front = &bspNode{}
+		}
+		n.front.build(front, tolerance)
+	}
+	if len(back) > 0 {
+		if n.back == nil {
+			n.back = &bspNode{}
+		}
+		n.back.build(back, tolerance)
+	}
+}
+
+// invert swaps the inside and the outside of the solid
+func (n *bspNode) invert() {
+	for i, polygon := range n.polygons {
+		n.polygons[i] = polygon.flipped()
+	}
+	if n.plane != nil {
+		plane := n.plane.flipped()
+		n.plane = &plane
+	}
+	if n.front != nil {
+		n.front.invert()
+	}
+	if n.back != nil {
+		n.back.invert()
+	}
+	n.front, n.back = n.back, n.front
+}
+
+// clipPolygons returns the parts of the polygons outside the solid
+func (n *bspNode) clipPolygons(polygons []csgPolygon, tolerance float64) []csgPolygon {
+	if n.plane == nil {
+		return slices.Clone(polygons)
+	}
+
+	var front, back []csgPolygon
+	for _, polygon := range polygons {
+		n.plane.split(polygon, tolerance, &front, &back, &front, &back)
+	}
+	if n.front != nil {
+		front = n.front.clipPolygons(front, tolerance)
+	}
+	if n.back != nil {
+		back = n.back.clipPolygons(back, tolerance)
+	} else {
+		back = nil
+	}
+	return append(front, back...)
+}
+
+// clipTo removes the parts of the polygons of this tree inside the other solid
+func (n *bspNode) clipTo(other *bspNode, tolerance float64) {
+	n.polygons = other.clipPolygons(n.polygons, tolerance)
+	if n.front != nil {
+		n.front.clipTo(other, tolerance)
+	}
+	if n.back != nil {
+		n.back.clipTo(other, tolerance)
+	}
+}
+
+// allPolygons appends the polygons of the tree to polygons
+func (n *bspNode) allPolygons(polygons []csgPolygon) []csgPolygon {
+	polygons = append(polygons, n.polygons...)
+	if n.front != nil {
+		polygons = n.front.allPolygons(polygons)
+	}
+	if n.back != nil {
+		polygons = n.back.allPolygons(polygons)
+	}
+	return polygons
+}
+
+// addCSGPolygons fans the polygons into faces and stitches them into a closed
+// surface: coincident points are welded, slivers and opposing face pairs
+// dropped, and edges split where a neighbouring polygon was cut
+func (m *Mesh) addCSGPolygons(polygons []csgPolygon, tolerance float64) {
+	for _, polygon := range polygons {
+		first := len(m.myVertices)
+		for _, point := range polygon.points {
+			m.myVertices = append(m.myVertices, NewVertex(point.X(), point.Y(), point.Z()))
+		}
+		for k := 1; k+1 < len(polygon.points); k++ {
+			m.myFaces = append(m.myFaces, Triangle{
+				myVertexIndices: [3]int{first, first + k, first + k + 1},
+				myNormal:        polygon.plane.normal,
+				myMaterial:      polygon.material,
+				myGroup:         polygon.group,
+			})
+		}
+	}
+
+	_, _ = m.Weld(tolerance)
+	m.RemoveDegenerateFaces()
+	m.removeOpposingFaces()
+	m.splitTJunctions(tolerance)
+	m.RemoveUnusedVertices()
+}
+
+// removeOpposingFaces drops pairs of faces on the same three vertices with
+// opposite winding, which enclose no volume
+func (m *Mesh) removeOpposingFaces() int {
+	// Rotate the smallest index first so that equal windings give equal keys
+	canonical := func(indices [3]int) [3]int {
+		for indices[0] > indices[1] || indices[0] > indices[2] {
+			indices = [3]int{indices[1], indices[2], indices[0]}
+		}
+		return indices
+	}
+
+	unmatched := make(map[[3]int][]int)
+	remove := make([]bool, len(m.myFaces))
+	for f, face := range m.myFaces {
+		key := canonical(face.myVertexIndices)
+		opposite := canonical([3]int{key[0], key[2], key[1]})
+		if faces := unmatched[opposite]; len(faces) > 0 {
+			remove[f], remove[faces[len(faces)-1]] = true, true
+			unmatched[opposite] = faces[:len(faces)-1]
+			continue
+		}
+		unmatched[key] = append(unmatched[key], f)
+	}
+
+	kept := m.myFaces[:0]
+	for f, face := range m.myFaces {
+		if !remove[f] {
+			kept = append(kept, face)
+		}
+	}
+	m.myFaces = kept
+	return len(remove) - len(kept)
+}
+
+// splitTJunctions splits faces whose boundary edge passes within tolerance of
+// another boundary vertex, closing the cracks left where only one side of an
+// edge was cut
+func (m *Mesh) splitTJunctions(tolerance float64) {
+	for {
+		topology := NewHalfEdgeMesh(m)
+		boundary := topology.BoundaryEdges()
+		var candidates []int
+		for _, e := range boundary {
+			edge := topology.Edge(e)
+			candidates = append(candidates, edge.V0, edge.V1)
+		}
+		slices.Sort(candidates)
+		candidates = slices.Compact(candidates)
+
+		split := make(map[int]bool)
+		for _, e := range boundary {
+			edge := topology.Edge(e)
+			f := edge.Faces[0]
+			if split[f] {
+				continue
+			}
+
+			// Walk the edge in the winding of its face, with c opposite
+			indices := m.myFaces[f].myVertexIndices
+			k := 0
+			for [2]int{min(indices[k], indices[(k+1)%3]), max(indices[k], indices[(k+1)%3])} != [2]int{edge.V0, edge.V1} {
+				k++
+			}
+			a, b, c := indices[k], indices[(k+1)%3], indices[(k+2)%3]
+
+			start := NewVectorFromVertex(m.myVertices[a])
+			end := NewVectorFromVertex(m.myVertices[b])
+			direction := end.Subtracted(start)
+			length := direction.Length()
+			type junction struct {
+				vertex int
+				t      float64
+			}
+			var junctions []junction
+			for _, v := range candidates {
+				if v == a || v == b || v == c {
+					continue
+				}
+				offset := NewVectorFromVertex(m.myVertices[v])
+				offset.Subtract(start)
+				t := offset.Dot(direction) / (length * length)
+				if t*length <= tolerance || (1-t)*length <= tolerance {
+					continue
+				}
+				if closest := direction.Multiplied(t); closest.Subtracted(offset).Length() <= tolerance {
+					junctions = append(junctions, junction{v, t})
+				}
+			}
+			if len(junctions) == 0 {
+				continue
+			}
+
+			sort.Slice(junctions, func(i, j int) bool { return junctions[i].t < junctions[j].t })
+			chain := []int{a}
+			for _, j := range junctions {
+				chain = append(chain, j.vertex)
+			}
+			chain = append(chain, b)
+
+			face := m.myFaces[f]
+			for i := 0; i+1 < len(chain); i++ {
+				face.myVertexIndices = [3]int{chain[i], chain[i+1], c}
+				if i == 0 {
+					m.myFaces[f] = face
+				} else {
+					m.myFaces = append(m.myFaces, face)
+				}
+			}
+			split[f] = true
+		}
+
+		if len(split) == 0 {
+			return
+		}
+	}
+}
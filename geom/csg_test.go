@@ -0,0 +1,166 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+// assertClosedSolid fails unless the mesh is a valid closed surface with the expected volume
+func assertClosedSolid(t *testing.T, name string, mesh *Mesh, volume, tolerance float64) {
+	t.Helper()
+	report := mesh.Validate()
+	if !report.IsWatertight() || len(report.InconsistentEdges) > 0 || len(report.DegenerateFaces) > 0 {
+		t.Errorf("%s: expected a closed, consistently oriented mesh, got\n%v", name, report)
+	}
+	if math.Abs(mesh.SignedVolume()-volume) > tolerance {
+		t.Errorf("%s: expected volume %v, got %v", name, volume, mesh.SignedVolume())
+	}
+}
+
+func TestCSG_OverlappingCubes(t *testing.T) {
+	a := CreateCube(2)
+	b := CreateCube(2)
+	b.Translate(NewVector(1, 1, 1))
+
+	union, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("Union failed: %v", err)
+	}
+	assertClosedSolid(t, "union", union, 15, 1e-9)
+
+	intersection, err := a.Intersection(b)
+	if err != nil {
+		t.Fatalf("Intersection failed: %v", err)
+	}
+	assertClosedSolid(t, "intersection", intersection, 1, 1e-9)
+	assertVectorNear(t, "intersection size", NewVector(1, 1, 1), intersection.BoundingBox().Size())
+
+	difference, err := a.Difference(b)
+	if err != nil {
+		t.Fatalf("Difference failed: %v", err)
+	}
+	assertClosedSolid(t, "difference", difference, 7, 1e-9)
+
+	if a.FaceNumber() != 12 || b.FaceNumber() != 12 {
+		t.Errorf("Expected the operands to stay unchanged")
+	}
+}
+
+func TestCSG_CubeAndSphere(t *testing.T) {
+	cube := CreateCube(2)
+	sphere := CreateIcosphere(1.25, 3)
+	sphere.Rotate(NewQuaternionFromAxisAngle(NewVector(1, 2, 3), 0.3))
+	sphereVolume := sphere.SignedVolume()
+
+	intersection, err := cube.Intersection(sphere)
+	if err != nil {
+		t.Fatalf("Intersection failed: %v", err)
+	}
+	inside := intersection.SignedVolume()
+	if inside >= 8 || inside >= sphereVolume || inside <= 4 {
+		t.Errorf("Expected the rounded cube to be smaller than both operands, got %v", inside)
+	}
+	assertClosedSolid(t, "intersection", intersection, inside, 0)
+
+	// The parts add up to the operands
+	difference, err := cube.Difference(sphere)
+	if err != nil {
+		t.Fatalf("Difference failed: %v", err)
+	}
+	assertClosedSolid(t, "cube minus sphere", difference, 8-inside, 1e-9)
+
+	union, err := cube.Union(sphere)
+	if err != nil {
+		t.Fatalf("Union failed: %v", err)
+	}
+	assertClosedSolid(t, "union", union, 8+sphereVolume-inside, 1e-9)
+
+	// A sphere inside the cube leaves a cavity
+	small := CreateIcosphere(0.5, 2)
+	hollow, _ := cube.Difference(small)
+	assertClosedSolid(t, "cavity", hollow, 8-small.SignedVolume(), 1e-9)
+	if hollow.BoundingBox().Size().Length() != cube.BoundingBox().Size().Length() {
+		t.Errorf("Expected the outside of the cube to stay, got %v", hollow.BoundingBox())
+	}
+}
+
+func TestCSG_CoplanarFaces(t *testing.T) {
+	// Cubes sharing a side merge into a box
+	a := CreateCube(2)
+	b := CreateCube(2)
+	b.Translate(NewVector(2, 0, 0))
+	union, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("Union failed: %v", err)
+	}
+	assertClosedSolid(t, "adjacent union", union, 16, 1e-9)
+	assertVectorNear(t, "adjacent union size", NewVector(4, 2, 2), union.BoundingBox().Size())
+
+	same, _ := a.Union(CreateCube(2))
+	assertClosedSolid(t, "self union", same, 8, 1e-9)
+	empty, _ := a.Difference(CreateCube(2))
+	if empty.FaceNumber() != 0 {
+		t.Errorf("Expected nothing left of a cube minus itself, got %d faces", empty.FaceNumber())
+	}
+	disjoint := CreateCube(1)
+	disjoint.Translate(NewVector(5, 0, 0))
+	if none, _ := a.Intersection(disjoint); none.FaceNumber() != 0 {
+		t.Errorf("Expected an empty intersection, got %d faces", none.FaceNumber())
+	}
+}
+
+func TestCSG_SplitVertices(t *testing.T) {
+	// Computing normals splits the cube at its edges into 24 vertices
+	a := CreateCube(2)
+	if _, err := a.ComputeVertexNormals(DefaultVertexNormalConfig()); err != nil {
+		t.Fatalf("ComputeVertexNormals failed: %v", err)
+	}
+	b := CreateCube(2)
+	b.Translate(NewVector(1, 1, 1))
+
+	union, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("Union failed: %v", err)
+	}
+	assertClosedSolid(t, "union", union, 15, 1e-9)
+	if a.VertexNumber() != 24 {
+		t.Errorf("Expected the operand to keep its split vertices, got %d", a.VertexNumber())
+	}
+}
+
+func TestCSG_KeepsMaterials(t *testing.T) {
+	a := CreateCube(2)
+	red := a.AddMaterial(NewMaterial("red"))
+	for f := 0; f < a.FaceNumber(); f++ {
+		_ = a.SetFaceMaterial(f, red)
+	}
+	b := CreateIcosphere(1.2, 2)
+	blue := b.AddMaterial(NewMaterial("blue"))
+	for f := 0; f < b.FaceNumber(); f++ {
+		_ = b.SetFaceMaterial(f, blue)
+	}
+
+	difference, err := a.Difference(b)
+	if err != nil {
+		t.Fatalf("Difference failed: %v", err)
+	}
+	counts := make(map[string]int)
+	for f := 0; f < difference.FaceNumber(); f++ {
+		index, _ := difference.FaceMaterial(f)
+		material, _ := difference.Material(index)
+		counts[material.Name]++
+	}
+	if counts["red"] == 0 || counts["blue"] == 0 || counts["red"]+counts["blue"] != difference.FaceNumber() {
+		t.Errorf("Expected the faces to keep red and blue materials, got %v", counts)
+	}
+}
+
+func TestCSG_InvalidOperands(t *testing.T) {
+	cube := CreateCube(1)
+	if _, err := cube.Union(nil); err == nil {
+		t.Errorf("Expected an error for a nil operand")
+	}
+	if _, err := cube.Difference(CreatePlane(1, 1, 1, 1)); err == nil {
+		t.Errorf("Expected an error for an open operand")
+	}
+}
@@ -0,0 +1,502 @@
+// decimate.go
+package geom
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// boundaryWeight scales the planes that hold border edges in place relative to
+// the planes of the faces
+const boundaryWeight = 1000
+
+// DecimationConfig controls Mesh.Decimate
+type DecimationConfig struct {
+	// Collapsing stops once the mesh has at most this many faces; 0 sets no target
+	TargetFaces int
+	// Collapses with a larger quadric error, the sum of squared distances to the
+	// planes of the original faces around the merged vertices, are not made.
+	// It is relative to the squared bounding box diagonal, so the limit does not
+	// depend on the size of the mesh; math.Inf(1) sets no limit
+	MaxError float64
+	// Border vertices only merge along the border and keep their original
+	// positions, and removing border corners is made expensive
+	PreserveBoundary bool
+	// Vertices that keep their position and are never merged into others
+	LockedVertices []int
+}
+
+// DefaultDecimationConfig removes only vertices that do not change the shape,
+// such as the inner vertices of flat regions
+func DefaultDecimationConfig() DecimationConfig {
+	return DecimationConfig{
+		TargetFaces:      0,
+		MaxError:         DefaultTolerance,
+		PreserveBoundary: true,
+	}
+}
+
+// Decimate simplifies the mesh by Garland–Heckbert quadric error edge collapses,
+// cheapest first, until the target face count or the error limit is reached.
+// Collapses that would make the surface non-manifold, pinch a border or flip a
+// face are skipped. The merged vertex blends the normals, UVs, colors and
+// attributes of both ends. It returns the number of removed faces.
+func (m *Mesh) Decimate(config DecimationConfig) (int, error) {
+	if config.TargetFaces < 0 {
+		return 0, fmt.Errorf("target face count must not be negative, got %d", config.TargetFaces)
+	}
+	if !(config.MaxError >= 0) {
+		return 0, fmt.Errorf("max error must be a non-negative number, got %v", config.MaxError)
+	}
+	locked := make([]bool, len(m.myVertices))
+	for _, v := range config.LockedVertices {
+		if v < 0 || v >= len(m.myVertices) {
+			return 0, fmt.Errorf("locked vertex index out of bounds: %d (mesh has %d vertices)", v, len(m.myVertices))
+		}
+		locked[v] = true
+	}
+
+	limit := config.MaxError
+	if diagonal := m.BoundingBox().Size().Length(); diagonal > 0 {
+		limit *= diagonal * diagonal
+	}
+
+	d := newDecimation(m, locked, config.PreserveBoundary)
+	before := d.faceNumber
+	for d.queue.Len() > 0 {
+		if config.TargetFaces > 0 && d.faceNumber <= config.TargetFaces {
+			break
+		}
+		c := heap.Pop(&d.queue).(edgeCollapse)
+		if c.cost > limit {
+			break
+		}
+		if c.keepVersion != d.versions[c.keep] || c.removeVersion != d.versions[c.remove] || !d.canCollapse(c) {
+			continue
+		}
+		d.collapse(c)
+	}
+	d.writeBack()
+	return before - d.faceNumber, nil
+}
+
+// quadric is the symmetric 4x4 error matrix of a vertex, stored as its upper
+// triangle: aa ab ac ad bb bc bd cc cd dd for planes ax + by + cz + d = 0
+type quadric [10]float64
+
+func planeQuadric(normal Vector, offset, weight float64) quadric {
+	a, b, c, d := normal.X(), normal.Y(), normal.Z(), offset
+	return quadric{
+		weight * a * a, weight * a * b, weight * a * c, weight * a * d,
+		weight * b * b, weight * b * c, weight * b * d,
+		weight * c * c, weight * c * d,
+		weight * d * d,
+	}
+}
+
+func (q *quadric) add(other quadric) {
+	for i := range q {
+		q[i] += other[i]
+	}
+}
+
+// evaluate returns the error of moving the vertex to p
+func (q quadric) evaluate(p Vector) float64 {
+	x, y, z := p.X(), p.Y(), p.Z()
+	return q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x +
+		q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+		q[7]*z*z + 2*q[8]*z +
+		q[9]
+}
+
+// minimizer returns the position of least error, or false if the error is
+// constant along a line or plane
+func (q quadric) minimizer() (Vector, bool) {
+	a := [3][3]float64{{q[0], q[1], q[2]}, {q[1], q[4], q[5]}, {q[2], q[5], q[7]}}
+	b := [3]float64{-q[3], -q[6], -q[8]}
+	det := determinant3(a)
+	scale := (q[0] + q[4] + q[7]) / 3
+	if math.Abs(det) <= 1e-10*scale*scale*scale || det == 0 {
+		return Vector{}, false
+	}
+	// Cramer's rule
+	var solution [3]float64
+	for column := range solution {
+		replaced := a
+		for row := range replaced {
+			replaced[row][column] = b[row]
+		}
+		solution[column] = determinant3(replaced) / det
+	}
+	return NewVector(solution[0], solution[1], solution[2]), true
+}
+
+func determinant3(a [3][3]float64) float64 {
+	return a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) -
+		a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) +
+		a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+}
+
+// edgeCollapse merges vertex remove into vertex keep, which moves to position.
+// The versions detect entries made stale by earlier collapses.
+type edgeCollapse struct {
+	cost          float64
+	keep, remove  int
+	position      Vector
+	keepVersion   int
+	removeVersion int
+}
+
+// collapseQueue is a min-heap of edge collapses by cost
+type collapseQueue []edgeCollapse
+
+func (q collapseQueue) Len() int           { return len(q) }
+func (q collapseQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q collapseQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *collapseQueue) Push(x any)        { *q = append(*q, x.(edgeCollapse)) }
+func (q *collapseQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+// decimation is the working state of Mesh.Decimate. Faces are edited in place
+// and compacted by writeBack.
+type decimation struct {
+	m                *Mesh
+	positions        []Vector
+	quadrics         []quadric
+	locked           []bool
+	border           []bool
+	preserveBoundary bool
+	versions         []int
+	vertexFaces      [][]int
+	removedFaces     []bool
+	removedVertex    []bool
+	changedFaces     []bool
+	faceNumber       int
+	queue            collapseQueue
+}
+
+func newDecimation(m *Mesh, locked []bool, preserveBoundary bool) *decimation {
+	d := &decimation{
+		m:                m,
+		positions:        make([]Vector, len(m.myVertices)),
+		quadrics:         make([]quadric, len(m.myVertices)),
+		locked:           locked,
+		border:           make([]bool, len(m.myVertices)),
+		preserveBoundary: preserveBoundary,
+		versions:         make([]int, len(m.myVertices)),
+		vertexFaces:      make([][]int, len(m.myVertices)),
+		removedFaces:     make([]bool, len(m.myFaces)),
+		removedVertex:    make([]bool, len(m.myVertices)),
+		changedFaces:     make([]bool, len(m.myFaces)),
+		faceNumber:       len(m.myFaces),
+	}
+	for v, vertex := range m.myVertices {
+		d.positions[v] = NewVectorFromVertex(vertex)
+	}
+
+	// Edges in order of first use keep the collapse order deterministic
+	var edges [][2]int
+	edgeFaces := make(map[[2]int][]int)
+	for f, face := range m.myFaces {
+		for _, v := range uniqueIndices(face.myVertexIndices) {
+			d.vertexFaces[v] = append(d.vertexFaces[v], f)
+		}
+		v1, v2, v3 := m.faceVertices(face)
+		normal := ComputeNormal(v1, v2, v3)
+		if normal.Length() == 0 || math.IsNaN(normal.Length()) {
+			continue
+		}
+		plane := planeQuadric(normal, -normal.Dot(d.positions[face.myVertexIndices[0]]), 1)
+		for k, a := range face.myVertexIndices {
+			d.quadrics[a].add(plane)
+			b := face.myVertexIndices[(k+1)%3]
+			key := [2]int{min(a, b), max(a, b)}
+			if _, ok := edgeFaces[key]; !ok {
+				edges = append(edges, key)
+			}
+			edgeFaces[key] = append(edgeFaces[key], f)
+		}
+	}
+
+	for _, key := range edges {
+		faces := edgeFaces[key]
+		if len(faces) != 1 {
+			continue
+		}
+		d.border[key[0]] = true
+		d.border[key[1]] = true
+		if preserveBoundary {
+			// A plane through the border edge, perpendicular to its face
+			face := m.myFaces[faces[0]]
+			v1, v2, v3 := m.faceVertices(face)
+			edge := d.positions[key[1]].Subtracted(d.positions[key[0]])
+			normal := edge.Cross(ComputeNormal(v1, v2, v3))
+			normal.Normalize()
+			plane := planeQuadric(normal, -normal.Dot(d.positions[key[0]]), boundaryWeight)
+			d.quadrics[key[0]].add(plane)
+			d.quadrics[key[1]].add(plane)
+		}
+	}
+
+	for _, key := range edges {
+		d.push(key[0], key[1])
+	}
+	return d
+}
+
+// push queues the collapse of edge (u, v) into its cheapest position
+func (d *decimation) push(u, v int) {
+	if d.locked[u] && d.locked[v] {
+		return
+	}
+	keep, remove := u, v
+	if d.locked[v] {
+		keep, remove = v, u
+	}
+
+	q := d.quadrics[keep]
+	q.add(d.quadrics[remove])
+	position, ok := q.minimizer()
+	onBorder := d.preserveBoundary && (d.border[keep] || d.border[remove])
+	switch {
+	case d.locked[keep]:
+		position = d.positions[keep]
+	case onBorder:
+		// Border vertices stay where they are, so merge into the cheaper border end
+		if !d.border[keep] || (d.border[remove] && q.evaluate(d.positions[remove]) < q.evaluate(d.positions[keep])) {
+			keep, remove = remove, keep
+		}
+		position = d.positions[keep]
+	case !ok:
+		// Without a unique minimum, take the best of the ends and the midpoint
+		midpoint := d.positions[keep].Added(d.positions[remove])
+		midpoint.Scale(0.5)
+		position = d.positions[keep]
+		for _, candidate := range []Vector{d.positions[remove], midpoint} {
+			if q.evaluate(candidate) < q.evaluate(position) {
+				position = candidate
+			}
+		}
+	}
+
+	heap.Push(&d.queue, edgeCollapse{
+		cost:          max(q.evaluate(position), 0),
+		keep:          keep,
+		remove:        remove,
+		position:      position,
+		keepVersion:   d.versions[keep],
+		removeVersion: d.versions[remove],
+	})
+}
+
+// neighbors returns the vertices that share a face with v
+func (d *decimation) neighbors(v int) []int {
+	var res []int
+	for _, f := range d.vertexFaces[v] {
+		for _, w := range d.m.myFaces[f].myVertexIndices {
+			if w != v && !slices.Contains(res, w) {
+				res = append(res, w)
+			}
+		}
+	}
+	return res
+}
+
+func (d *decimation) sharedFaces(u, v int) []int {
+	var res []int
+	for _, f := range d.vertexFaces[u] {
+		if slices.Contains(d.m.myFaces[f].myVertexIndices[:], v) {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// canCollapse reports whether the collapse keeps the surface manifold and
+// does not fold any remaining face over
+func (d *decimation) canCollapse(c edgeCollapse) bool {
+	shared := d.sharedFaces(c.keep, c.remove)
+	if len(shared) == 0 || len(shared) > 2 {
+		return false
+	}
+
+	// Link condition: the ends may only share the neighbours across the edge
+	var opposite []int
+	for _, f := range shared {
+		for _, w := range d.m.myFaces[f].myVertexIndices {
+			if w != c.keep && w != c.remove {
+				opposite = append(opposite, w)
+			}
+		}
+	}
+	removeNeighbors := d.neighbors(c.remove)
+	for _, w := range d.neighbors(c.keep) {
+		if w != c.remove && slices.Contains(removeNeighbors, w) && !slices.Contains(opposite, w) {
+			return false
+		}
+	}
+	if len(shared) == 2 && d.border[c.keep] && d.border[c.remove] {
+		return false
+	}
+
+	seen := make(map[[3]int]bool)
+	for _, v := range []int{c.keep, c.remove} {
+		for _, f := range d.vertexFaces[v] {
+			if slices.Contains(shared, f) {
+				continue
+			}
+			indices := d.m.myFaces[f].myVertexIndices
+			var before, after [3]Vector
+			for k, w := range indices {
+				before[k] = d.positions[w]
+				after[k] = d.positions[w]
+				if w == c.keep || w == c.remove {
+					after[k] = c.position
+					indices[k] = c.keep
+				}
+			}
+			oldNormal := triangleNormal(before)
+			newNormal := triangleNormal(after)
+			if newNormal.Length() == 0 || oldNormal.Dot(newNormal) <= 0 {
+				return false
+			}
+
+			// Two faces on the same three vertices would close a pocket
+			sorted := indices
+			slices.Sort(sorted[:])
+			if seen[sorted] {
+				return false
+			}
+			seen[sorted] = true
+		}
+	}
+	return true
+}
+
+func triangleNormal(corners [3]Vector) Vector {
+	side1 := corners[1].Subtracted(corners[0])
+	side2 := corners[2].Subtracted(corners[0])
+	normal := side1.Cross(side2)
+	normal.Normalize()
+	return normal
+}
+
+func (d *decimation) collapse(c edgeCollapse) {
+	keep, remove := c.keep, c.remove
+
+	// Blend the vertex data by where the new position projects onto the edge
+	edge := d.positions[remove].Subtracted(d.positions[keep])
+	t := 0.0
+	if length := edge.Dot(edge); length > 0 {
+		offset := c.position.Subtracted(d.positions[keep])
+		t = min(max(offset.Dot(edge)/length, 0), 1)
+	}
+	d.m.blendVertex(keep, remove, t)
+
+	for _, f := range d.vertexFaces[remove] {
+		face := &d.m.myFaces[f]
+		if slices.Contains(face.myVertexIndices[:], keep) {
+			d.removedFaces[f] = true
+			d.faceNumber--
+			for _, w := range face.myVertexIndices {
+				if w != remove {
+					d.vertexFaces[w] = slices.DeleteFunc(d.vertexFaces[w], func(g int) bool {
+						return g == f
+					})
+				}
+			}
+			continue
+		}
+		for k, w := range face.myVertexIndices {
+			if w == remove {
+				face.myVertexIndices[k] = keep
+			}
+		}
+		d.vertexFaces[keep] = append(d.vertexFaces[keep], f)
+	}
+	d.vertexFaces[remove] = nil
+	d.removedVertex[remove] = true
+	for _, f := range d.vertexFaces[keep] {
+		d.changedFaces[f] = true
+	}
+
+	d.positions[keep] = c.position
+	d.border[keep] = d.border[keep] || d.border[remove]
+	d.quadrics[keep].add(d.quadrics[remove])
+	d.versions[keep]++
+	d.versions[remove]++
+	for _, w := range d.neighbors(keep) {
+		d.push(keep, w)
+	}
+}
+
+// writeBack stores the new positions and face normals and drops the removed
+// faces and vertices
+func (d *decimation) writeBack() {
+	m := d.m
+	for v, position := range d.positions {
+		m.myVertices[v] = NewVertex(position.X(), position.Y(), position.Z())
+	}
+	for f, changed := range d.changedFaces {
+		if changed {
+			v1, v2, v3 := m.faceVertices(m.myFaces[f])
+			m.myFaces[f].myNormal = ComputeNormal(v1, v2, v3)
+		}
+	}
+
+	kept := m.myFaces[:0]
+	for f, face := range m.myFaces {
+		if !d.removedFaces[f] {
+			kept = append(kept, face)
+		}
+	}
+	m.myFaces = kept
+
+	target := make([]int, len(m.myVertices))
+	for v := range target {
+		target[v] = v
+		if d.removedVertex[v] {
+			target[v] = -1
+		}
+	}
+	m.remapVertices(target)
+}
+
+// blendVertex sets the normal, UV, color and attributes of vertex a to the
+// interpolation between a and b at t
+func (m *Mesh) blendVertex(a, b int, t float64) {
+	if t == 0 {
+		return
+	}
+	lerp := func(x, y float64) float64 {
+		return x + (y-x)*t
+	}
+	if m.myNormals != nil {
+		na, nb := m.myNormals[a], m.myNormals[b]
+		normal := NewVector(lerp(na.X(), nb.X()), lerp(na.Y(), nb.Y()), lerp(na.Z(), nb.Z()))
+		normal.Normalize()
+		m.myNormals[a] = normal
+	}
+	if m.myUVs != nil {
+		ua, ub := m.myUVs[a], m.myUVs[b]
+		m.myUVs[a] = NewVertex2d(lerp(ua.X(), ub.X()), lerp(ua.Y(), ub.Y()))
+	}
+	if m.myColors != nil {
+		ca, cb := m.myColors[a], m.myColors[b]
+		m.myColors[a] = NewColor(lerp(ca.R, cb.R), lerp(ca.G, cb.G), lerp(ca.B, cb.B), lerp(ca.A, cb.A))
+	}
+	for i := range m.myAttributes {
+		attribute := &m.myAttributes[i]
+		value := lerp(attribute.Values[a], attribute.Values[b])
+		if attribute.Type != ScalarFloat64 && attribute.Type != ScalarFloat32 {
+			value = math.Round(value)
+		}
+		attribute.Values[a] = value
+	}
+}
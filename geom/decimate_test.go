@@ -0,0 +1,134 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecimate_TargetFaces(t *testing.T) {
+	sphere := CreateIcosphere(1, 4)
+	volume := sphere.SignedVolume()
+	size := sphere.BoundingBox().Size()
+
+	config := DecimationConfig{TargetFaces: 500, MaxError: math.Inf(1), PreserveBoundary: true}
+	removed, err := sphere.Decimate(config)
+	if err != nil {
+		t.Fatalf("Decimate failed: %v", err)
+	}
+	if sphere.FaceNumber() > 500 || sphere.FaceNumber() < 490 || removed != 5120-sphere.FaceNumber() {
+		t.Fatalf("Expected about 500 faces, got %d with %d removed", sphere.FaceNumber(), removed)
+	}
+	if report := sphere.Validate(); !report.IsValid() {
+		t.Errorf("Expected a valid closed mesh, got\n%v", report)
+	}
+	if sphere.VertexNumber() != sphere.FaceNumber()/2+2 {
+		t.Errorf("Expected a closed genus-0 mesh, got %d vertices for %d faces", sphere.VertexNumber(), sphere.FaceNumber())
+	}
+
+	if math.Abs(sphere.SignedVolume()-volume)/volume > 0.02 {
+		t.Errorf("Expected the volume to stay within 2%% of %v, got %v", volume, sphere.SignedVolume())
+	}
+	decimated := sphere.BoundingBox().Size()
+	for _, pair := range [][2]float64{{size.X(), decimated.X()}, {size.Y(), decimated.Y()}, {size.Z(), decimated.Z()}} {
+		if math.Abs(pair[1]-pair[0])/pair[0] > 0.02 {
+			t.Errorf("Expected the bounding box to stay within 2%% of %v, got %v", size, decimated)
+		}
+	}
+}
+
+func TestDecimate_MaxError(t *testing.T) {
+	// Flat regions collapse without error, curved ones do not
+	plane := CreatePlane(4, 4, 8, 8)
+	for v := 0; v < plane.VertexNumber(); v++ {
+		vertex, _ := plane.Vertex(v)
+		_ = plane.SetVertexUV(v, NewVertex2d(vertex.X(), vertex.Y()))
+	}
+	if _, err := plane.Decimate(DefaultDecimationConfig()); err != nil {
+		t.Fatalf("Decimate failed: %v", err)
+	}
+	if plane.FaceNumber() > 8 {
+		t.Errorf("Expected the flat grid to collapse to a few faces, got %d", plane.FaceNumber())
+	}
+	if math.Abs(plane.SurfaceArea()-16) > 1e-9 {
+		t.Errorf("Expected the area 16 to be kept, got %v", plane.SurfaceArea())
+	}
+	assertVectorNear(t, "plane outline", NewVector(4, 4, 0), plane.BoundingBox().Size())
+	for v := 0; v < plane.VertexNumber(); v++ {
+		vertex, _ := plane.Vertex(v)
+		uv, _ := plane.VertexUV(v)
+		if math.Abs(uv.X()-vertex.X()) > 1e-9 || math.Abs(uv.Y()-vertex.Y()) > 1e-9 {
+			t.Errorf("Vertex %d at %v has UV %v", v, vertex, uv)
+		}
+	}
+
+	for _, radius := range []float64{1e-3, 1, 1e3} {
+		sphere := CreateUVSphere(radius, 16, 8)
+		if removed, _ := sphere.Decimate(DefaultDecimationConfig()); removed != 0 {
+			t.Errorf("Expected no collapses on a curved surface of radius %v, got %d", radius, removed)
+		}
+	}
+}
+
+func TestDecimate_PreserveBoundary(t *testing.T) {
+	grid := createWavyGrid(16)
+	box := grid.BoundingBox()
+	target := grid.FaceNumber() / 4
+
+	config := DecimationConfig{TargetFaces: target, MaxError: math.Inf(1), PreserveBoundary: true}
+	if _, err := grid.Decimate(config); err != nil {
+		t.Fatalf("Decimate failed: %v", err)
+	}
+	if grid.FaceNumber() > target {
+		t.Errorf("Expected at most %d faces, got %d", target, grid.FaceNumber())
+	}
+	// The border corners keep the outline in the XY plane
+	decimated := grid.BoundingBox()
+	for _, pair := range [][2]Vertex{{box.Min(), decimated.Min()}, {box.Max(), decimated.Max()}} {
+		expected, actual := NewVectorFromVertex(pair[0]), NewVectorFromVertex(pair[1])
+		assertVectorNear(t, "outline corner", NewVector(expected.X(), expected.Y(), 0), NewVector(actual.X(), actual.Y(), 0))
+	}
+
+	report := grid.Validate()
+	if len(report.Holes) != 1 || len(report.NonManifoldEdges) != 0 || len(report.NonManifoldVertices) != 0 {
+		t.Errorf("Expected a single open border, got\n%v", report)
+	}
+}
+
+func TestDecimate_LockedVertices(t *testing.T) {
+	sphere := CreateIcosphere(1, 3)
+	locked := []int{0, 5, 11, 100, 200}
+	var positions []Vertex
+	for _, v := range locked {
+		vertex, _ := sphere.Vertex(v)
+		positions = append(positions, vertex)
+	}
+
+	config := DecimationConfig{TargetFaces: 100, MaxError: math.Inf(1), LockedVertices: locked}
+	if _, err := sphere.Decimate(config); err != nil {
+		t.Fatalf("Decimate failed: %v", err)
+	}
+	for i, position := range positions {
+		found := false
+		for v := 0; v < sphere.VertexNumber(); v++ {
+			if vertex, _ := sphere.Vertex(v); vertex.myCoords.Equals(position.myCoords) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected locked vertex %d at %v to remain", locked[i], position)
+		}
+	}
+}
+
+func TestDecimate_InvalidConfig(t *testing.T) {
+	for name, config := range map[string]DecimationConfig{
+		"target":    {TargetFaces: -1},
+		"max error": {MaxError: math.NaN()},
+		"locked":    {LockedVertices: []int{8}},
+	} {
+		cube := CreateCube(1)
+		if _, err := cube.Decimate(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -4,8 +4,48 @@
 //   - 2D and 3D coordinate systems (Coords2d, Coords3d)
 //   - Vertex types for 2D and 3D points
 //   - Vector types with mathematical operations (dot product, cross product, normalization)
-//   - Mesh structures for representing 3D models with vertices and faces
-//   - Predefined 3D primitives (CreateCube, CreateTetrahedron)
+//   - Transforms: Matrix4 (composition, inversion, look-at and projection constructors,
+//     normal transform) and Quaternion (axis-angle, Euler angles, slerp)
+//   - Mesh structures for representing 3D models with vertices and faces, optional
+//     per-vertex normals, UVs, colors and named attributes, and per-face materials and groups
+//   - Mesh transforms (Transform, Transformed, Translate, Rotate, Scale) that keep normals
+//     and outward winding consistent
+//   - Bounding volumes (AABB, Sphere via Welzl's algorithm) and mass properties
+//     (SurfaceArea, SignedVolume, Centroid, MassProperties with the inertia tensor)
+//   - Plane with signed distances, affine transforms and AABB side tests
+//   - Ray casting: Ray with Möller–Trumbore triangle and slab AABB tests, and
+//     Mesh.Intersect / IntersectAll returning Hit records
+//   - BVH: a SAH-built bounding volume hierarchy over mesh faces with nearest-hit,
+//     any-hit, AABB overlap and closest-point queries
+//   - HalfEdgeMesh: face adjacency with vertex one-rings, face neighbours, edge-to-face
+//     lookup, boundary loops and non-manifold edge/vertex reporting
+//   - Mesh.Validate: a ValidationReport of non-manifold and boundary edges, holes,
+//     inconsistent winding, degenerate and duplicate faces, unused or invalid vertices
+//     and self-intersections, with the offending face and vertex indices
+//   - Repair: Weld, RemoveDegenerateFaces, RemoveDuplicateFaces, RemoveUnusedVertices,
+//     UnifyOrientation and FillHoles, combined by Mesh.Repair
+//   - Smooth vertex normals (ComputeVertexNormals) with angle, area or uniform weighting
+//     and a crease angle that splits vertices along sharp edges
+//   - Quadric error decimation (Mesh.Decimate) to a face count or error limit, with
+//     border preservation and locked vertices
+//   - Loop and Catmull–Clark subdivision (Mesh.Subdivide) with boundary rules and
+//     creases from explicit edges or a dihedral angle threshold
+//   - Constructive solid geometry on closed meshes (Mesh.Union, Intersection,
+//     Difference) with BSP trees, returning closed meshes with the inputs' materials
+//   - Convex hulls of point sets and meshes (ConvexHull, Mesh.ConvexHull) by quickhull,
+//     with flat polygons for coplanar input
+//   - Predefined 3D primitives: platonic solids (CreateCube, CreateTetrahedron,
+//     CreateOctahedron, CreateDodecahedron, CreateIcosahedron) and parametric
+//     shapes (CreateUVSphere, CreateIcosphere, CreateCylinder, CreateCone,
+//     CreateTorus, CreatePlane, CreateCapsule)
+//   - STL import and export (ReadSTL, WriteSTLBinary, WriteSTLASCII)
+//   - Wavefront OBJ/MTL import and export (LoadOBJ, ReadOBJ, ReadMTL, WriteOBJ, SaveOBJ)
+//   - PLY import and export in ASCII and binary encodings that keep the
+//     stored scalar types (ReadPLY, ReadPLYWithWarnings, WritePLY)
+//   - MergeMeshes for combining meshes with their per-vertex data and materials
+//
+// Format-independent loading and saving by extension or content sniffing lives
+// in the geom/meshio subpackage.
 //
 // All geometric operations use floating-point arithmetic with a default tolerance
 // (DefaultTolerance) for equality comparisons to handle floating-point precision issues.
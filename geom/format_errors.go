@@ -0,0 +1,43 @@
+// format_errors.go
+package geom
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTruncated reports that a mesh file ended before all of its declared data was read
+	ErrTruncated = errors.New("truncated file")
+
+	// ErrMalformed reports that a mesh file does not follow the grammar of its format
+	ErrMalformed = errors.New("malformed file")
+)
+
+// FormatError describes a failure to decode a mesh file.
+// Use errors.Is with ErrTruncated or ErrMalformed to classify it.
+type FormatError struct {
+	Format string // short format name, e.g. "stl"
+	Line   int    // 1-based line number for text encodings, 0 if not applicable
+	Kind   error  // ErrTruncated or ErrMalformed
+	Msg    string
+}
+
+func (e *FormatError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s: %v at line %d: %s", e.Format, e.Kind, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %v: %s", e.Format, e.Kind, e.Msg)
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Kind
+}
+
+func truncatedError(name string, line int, msg string, args ...any) error {
+	return &FormatError{Format: name, Line: line, Kind: ErrTruncated, Msg: fmt.Sprintf(msg, args...)}
+}
+
+func malformedError(name string, line int, msg string, args ...any) error {
+	return &FormatError{Format: name, Line: line, Kind: ErrMalformed, Msg: fmt.Sprintf(msg, args...)}
+}
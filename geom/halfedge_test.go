@@ -0,0 +1,225 @@
+package geom
+
+import (
+	"slices"
+	"testing"
+)
+
+// createGrid returns an n×n grid of quads split into triangles in the XY plane
+func createGrid(n int) *Mesh {
+	mesh := &Mesh{}
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			mesh.AddVertex(NewVertex(float64(x), float64(y), 0))
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			a := y*(n+1) + x
+			_, _ = mesh.AddFace(a, a+1, a+n+2)
+			_, _ = mesh.AddFace(a, a+n+2, a+n+1)
+		}
+	}
+	return mesh
+}
+
+func TestHalfEdgeMesh_ClosedCube(t *testing.T) {
+	h := NewHalfEdgeMesh(CreateCube(2))
+
+	if h.HalfEdgeNumber() != 36 || h.EdgeNumber() != 18 {
+		t.Fatalf("Expected 36 half-edges and 18 edges, got %d and %d", h.HalfEdgeNumber(), h.EdgeNumber())
+	}
+	if !h.IsClosed() || !h.IsManifold() {
+		t.Fatalf("Expected the cube to be closed and manifold")
+	}
+	if loops := h.BoundaryLoops(); len(loops) != 0 {
+		t.Errorf("Expected no boundary loops, got %v", loops)
+	}
+
+	for e := 0; e < h.HalfEdgeNumber(); e++ {
+		twin := h.Twin(e)
+		if twin == NoHalfEdge {
+			t.Fatalf("Half-edge %d has no twin", e)
+		}
+		if h.Twin(twin) != e || h.Origin(twin) != h.Target(e) || h.Target(twin) != h.Origin(e) {
+			t.Errorf("Half-edge %d and twin %d do not match", e, twin)
+		}
+		if h.Next(h.Prev(e)) != e || h.EdgeOf(e) != h.EdgeOf(twin) {
+			t.Errorf("Half-edge %d has inconsistent links", e)
+		}
+	}
+
+	for f := 0; f < h.FaceNumber(); f++ {
+		for _, neighbor := range h.FaceNeighbors(f) {
+			back := h.FaceNeighbors(neighbor)
+			if neighbor < 0 || !slices.Contains(back[:], f) {
+				t.Errorf("Face %d and neighbor %d are not mutual", f, neighbor)
+			}
+		}
+	}
+
+	for v := 0; v < h.VertexNumber(); v++ {
+		ring := h.VertexNeighbors(v)
+		faces := h.VertexFaces(v)
+		if len(ring) != h.VertexValence(v) || len(faces) != len(ring) {
+			t.Errorf("Vertex %d: ring %v and faces %v do not match valence %d", v, ring, faces, h.VertexValence(v))
+		}
+		// Consecutive ring vertices span a face around v
+		for i := range ring {
+			edgeFaces := h.EdgeFaces(ring[i], ring[(i+1)%len(ring)])
+			if !slices.Contains(edgeFaces, faces[i]) {
+				t.Errorf("Vertex %d: ring %v is not ordered like faces %v", v, ring, faces)
+				break
+			}
+		}
+	}
+}
+
+func TestHalfEdgeMesh_GridBoundary(t *testing.T) {
+	h := NewHalfEdgeMesh(createGrid(2))
+
+	if h.IsClosed() || !h.IsManifold() {
+		t.Fatalf("Expected an open manifold grid")
+	}
+	if len(h.BoundaryEdges()) != 8 {
+		t.Errorf("Expected 8 boundary edges, got %d", len(h.BoundaryEdges()))
+	}
+
+	loops := h.BoundaryLoops()
+	if len(loops) != 1 || len(loops[0]) != 8 {
+		t.Fatalf("Expected one boundary loop of 8 vertices, got %v", loops)
+	}
+	for i, v := range loops[0] {
+		if v == 4 || !h.IsBoundaryVertex(v) {
+			t.Errorf("Loop vertex %d is not on the boundary", v)
+		}
+		next := loops[0][(i+1)%len(loops[0])]
+		if edge, ok := h.FindEdge(v, next); !ok || !h.IsBoundaryEdge(edge) {
+			t.Errorf("Loop step %d -> %d is not a boundary edge", v, next)
+		}
+	}
+
+	// The interior vertex has a closed fan of 6 faces
+	if h.IsBoundaryVertex(4) || len(h.VertexFaces(4)) != 6 || len(h.VertexNeighbors(4)) != 6 {
+		t.Errorf("Expected interior vertex with 6 faces, got faces %v ring %v", h.VertexFaces(4), h.VertexNeighbors(4))
+	}
+
+	// The corner vertex 2 has one face and an open ring of two vertices
+	if faces, ring := h.VertexFaces(2), h.VertexNeighbors(2); len(faces) != 1 || len(ring) != 2 {
+		t.Errorf("Expected corner with 1 face and 2 neighbors, got faces %v ring %v", faces, ring)
+	}
+	// Boundary vertex 1 has an open fan of three faces and four neighbors
+	if faces, ring := h.VertexFaces(1), h.VertexNeighbors(1); len(faces) != 3 || len(ring) != 4 {
+		t.Errorf("Expected boundary vertex with 3 faces and 4 neighbors, got faces %v ring %v", faces, ring)
+	}
+}
+
+func TestHalfEdgeMesh_NonManifold(t *testing.T) {
+	// Two triangles touching only at vertex 0
+	bowtie := &Mesh{}
+	for _, v := range []Vertex{
+		NewVertex(0, 0, 0), NewVertex(1, 0, 0), NewVertex(1, 1, 0),
+		NewVertex(-1, 0, 0), NewVertex(-1, -1, 0),
+	} {
+		bowtie.AddVertex(v)
+	}
+	_, _ = bowtie.AddFace(0, 1, 2)
+	_, _ = bowtie.AddFace(0, 3, 4)
+
+	h := NewHalfEdgeMesh(bowtie)
+	if got := h.NonManifoldVertices(); !slices.Equal(got, []int{0}) {
+		t.Errorf("Expected bow-tie vertex 0, got %v", got)
+	}
+	if len(h.NonManifoldEdges()) != 0 || h.IsManifold() {
+		t.Errorf("Expected no non-manifold edges but a non-manifold mesh")
+	}
+	if got := h.VertexFaces(0); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("Expected both faces at the bow-tie vertex, got %v", got)
+	}
+	if len(h.BoundaryLoops()) != 2 {
+		t.Errorf("Expected two boundary loops, got %v", h.BoundaryLoops())
+	}
+
+	// Three triangles sharing edge 0-1
+	fin := &Mesh{}
+	for _, v := range []Vertex{
+		NewVertex(0, 0, 0), NewVertex(1, 0, 0),
+		NewVertex(0, 1, 0), NewVertex(0, -1, 0), NewVertex(0, 0, 1),
+	} {
+		fin.AddVertex(v)
+	}
+	_, _ = fin.AddFace(0, 1, 2)
+	_, _ = fin.AddFace(1, 0, 3)
+	_, _ = fin.AddFace(0, 1, 4)
+
+	h = NewHalfEdgeMesh(fin)
+	edge, ok := h.FindEdge(1, 0)
+	if !ok {
+		t.Fatalf("Expected edge 0-1")
+	}
+	if got := h.NonManifoldEdges(); !slices.Equal(got, []int{edge}) {
+		t.Errorf("Expected non-manifold edge %d, got %v", edge, got)
+	}
+	if got := h.EdgeFaces(0, 1); !slices.Equal(got, []int{0, 1, 2}) || h.EdgeValence(edge) != 3 {
+		t.Errorf("Expected faces [0 1 2] on edge 0-1, got %v", got)
+	}
+	if got := h.NonManifoldVertices(); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("Expected non-manifold vertices [0 1], got %v", got)
+	}
+	if h.Twin(0) != NoHalfEdge {
+		t.Errorf("Expected no twin across a non-manifold edge")
+	}
+}
+
+func TestHalfEdgeMesh_InconsistentOrientation(t *testing.T) {
+	mesh := createGrid(1)
+	h := NewHalfEdgeMesh(mesh)
+	if len(h.InconsistentEdges()) != 0 {
+		t.Errorf("Expected a consistently oriented grid")
+	}
+
+	// Reverse the second triangle so both traverse the diagonal 0-3 the same way
+	reversed := &Mesh{}
+	for v := 0; v < mesh.VertexNumber(); v++ {
+		vertex, _ := mesh.Vertex(v)
+		reversed.AddVertex(vertex)
+	}
+	_, _ = reversed.AddFace(0, 1, 3)
+	_, _ = reversed.AddFace(0, 2, 3)
+
+	h = NewHalfEdgeMesh(reversed)
+	edge, _ := h.FindEdge(0, 3)
+	if got := h.InconsistentEdges(); !slices.Equal(got, []int{edge}) {
+		t.Errorf("Expected inconsistent edge %d, got %v", edge, got)
+	}
+	if neighbors := h.FaceNeighbors(0); slices.Contains(neighbors[:], 1) {
+		t.Errorf("Expected no twin across the inconsistent edge, got neighbors %v", neighbors)
+	}
+	if !h.IsManifold() {
+		t.Errorf("Orientation alone should not make the mesh non-manifold")
+	}
+}
+
+func TestHalfEdgeMesh_ToMesh(t *testing.T) {
+	mesh := CreateTetrahedron(1)
+	_ = mesh.SetVertexColor(2, NewColor(1, 0, 0, 1))
+
+	h := NewHalfEdgeMesh(mesh)
+	_, _ = mesh.AddFace(0, 1, 2)
+
+	back := h.ToMesh()
+	if back.FaceNumber() != 4 || back.VertexNumber() != 4 {
+		t.Fatalf("Expected the original 4 faces and vertices, got %d and %d", back.FaceNumber(), back.VertexNumber())
+	}
+	for f := 0; f < back.FaceNumber(); f++ {
+		indices, _ := back.FaceIndices(f)
+		for k, halfEdge := range h.FaceHalfEdges(f) {
+			if h.Origin(halfEdge) != indices[k] {
+				t.Errorf("Face %d: half-edge %d starts at %d, expected %d", f, halfEdge, h.Origin(halfEdge), indices[k])
+			}
+		}
+	}
+	if color, _ := back.VertexColor(2); !color.Equals(NewColor(1, 0, 0, 1)) {
+		t.Errorf("Expected vertex color to survive, got %v", color)
+	}
+}
@@ -0,0 +1,303 @@
+// hull.go
+package geom
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// ConvexHull returns the smallest convex mesh containing the points, built by
+// quickhull with faces wound outward. Points on the hull surface but not at a
+// corner, duplicates and interior points are left out; the hull vertices keep
+// the order of the input.
+//
+// Degenerate inputs give degenerate hulls: coplanar points a single-sided
+// convex polygon, collinear points the two end points and a single distinct
+// point that point, both without faces.
+func ConvexHull(points []Vertex) *Mesh {
+	if len(points) == 0 {
+		return &Mesh{}
+	}
+	position := func(i int) Vector {
+		return NewVectorFromVertex(points[i])
+	}
+
+	// Points closer than the tolerance to a face count as lying on it
+	scale := 0.0
+	for _, p := range points {
+		scale = max(scale, math.Abs(p.myCoords.X), math.Abs(p.myCoords.Y), math.Abs(p.myCoords.Z))
+	}
+	tolerance := DefaultTolerance * max(scale, 1)
+
+	// The initial simplex grows from the two farthest axis extremes
+	var extremes []int
+	for axis := 0; axis < 3; axis++ {
+		low, high := 0, 0
+		for i, p := range points {
+			if coordinate(p, axis) < coordinate(points[low], axis) {
+				low = i
+			}
+			if coordinate(p, axis) > coordinate(points[high], axis) {
+				high = i
+			}
+		}
+		extremes = append(extremes, low, high)
+	}
+	a, b := extremes[0], extremes[0]
+	for _, i := range extremes {
+		for _, j := range extremes {
+			if points[i].Distance(points[j]) > points[a].Distance(points[b]) {
+				a, b = i, j
+			}
+		}
+	}
+	if points[a].Distance(points[b]) <= tolerance {
+		return hullMesh(points, []int{a}, nil)
+	}
+
+	axis := position(b)
+	axis.Subtract(position(a))
+	axis.Normalize()
+	c, lineDistance := a, 0.0
+	for i := range points {
+		offset := position(i)
+		offset.Subtract(position(a))
+		if distance := offset.Cross(axis).Length(); distance > lineDistance {
+			c, lineDistance = i, distance
+		}
+	}
+	if lineDistance <= tolerance {
+		return hullMesh(points, []int{min(a, b), max(a, b)}, nil)
+	}
+
+	base := newHullFace(points, a, b, c)
+	d, planeDistance := a, 0.0
+	for i := range points {
+		if distance := math.Abs(base.distance(position(i))); distance > planeDistance {
+			d, planeDistance = i, distance
+		}
+	}
+	if planeDistance <= tolerance {
+		return planarHull(points, base, tolerance)
+	}
+
+	return quickhull(points, [4]int{a, b, c, d}, tolerance)
+}
+
+// ConvexHull returns the convex hull of the mesh vertices
+func (m *Mesh) ConvexHull() *Mesh {
+	return ConvexHull(m.myVertices)
+}
+
+// coordinate returns the X, Y or Z coordinate of the vertex for axis 0, 1 or 2
+func coordinate(v Vertex, axis int) float64 {
+	switch axis {
+	case 0:
+		return v.myCoords.X
+	case 1:
+		return v.myCoords.Y
+	}
+	return v.myCoords.Z
+}
+
+// hullFace is a triangle of the hull under construction with the points
+// assigned to it that lie outside its plane
+type hullFace struct {
+	vertices [3]int
+	normal   Vector
+	offset   float64
+	outside  []int
+	removed  bool
+}
+
+func newHullFace(points []Vertex, a, b, c int) *hullFace {
+	normal := ComputeNormal(points[a], points[b], points[c])
+	return &hullFace{
+		vertices: [3]int{a, b, c},
+		normal:   normal,
+		offset:   normal.Dot(NewVectorFromVertex(points[a])),
+	}
+}
+
+// distance returns the signed distance of the point above the face plane
+func (f *hullFace) distance(point Vector) float64 {
+	return f.normal.Dot(point) - f.offset
+}
+
+// quickhull grows the hull from a tetrahedron by repeatedly adding the
+// farthest outside point of a face and replacing the faces it can see
+func quickhull(points []Vertex, simplex [4]int, tolerance float64) *Mesh {
+	var faces []*hullFace
+	edges := make(map[[2]int]int) // directed edge → face
+	addFace := func(a, b, c int) *hullFace {
+		face := newHullFace(points, a, b, c)
+		for k := 0; k < 3; k++ {
+			edges[[2]int{face.vertices[k], face.vertices[(k+1)%3]}] = len(faces)
+		}
+		faces = append(faces, face)
+		return face
+	}
+
+	// Wind each side of the tetrahedron away from the opposite corner
+	for _, side := range [4][4]int{{0, 1, 2, 3}, {0, 3, 1, 2}, {0, 2, 3, 1}, {1, 3, 2, 0}} {
+		a, b, c, opposite := simplex[side[0]], simplex[side[1]], simplex[side[2]], simplex[side[3]]
+		if newHullFace(points, a, b, c).distance(NewVectorFromVertex(points[opposite])) > 0 {
+			b, c = c, b
+		}
+		addFace(a, b, c)
+	}
+
+	assign := func(candidates []int, to []*hullFace) {
+		for _, i := range candidates {
+			for _, face := range to {
+				if face.distance(NewVectorFromVertex(points[i])) > tolerance {
+					face.outside = append(face.outside, i)
+					break
+				}
+			}
+		}
+	}
+	var rest []int
+	for i := range points {
+		if !slices.Contains(simplex[:], i) {
+			rest = append(rest, i)
+		}
+	}
+	assign(rest, faces)
+
+	for f := 0; f < len(faces); f++ {
+		for !faces[f].removed && len(faces[f].outside) > 0 {
+			start := faces[f]
+			eye := start.outside[0]
+			for _, i := range start.outside {
+				if start.distance(NewVectorFromVertex(points[i])) > start.distance(NewVectorFromVertex(points[eye])) {
+					eye = i
+				}
+			}
+			eyePosition := NewVectorFromVertex(points[eye])
+
+			// Collect the faces the eye can see and the horizon edges around them
+			visible := map[int]bool{f: true}
+			stack := []int{f}
+			var horizon [][2]int
+			for len(stack) > 0 {
+				current := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				vertices := faces[current].vertices
+				for k := 0; k < 3; k++ {
+					edge := [2]int{vertices[k], vertices[(k+1)%3]}
+					neighbor := edges[[2]int{edge[1], edge[0]}]
+					if visible[neighbor] {
+						continue
+					}
+					if faces[neighbor].distance(eyePosition) > tolerance {
+						visible[neighbor] = true
+						stack = append(stack, neighbor)
+						continue
+					}
+					horizon = append(horizon, edge)
+				}
+			}
+
+			var orphans []int
+			for v := range visible {
+				face := faces[v]
+				face.removed = true
+				for _, i := range face.outside {
+					if i != eye {
+						orphans = append(orphans, i)
+					}
+				}
+				face.outside = nil
+				for k := 0; k < 3; k++ {
+					edge := [2]int{face.vertices[k], face.vertices[(k+1)%3]}
+					if edges[edge] == v {
+						delete(edges, edge)
+					}
+				}
+			}
+
+			created := make([]*hullFace, 0, len(horizon))
+			for _, edge := range horizon {
+				created = append(created, addFace(edge[0], edge[1], eye))
+			}
+			// Orphans were collected from a map, so sort them to keep the result deterministic
+			sort.Ints(orphans)
+			assign(orphans, created)
+		}
+	}
+
+	var vertices []int
+	var triangles [][3]int
+	for _, face := range faces {
+		if !face.removed {
+			vertices = append(vertices, face.vertices[:]...)
+			triangles = append(triangles, face.vertices)
+		}
+	}
+	slices.Sort(vertices)
+	return hullMesh(points, slices.Compact(vertices), triangles)
+}
+
+// planarHull returns the convex polygon around coplanar points, triangulated
+// on the side facing the normal of base
+func planarHull(points []Vertex, base *hullFace, tolerance float64) *Mesh {
+	origin := NewVectorFromVertex(points[base.vertices[0]])
+	u := NewVectorFromVertex(points[base.vertices[1]])
+	u.Subtract(origin)
+	u.Normalize()
+	v := base.normal.Cross(u)
+
+	projected := make([]Coords2d, len(points))
+	order := make([]int, len(points))
+	for i := range points {
+		offset := NewVectorFromVertex(points[i])
+		offset.Subtract(origin)
+		projected[i] = Coords2d{offset.Dot(u), offset.Dot(v)}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		p, q := projected[order[i]], projected[order[j]]
+		return p.X < q.X || (p.X == q.X && p.Y < q.Y)
+	})
+
+	// Andrew's monotone chain, dropping points on the outline between corners
+	chain := func(indices []int) []int {
+		var hull []int
+		for _, i := range indices {
+			for len(hull) >= 2 {
+				a, b := projected[hull[len(hull)-2]], projected[hull[len(hull)-1]]
+				// The cross product is the distance from the line times the length of ab
+				if cross2d(a, b, projected[i]) > tolerance*a.Distance(b) {
+					break
+				}
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, i)
+		}
+		return hull[:len(hull)-1]
+	}
+	lower := chain(order)
+	slices.Reverse(order)
+	outline := append(lower, chain(order)...)
+
+	var vertices []int
+	vertices = append(vertices, outline...)
+	slices.Sort(vertices)
+	return hullMesh(points, vertices, fanTriangulation(outline))
+}
+
+// hullMesh builds a mesh from the given point indices, in ascending order, and
+// faces referring to them
+func hullMesh(points []Vertex, vertices []int, triangles [][3]int) *Mesh {
+	mesh := &Mesh{}
+	index := make(map[int]int, len(vertices))
+	for _, i := range vertices {
+		index[i] = mesh.AddVertex(points[i])
+	}
+	for _, triangle := range triangles {
+		_, _ = mesh.AddFace(index[triangle[0]], index[triangle[1]], index[triangle[2]])
+	}
+	return mesh
+}
@@ -0,0 +1,101 @@
+package geom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestConvexHull_Cube(t *testing.T) {
+	// Corners, points on the sides and edges, duplicates and interior points
+	var points []Vertex
+	for x := 0; x <= 4; x++ {
+		for y := 0; y <= 4; y++ {
+			for z := 0; z <= 4; z++ {
+				points = append(points, NewVertex(float64(x)/4, float64(y)/4, float64(z)/4))
+			}
+		}
+	}
+	points = append(points, points[:10]...)
+
+	hull := ConvexHull(points)
+	if hull.VertexNumber() != 8 || hull.FaceNumber() != 12 {
+		t.Fatalf("Expected 8 corners and 12 faces, got %d and %d", hull.VertexNumber(), hull.FaceNumber())
+	}
+	if report := hull.Validate(); !report.IsValid() {
+		t.Errorf("Expected a valid closed mesh, got\n%v", report)
+	}
+	if math.Abs(hull.SignedVolume()-1) > 1e-12 {
+		t.Errorf("Expected an outward hull with volume 1, got %v", hull.SignedVolume())
+	}
+}
+
+func TestConvexHull_RandomPoints(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	points := make([]Vertex, 500)
+	for i := range points {
+		points[i] = NewVertex(random.NormFloat64()*100, random.NormFloat64()*50, random.NormFloat64()*20+300)
+	}
+
+	hull := ConvexHull(points)
+	report := hull.Validate()
+	if !report.IsValid() {
+		t.Fatalf("Expected a valid closed mesh, got\n%v", report)
+	}
+	// A closed triangulated sphere has F = 2V - 4
+	if hull.FaceNumber() != 2*hull.VertexNumber()-4 {
+		t.Errorf("Expected %d faces for %d vertices, got %d", 2*hull.VertexNumber()-4, hull.VertexNumber(), hull.FaceNumber())
+	}
+	for f := 0; f < hull.FaceNumber(); f++ {
+		corner, _ := hull.VertexInFace(f, 0)
+		normal, _ := hull.Normal(f)
+		for _, p := range points {
+			if offset := NewVectorFromVertices(corner, p); offset.Dot(normal) > 1e-9 {
+				t.Fatalf("Face %d: point %v lies outside by %v", f, p, offset.Dot(normal))
+			}
+		}
+	}
+
+	sphere := CreateIcosphere(2, 2)
+	sphereHull := sphere.ConvexHull()
+	if sphereHull.FaceNumber() != sphere.FaceNumber() || math.Abs(sphereHull.SignedVolume()-sphere.SignedVolume()) > 1e-9 {
+		t.Errorf("Expected the hull of a convex mesh to match it, got %d faces and volume %v",
+			sphereHull.FaceNumber(), sphereHull.SignedVolume())
+	}
+}
+
+func TestConvexHull_Degenerate(t *testing.T) {
+	if hull := ConvexHull(nil); hull.VertexNumber() != 0 {
+		t.Errorf("Expected an empty hull, got %d vertices", hull.VertexNumber())
+	}
+
+	same := []Vertex{NewVertex(1, 2, 3), NewVertex(1, 2, 3)}
+	if hull := ConvexHull(same); hull.VertexNumber() != 1 || hull.FaceNumber() != 0 {
+		t.Errorf("Expected a single point, got %d vertices and %d faces", hull.VertexNumber(), hull.FaceNumber())
+	}
+
+	line := []Vertex{NewVertex(1, 1, 1), NewVertex(3, 3, 3), NewVertex(0, 0, 0), NewVertex(2, 2, 2)}
+	hull := ConvexHull(line)
+	if hull.VertexNumber() != 2 || hull.FaceNumber() != 0 {
+		t.Fatalf("Expected the two end points, got %d vertices and %d faces", hull.VertexNumber(), hull.FaceNumber())
+	}
+	if first, _ := hull.Vertex(0); !first.myCoords.Equals(Coords3d{3, 3, 3}) {
+		t.Errorf("Expected the end points in input order, got %v first", first)
+	}
+
+	// A tilted grid gives its outline, with one side of faces
+	plane := CreatePlane(4, 2, 4, 4)
+	plane.Rotate(NewQuaternionFromAxisAngle(NewVector(1, 1, 0), 0.5))
+	flat := plane.ConvexHull()
+	if flat.VertexNumber() != 4 || flat.FaceNumber() != 2 {
+		t.Fatalf("Expected the 4 corners and 2 faces, got %d and %d", flat.VertexNumber(), flat.FaceNumber())
+	}
+	if math.Abs(flat.SurfaceArea()-8) > 1e-9 {
+		t.Errorf("Expected the area 8 of the grid, got %v", flat.SurfaceArea())
+	}
+	first, _ := flat.Normal(0)
+	second, _ := flat.Normal(1)
+	if first.Dot(second) < 1-1e-9 {
+		t.Errorf("Expected both faces to point the same way, got %v and %v", first, second)
+	}
+}
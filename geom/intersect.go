@@ -0,0 +1,77 @@
+// intersect.go
+package geom
+
+import "sort"
+
+// Hit describes where a ray meets a mesh face
+type Hit struct {
+	Face     int
+	Distance float64
+	Point    Vertex
+	// Barycentric weights of the face's three vertices at the hit point
+	Barycentric [3]float64
+	// Normal is interpolated from vertex normals when the mesh has them, otherwise the face normal
+	Normal Vector
+	// FrontFacing is true when the ray arrives from the side the face normal points to
+	FrontFacing bool
+}
+
+// Intersect returns the nearest hit of the ray with the mesh. Faces are tested
+// from both sides; ties in distance go to the lower face index.
+func (m *Mesh) Intersect(ray Ray) (Hit, bool) {
+	var nearest Hit
+	found := false
+	for f := range m.myFaces {
+		hit, ok := m.intersectFace(ray, f)
+		if ok && (!found || hit.Distance < nearest.Distance) {
+			nearest = hit
+			found = true
+		}
+	}
+	return nearest, found
+}
+
+// IntersectAll returns every hit of the ray with the mesh, ordered by distance and then face index
+func (m *Mesh) IntersectAll(ray Ray) []Hit {
+	var hits []Hit
+	for f := range m.myFaces {
+		if hit, ok := m.intersectFace(ray, f); ok {
+			hits = append(hits, hit)
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Distance < hits[j].Distance
+	})
+	return hits
+}
+
+func (m *Mesh) intersectFace(ray Ray, faceIndex int) (Hit, bool) {
+	face := m.myFaces[faceIndex]
+	v0, v1, v2 := m.faceVertices(face)
+	distance, u, v, ok := ray.IntersectTriangle(v0, v1, v2)
+	if !ok {
+		return Hit{}, false
+	}
+
+	hit := Hit{
+		Face:        faceIndex,
+		Distance:    distance,
+		Point:       ray.At(distance),
+		Barycentric: [3]float64{1 - u - v, u, v},
+		Normal:      face.myNormal,
+	}
+	if m.myNormals != nil {
+		var normal Vector
+		for k, index := range face.myVertexIndices {
+			weighted := m.myNormals[index]
+			weighted.Scale(hit.Barycentric[k])
+			normal.Add(weighted)
+		}
+		if normal.Length() > 0 {
+			normal.Normalize()
+			hit.Normal = normal
+		}
+	}
+	hit.FrontFacing = ray.myDirection.Dot(face.myNormal) < 0
+	return hit, true
+}
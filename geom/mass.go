@@ -0,0 +1,178 @@
+// mass.go
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// MassProperties describes the solid enclosed by a closed, consistently wound
+// mesh with unit density, so the mass equals the volume
+type MassProperties struct {
+	Volume      float64
+	SurfaceArea float64
+	Centroid    Vertex
+	// Inertia is the inertia tensor about the centroid
+	Inertia [3][3]float64
+}
+
+// SurfaceArea returns the total area of all faces
+func (m *Mesh) SurfaceArea() float64 {
+	area := 0.0
+	for _, face := range m.myFaces {
+		v1, v2, v3 := m.faceVertices(face)
+		side1 := NewVectorFromVertices(v1, v2)
+		side2 := NewVectorFromVertices(v1, v3)
+		area += side1.Cross(side2).Length() / 2
+	}
+	return area
+}
+
+// SignedVolume returns the volume enclosed by the mesh. It is positive for
+// closed meshes with outward-facing winding and negative for inward winding;
+// for open meshes the value depends on the center of the bounding box.
+func (m *Mesh) SignedVolume() float64 {
+	center := m.volumeReference()
+	volume := 0.0
+	for _, face := range m.myFaces {
+		v1, v2, v3 := m.faceVertices(face)
+		a := Vector{v1.myCoords.Subtracted(center)}
+		b := Vector{v2.myCoords.Subtracted(center)}
+		c := Vector{v3.myCoords.Subtracted(center)}
+		volume += a.Dot(b.Cross(c)) / 6
+	}
+	return volume
+}
+
+// volumeReference returns the center of the bounding box. Volumes are summed
+// about it so that meshes far from the origin keep their precision.
+func (m *Mesh) volumeReference() Coords3d {
+	bounds := m.BoundingBox()
+	if bounds.IsEmpty() {
+		return Coords3d{}
+	}
+	return bounds.Center().myCoords
+}
+
+// Centroid returns the center of mass of the enclosed solid. For meshes that
+// enclose no volume, e.g. a single sheet, the area-weighted center of the
+// surface is returned; a mesh without faces reports the mean of its vertices.
+func (m *Mesh) Centroid() Vertex {
+	if properties, err := m.MassProperties(); err == nil {
+		return properties.Centroid
+	}
+
+	var sum Coords3d
+	weight := 0.0
+	for _, face := range m.myFaces {
+		v1, v2, v3 := m.faceVertices(face)
+		side1 := NewVectorFromVertices(v1, v2)
+		side2 := NewVectorFromVertices(v1, v3)
+		area := side1.Cross(side2).Length() / 2
+		sum.X += area * (v1.myCoords.X + v2.myCoords.X + v3.myCoords.X) / 3
+		sum.Y += area * (v1.myCoords.Y + v2.myCoords.Y + v3.myCoords.Y) / 3
+		sum.Z += area * (v1.myCoords.Z + v2.myCoords.Z + v3.myCoords.Z) / 3
+		weight += area
+	}
+	if weight == 0 {
+		for _, v := range m.myVertices {
+			sum.Add(v.myCoords)
+		}
+		weight = float64(len(m.myVertices))
+	}
+	if weight == 0 {
+		return Vertex{}
+	}
+	sum.Scale(1 / weight)
+	return Vertex{sum}
+}
+
+// MassProperties integrates volume, centroid and inertia tensor over the solid
+// bounded by the mesh (Eberly, "Polyhedral Mass Properties"). The mesh must be
+// closed with consistent winding; inward winding yields a negative volume and
+// an error is returned when the mesh encloses no volume relative to the cube
+// of its bounding box diagonal.
+func (m *Mesh) MassProperties() (MassProperties, error) {
+	// Integrals of 1, x, y, z, x², y², z², xy, yz, zx over the volume, taken
+	// relative to the reference point
+	var integrals [10]float64
+	center := m.volumeReference()
+
+	for _, face := range m.myFaces {
+		v0, v1, v2 := m.faceVertices(face)
+		p0, p1, p2 := v0.myCoords.Subtracted(center), v1.myCoords.Subtracted(center), v2.myCoords.Subtracted(center)
+		x0, y0, z0 := p0.X, p0.Y, p0.Z
+		x1, y1, z1 := p1.X, p1.Y, p1.Z
+		x2, y2, z2 := p2.X, p2.Y, p2.Z
+
+		// Unnormalized face normal
+		a1, b1, c1 := x1-x0, y1-y0, z1-z0
+		a2, b2, c2 := x2-x0, y2-y0, z2-z0
+		d0 := b1*c2 - b2*c1
+		d1 := a2*c1 - a1*c2
+		d2 := a1*b2 - a2*b1
+
+		f1x, f2x, f3x, g0x, g1x, g2x := massSubexpressions(x0, x1, x2)
+		_, f2y, f3y, g0y, g1y, g2y := massSubexpressions(y0, y1, y2)
+		_, f2z, f3z, g0z, g1z, g2z := massSubexpressions(z0, z1, z2)
+
+		integrals[0] += d0 * f1x
+		integrals[1] += d0 * f2x
+		integrals[2] += d1 * f2y
+		integrals[3] += d2 * f2z
+		integrals[4] += d0 * f3x
+		integrals[5] += d1 * f3y
+		integrals[6] += d2 * f3z
+		integrals[7] += d0 * (y0*g0x + y1*g1x + y2*g2x)
+		integrals[8] += d1 * (z0*g0y + z1*g1y + z2*g2y)
+		integrals[9] += d2 * (x0*g0z + x1*g1z + x2*g2z)
+	}
+
+	multipliers := [10]float64{1.0 / 6, 1.0 / 24, 1.0 / 24, 1.0 / 24, 1.0 / 60, 1.0 / 60, 1.0 / 60, 1.0 / 120, 1.0 / 120, 1.0 / 120}
+	for i := range integrals {
+		integrals[i] *= multipliers[i]
+	}
+
+	volume := integrals[0]
+	diagonal := m.BoundingBox().Size().Length()
+	if math.Abs(volume) <= DefaultTolerance*diagonal*diagonal*diagonal {
+		return MassProperties{}, fmt.Errorf("mesh encloses no volume (%g)", volume)
+	}
+
+	cx, cy, cz := integrals[1]/volume, integrals[2]/volume, integrals[3]/volume
+
+	xx := integrals[5] + integrals[6] - volume*(cy*cy+cz*cz)
+	yy := integrals[4] + integrals[6] - volume*(cz*cz+cx*cx)
+	zz := integrals[4] + integrals[5] - volume*(cx*cx+cy*cy)
+	xy := -(integrals[7] - volume*cx*cy)
+	yz := -(integrals[8] - volume*cy*cz)
+	zx := -(integrals[9] - volume*cz*cx)
+
+	return MassProperties{
+		Volume:      volume,
+		SurfaceArea: m.SurfaceArea(),
+		Centroid:    NewVertex(center.X+cx, center.Y+cy, center.Z+cz),
+		Inertia: [3][3]float64{
+			{xx, xy, zx},
+			{xy, yy, yz},
+			{zx, yz, zz},
+		},
+	}, nil
+}
+
+func massSubexpressions(w0, w1, w2 float64) (f1, f2, f3, g0, g1, g2 float64) {
+	temp0 := w0 + w1
+	f1 = temp0 + w2
+	temp1 := w0 * w0
+	temp2 := temp1 + w1*temp0
+	f2 = temp2 + w2*f1
+	f3 = w0*temp1 + w1*temp2 + w2*f2
+	g0 = f2 + w0*(f1+w0)
+	g1 = f2 + w1*(f1+w1)
+	g2 = f2 + w2*(f1+w2)
+	return
+}
+
+func (m *Mesh) faceVertices(face Triangle) (Vertex, Vertex, Vertex) {
+	return m.myVertices[face.myVertexIndices[0]], m.myVertices[face.myVertexIndices[1]], m.myVertices[face.myVertexIndices[2]]
+}
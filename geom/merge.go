@@ -0,0 +1,86 @@
+// merge.go
+package geom
+
+// MergeMeshes combines meshes into a new one without welding vertices.
+// Per-vertex normals, UVs, colors and attributes are kept when any input has
+// them and zero-filled for the others; materials and groups are concatenated
+// and face references remapped.
+func MergeMeshes(meshes ...*Mesh) *Mesh {
+	result := &Mesh{}
+
+	hasNormals, hasUVs, hasColors := false, false, false
+	for _, m := range meshes {
+		hasNormals = hasNormals || m.myNormals != nil
+		hasUVs = hasUVs || m.myUVs != nil
+		hasColors = hasColors || m.myColors != nil
+	}
+
+	for _, m := range meshes {
+		vertexOffset := len(result.myVertices)
+		materialOffset := len(result.myMaterials)
+
+		result.myVertices = append(result.myVertices, m.myVertices...)
+		if hasNormals {
+			result.myNormals = appendOrZero(result.myNormals, m.myNormals, len(m.myVertices))
+		}
+		if hasUVs {
+			result.myUVs = appendOrZero(result.myUVs, m.myUVs, len(m.myVertices))
+		}
+		if hasColors {
+			result.myColors = appendOrZero(result.myColors, m.myColors, len(m.myVertices))
+		}
+		mergeAttributes(result, m, vertexOffset)
+
+		result.myMaterials = append(result.myMaterials, m.myMaterials...)
+		groups := make([]int, len(m.myGroups))
+		for i, name := range m.myGroups {
+			groups[i] = result.AddGroup(name)
+		}
+
+		for _, face := range m.myFaces {
+			for k := range face.myVertexIndices {
+				face.myVertexIndices[k] += vertexOffset
+			}
+			if face.myMaterial != NoMaterial {
+				face.myMaterial += materialOffset
+			}
+			if face.myGroup != NoGroup {
+				face.myGroup = groups[face.myGroup]
+			}
+			result.myFaces = append(result.myFaces, face)
+		}
+	}
+
+	return result
+}
+
+func appendOrZero[T any](target, source []T, count int) []T {
+	if source != nil {
+		return append(target, source...)
+	}
+	return append(target, make([]T, count)...)
+}
+
+// mergeAttributes appends the attributes of source; attributes missing on either side are zero-filled
+func mergeAttributes(result, source *Mesh, vertexOffset int) {
+	for _, attribute := range source.myAttributes {
+		if _, ok := result.VertexAttribute(attribute.Name); !ok {
+			result.myAttributes = append(result.myAttributes, VertexAttribute{
+				Name:   attribute.Name,
+				Type:   attribute.Type,
+				Values: make([]float64, vertexOffset),
+			})
+		}
+	}
+
+	for i := range result.myAttributes {
+		target := &result.myAttributes[i]
+		values := make([]float64, len(source.myVertices))
+		for _, attribute := range source.myAttributes {
+			if attribute.Name == target.Name {
+				copy(values, attribute.Values)
+			}
+		}
+		target.Values = append(target.Values, values...)
+	}
+}
@@ -0,0 +1,50 @@
+package geom
+
+import "testing"
+
+func TestMergeMeshes(t *testing.T) {
+	cube := CreateCube(2)
+	red := NewMaterial("red")
+	_ = cube.SetFaceMaterial(0, cube.AddMaterial(red))
+	_ = cube.SetFaceGroup(1, cube.AddGroup("lid"))
+
+	tetra := CreateTetrahedron(1)
+	_ = tetra.SetVertexColor(0, NewColor(0, 1, 0, 1))
+	blue := NewMaterial("blue")
+	_ = tetra.SetFaceMaterial(2, tetra.AddMaterial(blue))
+	_ = tetra.SetFaceGroup(3, tetra.AddGroup("lid"))
+
+	merged := MergeMeshes(cube, tetra)
+
+	if merged.VertexNumber() != cube.VertexNumber()+tetra.VertexNumber() {
+		t.Fatalf("Expected %d vertices, got %d", cube.VertexNumber()+tetra.VertexNumber(), merged.VertexNumber())
+	}
+	if merged.FaceNumber() != cube.FaceNumber()+tetra.FaceNumber() {
+		t.Fatalf("Expected %d faces, got %d", cube.FaceNumber()+tetra.FaceNumber(), merged.FaceNumber())
+	}
+
+	offset := cube.FaceNumber()
+	for f := 0; f < tetra.FaceNumber(); f++ {
+		want, _ := tetra.Normal(f)
+		got, _ := merged.Normal(offset + f)
+		if !want.Equals(got) {
+			t.Errorf("Face %d: expected normal %v, got %v", f, want, got)
+		}
+	}
+
+	material, _ := merged.FaceMaterial(offset + 2)
+	if m, _ := merged.Material(material); m.Name != "blue" {
+		t.Errorf("Expected tetrahedron face to keep the blue material, got %q", m.Name)
+	}
+	if merged.GroupNumber() != 1 {
+		t.Errorf("Expected groups with the same name to be shared, got %d", merged.GroupNumber())
+	}
+
+	if !merged.HasVertexColors() {
+		t.Fatalf("Expected vertex colors to be kept")
+	}
+	color, _ := merged.VertexColor(cube.VertexNumber())
+	if !color.Equals(NewColor(0, 1, 0, 1)) {
+		t.Errorf("Expected green vertex color, got %v", color)
+	}
+}
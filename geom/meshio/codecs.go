@@ -0,0 +1,73 @@
+// codecs.go
+package meshio
+
+import (
+	"bytes"
+	"go4/geom"
+	"io"
+)
+
+func init() {
+	Register(Format{
+		Name:        "stl",
+		Description: "Stereolithography",
+		Extensions:  []string{".stl"},
+		Sniff:       sniffSTL,
+		Read:        geom.ReadSTL,
+		Write:       geom.WriteSTLBinary,
+	})
+	Register(Format{
+		Name:        "obj",
+		Description: "Wavefront OBJ",
+		Extensions:  []string{".obj"},
+		Sniff:       sniffOBJ,
+		Read:        geom.ReadOBJ,
+		Write: func(w io.Writer, m *geom.Mesh) error {
+			return geom.WriteOBJ(w, m, "")
+		},
+		Open: geom.LoadOBJ,
+		Save: geom.SaveOBJ,
+	})
+	Register(Format{
+		Name:        "ply",
+		Description: "Polygon File Format",
+		Extensions:  []string{".ply"},
+		Sniff:       sniffPLY,
+		ReadWithWarnings: func(r io.Reader) (*geom.Mesh, []string, error) {
+			mesh, warnings, err := geom.ReadPLYWithWarnings(r)
+			for i, warning := range warnings {
+				warnings[i] = "ply: " + warning
+			}
+			return mesh, warnings, err
+		},
+		Write: func(w io.Writer, m *geom.Mesh) error {
+			return geom.WritePLY(w, m, geom.PLYBinaryLittleEndian)
+		},
+	})
+}
+
+// sniffSTL only recognises ASCII STL; binary STL has no magic number and is
+// detected by extension
+func sniffSTL(header []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(header, " \t\r\n"), []byte("solid"))
+}
+
+func sniffPLY(header []byte) bool {
+	return bytes.HasPrefix(header, []byte("ply\n")) || bytes.HasPrefix(header, []byte("ply\r\n"))
+}
+
+// sniffOBJ accepts text whose first statement is a known OBJ keyword
+func sniffOBJ(header []byte) bool {
+	for _, line := range bytes.Split(header, []byte("\n")) {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 || fields[0][0] == '#' {
+			continue
+		}
+		switch string(fields[0]) {
+		case "v", "vt", "vn", "f", "o", "g", "s", "mtllib", "usemtl":
+			return true
+		}
+		return false
+	}
+	return false
+}
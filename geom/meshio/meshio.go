@@ -0,0 +1,353 @@
+// Package meshio is the single entry point for reading and writing mesh files.
+//
+// Codecs register a Format with the file extensions they handle and an optional
+// sniffer that recognises their content. Load and Save pick a format by file
+// extension, Read picks one by sniffing the stream, and Formats lists everything
+// that is registered for CLI help or file dialogs.
+//
+// STL, Wavefront OBJ and PLY are registered by this package. Other packages may
+// add codecs from an init function, e.g. the vis package registers glTF.
+package meshio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go4/geom"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SniffLength is the maximum number of leading bytes passed to Format.Sniff
+const SniffLength = 512
+
+// ErrUnknownFormat is returned when no registered format matches a path, name or stream
+var ErrUnknownFormat = errors.New("unknown mesh format")
+
+// ErrNotSupported is returned when a format has no decoder or encoder for the requested operation
+var ErrNotSupported = errors.New("operation not supported by mesh format")
+
+// Format describes a mesh codec
+type Format struct {
+	// Name identifies the format, e.g. "stl"
+	Name string
+	// Description is a human-readable name, e.g. "Wavefront OBJ"
+	Description string
+	// Extensions are the lower-case file extensions including the dot, e.g. ".obj"
+	Extensions []string
+	// Sniff reports whether the leading bytes of a stream (at most SniffLength)
+	// belong to this format. Formats without a magic number may leave it nil.
+	Sniff func(header []byte) bool
+
+	// Read decodes a mesh from a stream
+	Read func(r io.Reader) (*geom.Mesh, error)
+	// Write encodes a mesh to a stream; nil for read-only formats
+	Write func(w io.Writer, m *geom.Mesh) error
+
+	// Open and Save are optional file variants for formats that reference
+	// sibling files such as material libraries. Load and Save fall back to
+	// Read and Write when they are nil.
+	Open func(path string) (*geom.Mesh, error)
+	Save func(path string, m *geom.Mesh) error
+
+	// ReadWithWarnings and OpenWithWarnings are optional variants of Read and
+	// Open for formats that skip content they cannot represent, such as
+	// unsupported extensions. They return one message per skipped item and
+	// take precedence over Read and Open.
+	ReadWithWarnings func(r io.Reader) (*geom.Mesh, []string, error)
+	OpenWithWarnings func(path string) (*geom.Mesh, []string, error)
+}
+
+// CanRead reports whether the format can decode meshes
+func (f Format) CanRead() bool {
+	return f.Read != nil || f.Open != nil || f.ReadWithWarnings != nil || f.OpenWithWarnings != nil
+}
+
+// canReadStream reports whether the format can decode meshes from a stream
+func (f Format) canReadStream() bool {
+	return f.Read != nil || f.ReadWithWarnings != nil
+}
+
+// read decodes a mesh from a stream with the decoder the format has
+func (f Format) read(r io.Reader) (*geom.Mesh, []string, error) {
+	if f.ReadWithWarnings != nil {
+		return f.ReadWithWarnings(r)
+	}
+	mesh, err := f.Read(r)
+	return mesh, nil, err
+}
+
+// CanWrite reports whether the format can encode meshes
+func (f Format) CanWrite() bool {
+	return f.Write != nil || f.Save != nil
+}
+
+// String returns the description followed by the extensions, e.g. "Stereolithography (.stl)"
+func (f Format) String() string {
+	description := f.Description
+	if description == "" {
+		description = f.Name
+	}
+	return fmt.Sprintf("%s (%s)", description, strings.Join(f.Extensions, ", "))
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      []Format
+)
+
+// Register adds a format to the registry. It panics if the name is empty or
+// already registered, or if an extension is claimed by another format.
+func Register(format Format) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if format.Name == "" {
+		panic("meshio: Register called with an empty format name")
+	}
+	for i, extension := range format.Extensions {
+		format.Extensions[i] = strings.ToLower(extension)
+	}
+	for _, existing := range registry {
+		if existing.Name == format.Name {
+			panic("meshio: Register called twice for format " + format.Name)
+		}
+		for _, extension := range format.Extensions {
+			if existing.handlesExtension(extension) {
+				panic(fmt.Sprintf("meshio: extension %s of format %s is already registered by %s", extension, format.Name, existing.Name))
+			}
+		}
+	}
+
+	registry = append(registry, format)
+}
+
+// Formats returns the registered formats sorted by name
+func Formats() []Format {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	formats := make([]Format, len(registry))
+	copy(formats, registry)
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].Name < formats[j].Name
+	})
+	return formats
+}
+
+// Extensions returns all registered extensions, sorted; useful for file dialog filters
+func Extensions() []string {
+	var extensions []string
+	for _, format := range Formats() {
+		extensions = append(extensions, format.Extensions...)
+	}
+	sort.Strings(extensions)
+	return extensions
+}
+
+// Lookup returns the format registered under name
+func Lookup(name string) (Format, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	for _, format := range registry {
+		if format.Name == name {
+			return format, true
+		}
+	}
+	return Format{}, false
+}
+
+// FormatForPath returns the format registered for the extension of path
+func FormatForPath(path string) (Format, bool) {
+	extension := strings.ToLower(filepath.Ext(path))
+	if extension == "" {
+		return Format{}, false
+	}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	for _, format := range registry {
+		if format.handlesExtension(extension) {
+			return format, true
+		}
+	}
+	return Format{}, false
+}
+
+// Detect returns the first format whose sniffer accepts the header
+func Detect(header []byte) (Format, bool) {
+	if len(header) > SniffLength {
+		header = header[:SniffLength]
+	}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	for _, format := range registry {
+		if format.Sniff != nil && format.Sniff(header) {
+			return format, true
+		}
+	}
+	return Format{}, false
+}
+
+func (f Format) handlesExtension(extension string) bool {
+	for _, candidate := range f.Extensions {
+		if candidate == extension {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the mesh file at path. The format is chosen by extension and, if
+// the extension is unknown, by sniffing the file content. Warnings about
+// skipped content are discarded; use LoadWithWarnings to report them.
+func Load(path string) (*geom.Mesh, error) {
+	mesh, _, err := LoadWithWarnings(path)
+	return mesh, err
+}
+
+// LoadWithWarnings reads the mesh file at path like Load and also returns
+// messages about content the format had to skip
+func LoadWithWarnings(path string) (*geom.Mesh, []string, error) {
+	format, ok := FormatForPath(path)
+	if !ok {
+		header, err := readHeader(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if format, ok = Detect(header); !ok {
+			return nil, nil, fmt.Errorf("%s: %w", path, ErrUnknownFormat)
+		}
+	}
+
+	if format.OpenWithWarnings != nil {
+		return format.OpenWithWarnings(path)
+	}
+	if format.Open != nil {
+		mesh, err := format.Open(path)
+		return mesh, nil, err
+	}
+	if !format.canReadStream() {
+		return nil, nil, fmt.Errorf("%s: reading %s: %w", path, format.Name, ErrNotSupported)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	mesh, warnings, err := format.read(bufio.NewReader(file))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return mesh, warnings, nil
+}
+
+func readHeader(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, SniffLength)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return header[:n], nil
+}
+
+// Save writes the mesh to path in the format registered for its extension
+func Save(path string, m *geom.Mesh) error {
+	format, ok := FormatForPath(path)
+	if !ok {
+		return fmt.Errorf("%s: %w", path, ErrUnknownFormat)
+	}
+
+	if format.Save != nil {
+		return format.Save(path, m)
+	}
+	if format.Write == nil {
+		return fmt.Errorf("%s: writing %s: %w", path, format.Name, ErrNotSupported)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	if err := format.Write(writer, m); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Read decodes a mesh from a stream whose format is detected by sniffing.
+// Warnings about skipped content are discarded; use ReadWithWarnings to
+// report them.
+func Read(r io.Reader) (*geom.Mesh, Format, error) {
+	mesh, format, _, err := ReadWithWarnings(r)
+	return mesh, format, err
+}
+
+// ReadWithWarnings decodes a mesh from a stream like Read and also returns
+// messages about content the format had to skip
+func ReadWithWarnings(r io.Reader) (*geom.Mesh, Format, []string, error) {
+	reader := bufio.NewReaderSize(r, SniffLength)
+	header, err := reader.Peek(SniffLength)
+	if err != nil && err != io.EOF {
+		return nil, Format{}, nil, err
+	}
+
+	format, ok := Detect(header)
+	if !ok {
+		return nil, Format{}, nil, ErrUnknownFormat
+	}
+	if !format.canReadStream() {
+		return nil, format, nil, fmt.Errorf("reading %s: %w", format.Name, ErrNotSupported)
+	}
+
+	mesh, warnings, err := format.read(reader)
+	return mesh, format, warnings, err
+}
+
+// ReadFormat decodes a mesh from a stream using the format registered under
+// name, discarding warnings about skipped content
+func ReadFormat(r io.Reader, name string) (*geom.Mesh, error) {
+	format, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnknownFormat)
+	}
+	if !format.canReadStream() {
+		return nil, fmt.Errorf("reading %s: %w", format.Name, ErrNotSupported)
+	}
+	mesh, _, err := format.read(r)
+	return mesh, err
+}
+
+// Write encodes the mesh to a stream using the format registered under name
+func Write(w io.Writer, m *geom.Mesh, name string) error {
+	format, ok := Lookup(name)
+	if !ok {
+		return fmt.Errorf("%s: %w", name, ErrUnknownFormat)
+	}
+	if format.Write == nil {
+		return fmt.Errorf("writing %s: %w", format.Name, ErrNotSupported)
+	}
+	return format.Write(w, m)
+}
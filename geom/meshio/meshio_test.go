@@ -0,0 +1,151 @@
+package meshio
+
+import (
+	"bytes"
+	"errors"
+	"go4/geom"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndLoad_ByExtension(t *testing.T) {
+	dir := t.TempDir()
+	cube := geom.CreateCube(2)
+
+	for _, name := range []string{"cube.stl", "cube.obj", "cube.PLY"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := Save(path, cube); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if loaded.FaceNumber() != cube.FaceNumber() {
+				t.Errorf("Expected %d faces, got %d", cube.FaceNumber(), loaded.FaceNumber())
+			}
+		})
+	}
+}
+
+func TestLoad_SniffsUnknownExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.dat")
+	var buf bytes.Buffer
+	if err := geom.WritePLY(&buf, geom.CreateTetrahedron(1), geom.PLYASCII); err != nil {
+		t.Fatalf("WritePLY failed: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	mesh, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if mesh.FaceNumber() != 4 {
+		t.Errorf("Expected 4 faces, got %d", mesh.FaceNumber())
+	}
+}
+
+func TestRead_DetectsFormat(t *testing.T) {
+	tetra := geom.CreateTetrahedron(1)
+
+	var stl, obj, ply bytes.Buffer
+	_ = geom.WriteSTLASCII(&stl, tetra, "tetra")
+	_ = geom.WriteOBJ(&obj, tetra, "")
+	_ = geom.WritePLY(&ply, tetra, geom.PLYBinaryBigEndian)
+
+	cases := []struct {
+		input    []byte
+		expected string
+	}{
+		{stl.Bytes(), "stl"},
+		{append([]byte("# exported\n\n"), obj.Bytes()...), "obj"},
+		{ply.Bytes(), "ply"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.expected, func(t *testing.T) {
+			mesh, format, err := Read(bytes.NewReader(test.input))
+			if err != nil {
+				t.Fatalf("Read failed: %v", err)
+			}
+			if format.Name != test.expected {
+				t.Errorf("Expected format %s, got %s", test.expected, format.Name)
+			}
+			if mesh.FaceNumber() != 4 {
+				t.Errorf("Expected 4 faces, got %d", mesh.FaceNumber())
+			}
+		})
+	}
+
+	if _, _, err := Read(strings.NewReader("not a mesh")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestWrite_ByName(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, geom.CreateCube(1), "ply"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "ply\n") {
+		t.Errorf("Expected PLY output, got %q", buf.String()[:10])
+	}
+	if err := Write(&buf, geom.CreateCube(1), "fbx"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+	if err := Save("cube.fbx", geom.CreateCube(1)); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestRegister_RejectsDuplicates(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for a duplicate extension")
+		}
+	}()
+	Register(Format{Name: "stl-copy", Extensions: []string{".STL"}})
+}
+
+func TestLoadWithWarnings_PassesCodecWarnings(t *testing.T) {
+	Register(Format{
+		Name:       "warning-test",
+		Extensions: []string{".warn"},
+		ReadWithWarnings: func(r io.Reader) (*geom.Mesh, []string, error) {
+			return geom.CreateTetrahedron(1), []string{"skipped a feature"}, nil
+		},
+	})
+	path := filepath.Join(t.TempDir(), "model.warn")
+	if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	mesh, warnings, err := LoadWithWarnings(path)
+	if err != nil {
+		t.Fatalf("LoadWithWarnings failed: %v", err)
+	}
+	if mesh.FaceNumber() != 4 || len(warnings) != 1 || warnings[0] != "skipped a feature" {
+		t.Errorf("Expected the mesh and its warning, got %d faces and %v", mesh.FaceNumber(), warnings)
+	}
+	if _, err := Load(path); err != nil {
+		t.Errorf("Expected Load to read formats with warnings, got %v", err)
+	}
+}
+
+func TestFormats_ListsBuiltins(t *testing.T) {
+	names := map[string]bool{}
+	for _, format := range Formats() {
+		names[format.Name] = format.CanRead() && format.CanWrite()
+	}
+	for _, name := range []string{"obj", "ply", "stl"} {
+		if !names[name] {
+			t.Errorf("Expected readable and writable format %s to be registered", name)
+		}
+	}
+}
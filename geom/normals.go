@@ -0,0 +1,215 @@
+// normals.go
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// NormalWeighting selects how face normals contribute to a vertex normal
+type NormalWeighting int
+
+const (
+	// WeightAngle weights each face by its interior angle at the vertex
+	WeightAngle NormalWeighting = iota
+	// WeightArea weights each face by its area
+	WeightArea
+	// WeightUniform gives every face the same weight
+	WeightUniform
+)
+
+// String returns the string representation of the weighting
+func (w NormalWeighting) String() string {
+	switch w {
+	case WeightAngle:
+		return "angle"
+	case WeightArea:
+		return "area"
+	case WeightUniform:
+		return "uniform"
+	default:
+		return "unknown"
+	}
+}
+
+// VertexNormalConfig controls Mesh.ComputeVertexNormals
+type VertexNormalConfig struct {
+	Weighting NormalWeighting
+	// Faces around a vertex whose normals differ by more than this angle in
+	// radians are shaded separately; math.Pi keeps every vertex smooth
+	CreaseAngle float64
+}
+
+func DefaultVertexNormalConfig() VertexNormalConfig {
+	return VertexNormalConfig{
+		Weighting:   WeightAngle,
+		CreaseAngle: math.Pi / 6,
+	}
+}
+
+// ComputeVertexNormals replaces the vertex normals by weighted averages of the
+// adjacent face normals. Around each vertex, faces sharing an edge at the
+// vertex are smoothed together unless their normals differ by more than the
+// crease angle. Every further group of faces gets its own copy of the vertex,
+// with the same UV, color and attributes. It returns the number of vertices added.
+func (m *Mesh) ComputeVertexNormals(config VertexNormalConfig) (int, error) {
+	if config.Weighting < WeightAngle || config.Weighting > WeightUniform {
+		return 0, fmt.Errorf("unknown normal weighting %d", config.Weighting)
+	}
+	if !(config.CreaseAngle >= 0) {
+		return 0, fmt.Errorf("crease angle must be a non-negative number, got %v", config.CreaseAngle)
+	}
+
+	faceNormals := make([]Vector, len(m.myFaces))
+	vertexFaces := make([][]int, len(m.myVertices))
+	for f, face := range m.myFaces {
+		v1, v2, v3 := m.faceVertices(face)
+		side1 := NewVectorFromVertices(v1, v2)
+		side2 := NewVectorFromVertices(v1, v3)
+		faceNormals[f] = side1.Cross(side2)
+		for _, v := range uniqueIndices(face.myVertexIndices) {
+			vertexFaces[v] = append(vertexFaces[v], f)
+		}
+	}
+
+	threshold := math.Cos(min(config.CreaseAngle, math.Pi))
+	smooth := func(f, g int) bool {
+		a, b := faceNormals[f], faceNormals[g]
+		lengths := a.Length() * b.Length()
+		// Degenerate faces join their neighbours rather than splitting vertices
+		return lengths == 0 || a.Dot(b) >= threshold*lengths
+	}
+
+	normals := make([]Vector, len(m.myVertices))
+	added := 0
+	vertexNumber := len(m.myVertices)
+	for v := 0; v < vertexNumber; v++ {
+		faces := vertexFaces[v]
+		if len(faces) == 0 {
+			continue
+		}
+
+		groups := smoothingGroups(m, v, faces, smooth)
+		for i, group := range groups {
+			target := v
+			if i > 0 {
+				target = m.duplicateVertex(v)
+				normals = append(normals, Vector{})
+				added++
+				for _, f := range group {
+					for k, index := range m.myFaces[f].myVertexIndices {
+						if index == v {
+							m.myFaces[f].myVertexIndices[k] = target
+						}
+					}
+				}
+			}
+
+			var sum Vector
+			for _, f := range group {
+				normal := faceNormals[f]
+				weight := cornerWeight(m, f, target, config.Weighting)
+				normal.Normalize()
+				normal.Scale(weight)
+				sum.Add(normal)
+			}
+			sum.Normalize()
+			normals[target] = sum
+		}
+	}
+
+	m.myNormals = normals
+	return added, nil
+}
+
+// smoothingGroups partitions the faces around vertex v into groups connected
+// through shared edges at v with smooth transitions, ordered by their lowest face
+func smoothingGroups(m *Mesh, v int, faces []int, smooth func(f, g int) bool) [][]int {
+	parent := make([]int, len(faces))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	// Faces sharing an edge (v, w) are candidates for smoothing
+	byNeighbor := make(map[int][]int)
+	for i, f := range faces {
+		for _, w := range m.myFaces[f].myVertexIndices {
+			if w != v {
+				byNeighbor[w] = append(byNeighbor[w], i)
+			}
+		}
+	}
+	for _, shared := range byNeighbor {
+		for a := 0; a < len(shared); a++ {
+			for b := a + 1; b < len(shared); b++ {
+				if smooth(faces[shared[a]], faces[shared[b]]) {
+					parent[find(shared[a])] = find(shared[b])
+				}
+			}
+		}
+	}
+
+	var groups [][]int
+	groupOf := make(map[int]int)
+	for i, f := range faces {
+		root := find(i)
+		index, ok := groupOf[root]
+		if !ok {
+			index = len(groups)
+			groupOf[root] = index
+			groups = append(groups, nil)
+		}
+		groups[index] = append(groups[index], f)
+	}
+	return groups
+}
+
+// cornerWeight returns the contribution of face f to the normal of vertex v
+func cornerWeight(m *Mesh, f, v int, weighting NormalWeighting) float64 {
+	face := m.myFaces[f]
+	switch weighting {
+	case WeightArea:
+		v1, v2, v3 := m.faceVertices(face)
+		side1 := NewVectorFromVertices(v1, v2)
+		side2 := NewVectorFromVertices(v1, v3)
+		return side1.Cross(side2).Length() / 2
+	case WeightUniform:
+		return 1
+	}
+
+	for k, index := range face.myVertexIndices {
+		if index != v {
+			continue
+		}
+		corner := m.myVertices[index]
+		a := NewVectorFromVertices(corner, m.myVertices[face.myVertexIndices[(k+1)%3]])
+		b := NewVectorFromVertices(corner, m.myVertices[face.myVertexIndices[(k+2)%3]])
+		return math.Atan2(a.Cross(b).Length(), a.Dot(b))
+	}
+	return 0
+}
+
+// duplicateVertex appends a copy of vertex v with all its per-vertex data
+func (m *Mesh) duplicateVertex(v int) int {
+	m.myVertices = append(m.myVertices, m.myVertices[v])
+	if m.myNormals != nil {
+		m.myNormals = append(m.myNormals, m.myNormals[v])
+	}
+	if m.myUVs != nil {
+		m.myUVs = append(m.myUVs, m.myUVs[v])
+	}
+	if m.myColors != nil {
+		m.myColors = append(m.myColors, m.myColors[v])
+	}
+	for i := range m.myAttributes {
+		m.myAttributes[i].Values = append(m.myAttributes[i].Values, m.myAttributes[i].Values[v])
+	}
+	return len(m.myVertices) - 1
+}
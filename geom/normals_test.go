@@ -0,0 +1,119 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeVertexNormals_SmoothCube(t *testing.T) {
+	cube := CreateCube(2)
+	config := VertexNormalConfig{Weighting: WeightAngle, CreaseAngle: math.Pi}
+
+	added, err := cube.ComputeVertexNormals(config)
+	if err != nil {
+		t.Fatalf("ComputeVertexNormals failed: %v", err)
+	}
+	if added != 0 || !cube.HasVertexNormals() {
+		t.Fatalf("Expected smooth normals without splits, got %d added", added)
+	}
+
+	// Angle weighting gives every side of a corner the same 90 degrees
+	for v := 0; v < cube.VertexNumber(); v++ {
+		vertex, _ := cube.Vertex(v)
+		expected := NewVectorFromVertex(vertex)
+		expected.Normalize()
+		normal, _ := cube.VertexNormal(v)
+		assertVectorNear(t, "corner normal", expected, normal)
+	}
+}
+
+func TestComputeVertexNormals_CreaseSplitsCube(t *testing.T) {
+	cube := CreateCube(2)
+	_ = cube.SetVertexUV(0, NewVertex2d(0.25, 0.75))
+
+	added, err := cube.ComputeVertexNormals(DefaultVertexNormalConfig())
+	if err != nil {
+		t.Fatalf("ComputeVertexNormals failed: %v", err)
+	}
+	if added != 16 || cube.VertexNumber() != 24 {
+		t.Fatalf("Expected every corner split in three, got %d added and %d vertices", added, cube.VertexNumber())
+	}
+
+	// Every face corner uses a vertex whose normal is the face normal
+	for f := 0; f < cube.FaceNumber(); f++ {
+		faceNormal, _ := cube.Normal(f)
+		indices, _ := cube.FaceIndices(f)
+		for _, v := range indices {
+			normal, _ := cube.VertexNormal(v)
+			assertVectorNear(t, "face corner normal", faceNormal, normal)
+		}
+	}
+
+	// Copies of a split vertex keep its position and UV
+	corner, _ := cube.Vertex(0)
+	copies := 0
+	for v := 0; v < cube.VertexNumber(); v++ {
+		vertex, _ := cube.Vertex(v)
+		if vertex.myCoords.Equals(corner.myCoords) {
+			copies++
+			if uv, _ := cube.VertexUV(v); uv != NewVertex2d(0.25, 0.75) {
+				t.Errorf("Vertex %d: expected the UV of vertex 0, got %v", v, uv)
+			}
+		}
+	}
+	if copies != 3 {
+		t.Errorf("Expected 3 copies of vertex 0, got %d", copies)
+	}
+	if report := cube.Validate(); report.IsWatertight() {
+		t.Errorf("Expected split vertices to open the cube topologically")
+	}
+}
+
+func TestComputeVertexNormals_Weighting(t *testing.T) {
+	for _, weighting := range []NormalWeighting{WeightAngle, WeightArea, WeightUniform} {
+		grid := createWavyGrid(8)
+		flat := createGrid(4)
+		for _, mesh := range []*Mesh{grid, flat} {
+			config := VertexNormalConfig{Weighting: weighting, CreaseAngle: math.Pi}
+			if _, err := mesh.ComputeVertexNormals(config); err != nil {
+				t.Fatalf("%v: ComputeVertexNormals failed: %v", weighting, err)
+			}
+			for v := 0; v < mesh.VertexNumber(); v++ {
+				normal, _ := mesh.VertexNormal(v)
+				if math.Abs(normal.Length()-1) > 1e-9 || normal.Z() <= 0 {
+					t.Fatalf("%v: vertex %d has normal %v", weighting, v, normal)
+				}
+			}
+		}
+		for v := 0; v < flat.VertexNumber(); v++ {
+			normal, _ := flat.VertexNormal(v)
+			assertVectorNear(t, weighting.String(), NewVector(0, 0, 1), normal)
+		}
+	}
+
+	// Area weighting favours the larger face at a shared vertex
+	mesh := &Mesh{}
+	for _, v := range []Vertex{NewVertex(0, 0, 0), NewVertex(1, 0, 0), NewVertex(0, 1, 0), NewVertex(0, 0, 10)} {
+		mesh.AddVertex(v)
+	}
+	_, _ = mesh.AddFace(0, 1, 2)
+	_, _ = mesh.AddFace(0, 3, 1)
+	_, _ = mesh.ComputeVertexNormals(VertexNormalConfig{Weighting: WeightArea, CreaseAngle: math.Pi})
+	normal, _ := mesh.VertexNormal(0)
+	if math.Abs(normal.Y()) < 2*math.Abs(normal.Z()) {
+		t.Errorf("Expected the large face to dominate, got %v", normal)
+	}
+}
+
+func TestComputeVertexNormals_InvalidConfig(t *testing.T) {
+	cube := CreateCube(1)
+	if _, err := cube.ComputeVertexNormals(VertexNormalConfig{Weighting: NormalWeighting(7)}); err == nil {
+		t.Errorf("Expected an error for an unknown weighting")
+	}
+	if _, err := cube.ComputeVertexNormals(VertexNormalConfig{CreaseAngle: math.NaN()}); err == nil {
+		t.Errorf("Expected an error for a NaN crease angle")
+	}
+	if cube.HasVertexNormals() {
+		t.Errorf("Expected failed calls to leave the mesh unchanged")
+	}
+}
@@ -0,0 +1,461 @@
+// obj.go
+package geom
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	objFormatName = "obj"
+	mtlFormatName = "mtl"
+)
+
+// objCorner is one "v/vt/vn" reference of a face, zero-based; -1 marks a missing index
+type objCorner struct {
+	position, uv, normal int
+}
+
+// ReadOBJ decodes a Wavefront OBJ stream. Polygon faces are triangulated,
+// "o" and "g" statements start face groups and "usemtl" assigns materials by name.
+// Material libraries are not resolved, so materials only carry their names; use LoadOBJ for that.
+func ReadOBJ(r io.Reader) (*Mesh, error) {
+	mesh, _, err := readOBJ(r)
+	return mesh, err
+}
+
+// LoadOBJ reads an OBJ file and the MTL libraries it references.
+// Libraries that do not exist are skipped, leaving the materials with default values.
+func LoadOBJ(path string) (*Mesh, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mesh, libraries, err := readOBJ(file)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, library := range libraries {
+		materials, err := loadMTL(filepath.Join(filepath.Dir(path), library))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, material := range materials {
+			if index := mesh.MaterialIndex(material.Name); index != NoMaterial {
+				_ = mesh.SetMaterial(index, material)
+			}
+		}
+	}
+
+	return mesh, nil
+}
+
+func loadMTL(path string) ([]Material, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadMTL(file)
+}
+
+func readOBJ(r io.Reader) (*Mesh, []string, error) {
+	var positions []Vertex
+	var uvs []Vertex2d
+	var normals []Vector
+	var libraries []string
+
+	mesh := &Mesh{}
+	corners := make(map[objCorner]int)
+	material := NoMaterial
+	group := NoGroup
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+
+	parseFloats := func(fields []string, min, max int) ([]float64, error) {
+		if len(fields) < min || len(fields) > max {
+			return nil, malformedError(objFormatName, line, "expected %d to %d numbers, got %d", min, max, len(fields))
+		}
+		values := make([]float64, len(fields))
+		for i, field := range fields {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, malformedError(objFormatName, line, "invalid number %q", field)
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+	// resolveIndex converts a 1-based or negative (relative) OBJ index to a zero-based one
+	resolveIndex := func(field string, count int, kind string) (int, error) {
+		index, err := strconv.Atoi(field)
+		if err != nil {
+			return -1, malformedError(objFormatName, line, "invalid %s index %q", kind, field)
+		}
+		if index < 0 {
+			index += count
+		} else {
+			index--
+		}
+		if index < 0 || index >= count {
+			return -1, malformedError(objFormatName, line, "%s index %s out of range (%d defined)", kind, field, count)
+		}
+		return index, nil
+	}
+	parseCorner := func(field string) (objCorner, error) {
+		parts := strings.Split(field, "/")
+		if len(parts) > 3 {
+			return objCorner{}, malformedError(objFormatName, line, "invalid face vertex %q", field)
+		}
+
+		corner := objCorner{position: -1, uv: -1, normal: -1}
+		var err error
+		if corner.position, err = resolveIndex(parts[0], len(positions), "vertex"); err != nil {
+			return objCorner{}, err
+		}
+		if len(parts) > 1 && parts[1] != "" {
+			if corner.uv, err = resolveIndex(parts[1], len(uvs), "texture"); err != nil {
+				return objCorner{}, err
+			}
+		}
+		if len(parts) > 2 && parts[2] != "" {
+			if corner.normal, err = resolveIndex(parts[2], len(normals), "normal"); err != nil {
+				return objCorner{}, err
+			}
+		}
+		return corner, nil
+	}
+	// meshVertex returns the mesh vertex for a corner, splitting positions used with different UVs or normals
+	meshVertex := func(corner objCorner) int {
+		if index, ok := corners[corner]; ok {
+			return index
+		}
+		index := mesh.AddVertex(positions[corner.position])
+		if corner.uv >= 0 {
+			_ = mesh.SetVertexUV(index, uvs[corner.uv])
+		}
+		if corner.normal >= 0 {
+			_ = mesh.SetVertexNormal(index, normals[corner.normal])
+		}
+		corners[corner] = index
+		return index
+	}
+
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if comment := strings.IndexByte(text, '#'); comment >= 0 {
+			text = text[:comment]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "v":
+			// Trailing w or per-vertex colors are ignored
+			values, err := parseFloats(fields[1:], 3, 7)
+			if err != nil {
+				return nil, nil, err
+			}
+			positions = append(positions, NewVertex(values[0], values[1], values[2]))
+		case "vt":
+			values, err := parseFloats(fields[1:], 1, 3)
+			if err != nil {
+				return nil, nil, err
+			}
+			v := 0.0
+			if len(values) > 1 {
+				v = values[1]
+			}
+			uvs = append(uvs, NewVertex2d(values[0], v))
+		case "vn":
+			values, err := parseFloats(fields[1:], 3, 3)
+			if err != nil {
+				return nil, nil, err
+			}
+			normals = append(normals, NewVector(values[0], values[1], values[2]))
+		case "f":
+			if len(fields) < 4 {
+				return nil, nil, malformedError(objFormatName, line, "face needs at least 3 vertices, got %d", len(fields)-1)
+			}
+			polygon := make([]Vertex, len(fields)-1)
+			indices := make([]int, len(fields)-1)
+			for i, field := range fields[1:] {
+				corner, err := parseCorner(field)
+				if err != nil {
+					return nil, nil, err
+				}
+				indices[i] = meshVertex(corner)
+				polygon[i] = positions[corner.position]
+			}
+			for _, triangle := range TriangulatePolygon(polygon) {
+				face, err := mesh.AddFace(indices[triangle[0]], indices[triangle[1]], indices[triangle[2]])
+				if err != nil {
+					return nil, nil, err
+				}
+				mesh.myFaces[face].myMaterial = material
+				mesh.myFaces[face].myGroup = group
+			}
+		case "o", "g":
+			if len(fields) > 1 {
+				group = mesh.AddGroup(strings.Join(fields[1:], " "))
+			} else {
+				group = NoGroup
+			}
+		case "usemtl":
+			// A bare usemtl clears the current material
+			if len(fields) < 2 {
+				material = NoMaterial
+				continue
+			}
+			name := strings.Join(fields[1:], " ")
+			material = mesh.MaterialIndex(name)
+			if material == NoMaterial {
+				material = mesh.AddMaterial(NewMaterial(name))
+			}
+		case "mtllib":
+			libraries = append(libraries, fields[1:]...)
+		default:
+			// Smoothing groups, lines, points and free-form geometry are not supported
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return mesh, libraries, nil
+}
+
+// ReadMTL decodes a Wavefront material library.
+func ReadMTL(r io.Reader) ([]Material, error) {
+	var materials []Material
+	scanner := bufio.NewScanner(r)
+	line := 0
+
+	parseColor := func(fields []string) (Color, error) {
+		if len(fields) != 3 {
+			return Color{}, malformedError(mtlFormatName, line, "expected 3 color components, got %d", len(fields))
+		}
+		var values [3]float64
+		for i, field := range fields {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return Color{}, malformedError(mtlFormatName, line, "invalid number %q", field)
+			}
+			values[i] = value
+		}
+		return NewColor(values[0], values[1], values[2], 1), nil
+	}
+	parseScalar := func(fields []string) (float64, error) {
+		if len(fields) != 1 {
+			return 0, malformedError(mtlFormatName, line, "expected a single value, got %d", len(fields))
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, malformedError(mtlFormatName, line, "invalid number %q", fields[0])
+		}
+		return value, nil
+	}
+	current := func() (*Material, error) {
+		if len(materials) == 0 {
+			return nil, malformedError(mtlFormatName, line, "statement before newmtl")
+		}
+		return &materials[len(materials)-1], nil
+	}
+
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if comment := strings.IndexByte(text, '#'); comment >= 0 {
+			text = text[:comment]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "newmtl" {
+			if len(fields) < 2 {
+				return nil, malformedError(mtlFormatName, line, "newmtl needs a material name")
+			}
+			materials = append(materials, NewMaterial(strings.Join(fields[1:], " ")))
+			continue
+		}
+
+		var err error
+		var material *Material
+		switch fields[0] {
+		case "Ka", "Kd", "Ks", "Ns", "d", "Tr", "map_Kd":
+			if material, err = current(); err != nil {
+				return nil, err
+			}
+		default:
+			// Illumination models and other maps are not supported
+			continue
+		}
+
+		switch fields[0] {
+		case "Ka":
+			material.Ambient, err = parseColor(fields[1:])
+		case "Kd":
+			alpha := material.Diffuse.A
+			material.Diffuse, err = parseColor(fields[1:])
+			material.Diffuse.A = alpha
+		case "Ks":
+			material.Specular, err = parseColor(fields[1:])
+		case "Ns":
+			material.Shininess, err = parseScalar(fields[1:])
+		case "d":
+			material.Diffuse.A, err = parseScalar(fields[1:])
+		case "Tr":
+			var transparency float64
+			transparency, err = parseScalar(fields[1:])
+			material.Diffuse.A = 1 - transparency
+		case "map_Kd":
+			// Options before the file name are not supported; the last field is the path
+			material.DiffuseMap = fields[len(fields)-1]
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return materials, scanner.Err()
+}
+
+// WriteOBJ encodes the mesh as Wavefront OBJ. When the mesh has materials and
+// materialLibrary is not empty, an "mtllib" statement referencing it is written.
+func WriteOBJ(w io.Writer, m *Mesh, materialLibrary string) error {
+	buf := bufio.NewWriter(w)
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	if materialLibrary != "" && len(m.myMaterials) > 0 {
+		fmt.Fprintf(buf, "mtllib %s\n", materialLibrary)
+	}
+	for _, v := range m.myVertices {
+		fmt.Fprintf(buf, "v %s %s %s\n", format(v.X()), format(v.Y()), format(v.Z()))
+	}
+	for _, uv := range m.myUVs {
+		fmt.Fprintf(buf, "vt %s %s\n", format(uv.X()), format(uv.Y()))
+	}
+	for _, normal := range m.myNormals {
+		fmt.Fprintf(buf, "vn %s %s %s\n", format(normal.X()), format(normal.Y()), format(normal.Z()))
+	}
+
+	group, material := NoGroup, NoMaterial
+	for _, face := range m.myFaces {
+		if face.myGroup != group {
+			group = face.myGroup
+			if group == NoGroup {
+				fmt.Fprintf(buf, "g\n")
+			} else {
+				fmt.Fprintf(buf, "g %s\n", m.myGroups[group])
+			}
+		}
+		if face.myMaterial != material {
+			material = face.myMaterial
+			if material == NoMaterial {
+				fmt.Fprintf(buf, "usemtl\n")
+			} else {
+				fmt.Fprintf(buf, "usemtl %s\n", m.myMaterials[material].Name)
+			}
+		}
+
+		fmt.Fprintf(buf, "f")
+		for _, index := range face.myVertexIndices {
+			// Vertex, UV and normal arrays share the mesh vertex index
+			reference := strconv.Itoa(index + 1)
+			switch {
+			case m.myUVs != nil && m.myNormals != nil:
+				fmt.Fprintf(buf, " %s/%s/%s", reference, reference, reference)
+			case m.myUVs != nil:
+				fmt.Fprintf(buf, " %s/%s", reference, reference)
+			case m.myNormals != nil:
+				fmt.Fprintf(buf, " %s//%s", reference, reference)
+			default:
+				fmt.Fprintf(buf, " %s", reference)
+			}
+		}
+		fmt.Fprintf(buf, "\n")
+	}
+
+	return buf.Flush()
+}
+
+// WriteMTL encodes materials as a Wavefront material library.
+func WriteMTL(w io.Writer, materials []Material) error {
+	buf := bufio.NewWriter(w)
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	formatColor := func(c Color) string {
+		return format(c.R) + " " + format(c.G) + " " + format(c.B)
+	}
+
+	for i, material := range materials {
+		if i > 0 {
+			fmt.Fprintf(buf, "\n")
+		}
+		fmt.Fprintf(buf, "newmtl %s\n", material.Name)
+		fmt.Fprintf(buf, "Ka %s\n", formatColor(material.Ambient))
+		fmt.Fprintf(buf, "Kd %s\n", formatColor(material.Diffuse))
+		fmt.Fprintf(buf, "Ks %s\n", formatColor(material.Specular))
+		fmt.Fprintf(buf, "Ns %s\n", format(material.Shininess))
+		fmt.Fprintf(buf, "d %s\n", format(material.Diffuse.A))
+		if material.DiffuseMap != "" {
+			fmt.Fprintf(buf, "map_Kd %s\n", material.DiffuseMap)
+		}
+	}
+
+	return buf.Flush()
+}
+
+// SaveOBJ writes the mesh to path and, if it has materials, a sibling .mtl library.
+func SaveOBJ(path string, m *Mesh) error {
+	library := ""
+	if len(m.myMaterials) > 0 {
+		library = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + ".mtl"
+		if err := WriteFile(filepath.Join(filepath.Dir(path), library), func(w io.Writer) error {
+			return WriteMTL(w, m.myMaterials)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return WriteFile(path, func(w io.Writer) error {
+		return WriteOBJ(w, m, library)
+	})
+}
+
+// WriteFile creates the file at path and fills it by write. The file is
+// closed in any case; the first error is returned.
+func WriteFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
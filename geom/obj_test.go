@@ -0,0 +1,253 @@
+package geom
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func triangleArea(m *Mesh, faceIndex int) float64 {
+	v1, _ := m.VertexInFace(faceIndex, 0)
+	v2, _ := m.VertexInFace(faceIndex, 1)
+	v3, _ := m.VertexInFace(faceIndex, 2)
+	cross := NewVectorFromVertices(v1, v2).Cross(NewVectorFromVertices(v1, v3))
+	return cross.Length() / 2
+}
+
+func TestReadOBJ_TriangulatesPolygons(t *testing.T) {
+	// L-shaped hexagon: concave, area 3
+	input := `
+v 0 0 0
+v 2 0 0
+v 2 1 0
+v 1 1 0
+v 1 2 0
+v 0 2 0
+f 1 2 3 4 5 6
+`
+	mesh, err := ReadOBJ(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadOBJ failed: %v", err)
+	}
+	if mesh.FaceNumber() != 4 {
+		t.Fatalf("Expected 4 triangles, got %d", mesh.FaceNumber())
+	}
+
+	area := 0.0
+	for i := 0; i < mesh.FaceNumber(); i++ {
+		area += triangleArea(mesh, i)
+		normal, _ := mesh.Normal(i)
+		if !normal.Equals(NewVector(0, 0, 1)) {
+			t.Errorf("Face %d: expected winding to be kept, normal %v", i, normal)
+		}
+	}
+	if math.Abs(area-3) > 1e-9 {
+		t.Errorf("Expected triangulated area 3, got %f", area)
+	}
+}
+
+func TestReadOBJ_NegativeIndicesAndAttributes(t *testing.T) {
+	input := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+vn 0 0 1
+f -4/-4/-1 -3/-3/-1 -2/-2/-1 -1/-1/-1
+f 1/1/1 3/3/1 2/4/1
+`
+	mesh, err := ReadOBJ(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadOBJ failed: %v", err)
+	}
+	if mesh.FaceNumber() != 3 {
+		t.Errorf("Expected 3 faces, got %d", mesh.FaceNumber())
+	}
+	// Position 2 is used with two different UVs and must be split
+	if mesh.VertexNumber() != 5 {
+		t.Errorf("Expected 5 vertices after splitting, got %d", mesh.VertexNumber())
+	}
+	if !mesh.HasVertexUVs() || !mesh.HasVertexNormals() {
+		t.Fatalf("Expected UVs and normals to be loaded")
+	}
+
+	uv, _ := mesh.VertexUV(2)
+	if uv.X() != 1 || uv.Y() != 1 {
+		t.Errorf("Expected UV (1, 1) for vertex 2, got (%f, %f)", uv.X(), uv.Y())
+	}
+	normal, _ := mesh.VertexNormal(4)
+	if !normal.Equals(NewVector(0, 0, 1)) {
+		t.Errorf("Expected vertex normal (0, 0, 1), got %v", normal)
+	}
+}
+
+func TestReadOBJ_GroupsAndMaterials(t *testing.T) {
+	input := `
+mtllib parts.mtl
+v 0 0 0
+v 1 0 0
+v 0 1 0
+o part
+usemtl red
+f 1 2 3
+g lid
+usemtl blue
+f 1 3 2
+usemtl red
+f 2 3 1
+`
+	mesh, err := ReadOBJ(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadOBJ failed: %v", err)
+	}
+	if mesh.MaterialNumber() != 2 || mesh.GroupNumber() != 2 {
+		t.Fatalf("Expected 2 materials and 2 groups, got %d and %d", mesh.MaterialNumber(), mesh.GroupNumber())
+	}
+
+	expected := []struct {
+		material, group string
+	}{
+		{"red", "part"},
+		{"blue", "lid"},
+		{"red", "lid"},
+	}
+	for i, want := range expected {
+		materialIndex, _ := mesh.FaceMaterial(i)
+		material, _ := mesh.Material(materialIndex)
+		groupIndex, _ := mesh.FaceGroup(i)
+		group, _ := mesh.GroupName(groupIndex)
+		if material.Name != want.material || group != want.group {
+			t.Errorf("Face %d: expected %s/%s, got %s/%s", i, want.material, want.group, material.Name, group)
+		}
+	}
+}
+
+func TestReadOBJ_Errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+	}{
+		{"index out of range", "v 0 0 0\nv 1 0 0\nf 1 2 3\n"},
+		{"zero index", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 0 1 2\n"},
+		{"bad number", "v 0 x 0\n"},
+		{"face too small", "v 0 0 0\nv 1 0 0\nf 1 2\n"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadOBJ(strings.NewReader(test.input))
+			if !errors.Is(err, ErrMalformed) {
+				t.Errorf("Expected ErrMalformed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReadMTL(t *testing.T) {
+	input := `
+# two materials
+newmtl red
+Kd 1 0 0
+Ks 0.5 0.5 0.5
+Ns 32
+d 0.5
+
+newmtl textured
+map_Kd -bm 1 wood.png
+`
+	materials, err := ReadMTL(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadMTL failed: %v", err)
+	}
+	if len(materials) != 2 {
+		t.Fatalf("Expected 2 materials, got %d", len(materials))
+	}
+	if !materials[0].Diffuse.Equals(NewColor(1, 0, 0, 0.5)) {
+		t.Errorf("Unexpected diffuse color %v", materials[0].Diffuse)
+	}
+	if materials[0].Shininess != 32 {
+		t.Errorf("Expected shininess 32, got %f", materials[0].Shininess)
+	}
+	if materials[1].DiffuseMap != "wood.png" {
+		t.Errorf("Expected diffuse map wood.png, got %q", materials[1].DiffuseMap)
+	}
+
+	if _, err := ReadMTL(strings.NewReader("Kd 1 1 1\n")); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected ErrMalformed for statement before newmtl, got %v", err)
+	}
+}
+
+func TestOBJ_RoundTrip(t *testing.T) {
+	cube := CreateCube(2)
+	red := cube.AddMaterial(NewMaterial("red"))
+	group := cube.AddGroup("front")
+	for i := 0; i < 2; i++ {
+		_ = cube.SetFaceMaterial(i, red)
+		_ = cube.SetFaceGroup(i, group)
+	}
+	for i := 0; i < cube.VertexNumber(); i++ {
+		_ = cube.SetVertexUV(i, NewVertex2d(float64(i)/8, 0.5))
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOBJ(&buf, cube, "cube.mtl"); err != nil {
+		t.Fatalf("WriteOBJ failed: %v", err)
+	}
+
+	loaded, err := ReadOBJ(&buf)
+	if err != nil {
+		t.Fatalf("ReadOBJ failed: %v", err)
+	}
+	assertMeshesEqual(t, cube, loaded)
+
+	for i := 0; i < cube.FaceNumber(); i++ {
+		wantMaterial, _ := cube.FaceMaterial(i)
+		gotMaterial, _ := loaded.FaceMaterial(i)
+		wantGroup, _ := cube.FaceGroup(i)
+		gotGroup, _ := loaded.FaceGroup(i)
+		if wantMaterial != gotMaterial || wantGroup != gotGroup {
+			t.Errorf("Face %d: expected material/group %d/%d, got %d/%d", i, wantMaterial, wantGroup, gotMaterial, gotGroup)
+		}
+	}
+	uv, _ := loaded.VertexUV(3)
+	if uv.X() != 3.0/8 {
+		t.Errorf("Expected UV to survive round trip, got %f", uv.X())
+	}
+}
+
+func TestSaveAndLoadOBJ(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "part.obj")
+
+	tetra := CreateTetrahedron(1)
+	material := NewMaterial("steel")
+	material.Diffuse = NewColor(0.3, 0.4, 0.5, 1)
+	steel := tetra.AddMaterial(material)
+	for i := 0; i < tetra.FaceNumber(); i++ {
+		_ = tetra.SetFaceMaterial(i, steel)
+	}
+
+	if err := SaveOBJ(path, tetra); err != nil {
+		t.Fatalf("SaveOBJ failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "part.mtl")); err != nil {
+		t.Fatalf("Expected material library next to the OBJ file: %v", err)
+	}
+
+	loaded, err := LoadOBJ(path)
+	if err != nil {
+		t.Fatalf("LoadOBJ failed: %v", err)
+	}
+	loadedMaterial, _ := loaded.Material(0)
+	if !loadedMaterial.Diffuse.Equals(material.Diffuse) {
+		t.Errorf("Expected diffuse %v from library, got %v", material.Diffuse, loadedMaterial.Diffuse)
+	}
+}
@@ -0,0 +1,67 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPlane_Distance(t *testing.T) {
+	plane := NewPlane(NewVector(0, 0, 2), 4)
+	if math.Abs(plane.Offset()-2) > 1e-12 || math.Abs(plane.Normal().Z()-1) > 1e-12 {
+		t.Errorf("Expected the normalized plane z = 2, got normal %v and offset %f", plane.Normal(), plane.Offset())
+	}
+	if d := plane.Distance(NewVertex(5, -3, 7)); math.Abs(d-5) > 1e-12 {
+		t.Errorf("Expected distance 5, got %f", d)
+	}
+	if d := plane.Distance(NewVertex(0, 0, -1)); math.Abs(d+3) > 1e-12 {
+		t.Errorf("Expected distance -3, got %f", d)
+	}
+
+	through := NewPlaneThroughPoint(NewVertex(1, 2, 3), NewVector(1, 1, 0))
+	if d := through.Distance(NewVertex(2, 3, -8)); math.Abs(d-math.Sqrt2) > 1e-12 {
+		t.Errorf("Expected distance √2, got %f", d)
+	}
+
+	if d := NewPlane(Vector{}, 1).Distance(NewVertex(1, 2, 3)); d != 0 {
+		t.Errorf("Expected every point on a plane without normal, got distance %f", d)
+	}
+}
+
+func TestPlane_Transformed(t *testing.T) {
+	plane := NewPlaneThroughPoint(NewVertex(0, 0, 1), NewVector(0, 0, 1))
+	front, back := NewVertex(0.5, 2, 3), NewVertex(-1, 0, -2)
+
+	transforms := map[string]Matrix4{
+		"rotation":    NewRotationMatrix(NewVector(1, 1, 0), 0.8),
+		"translation": NewTranslationMatrix(NewVector(3, -1, 2)),
+		"scale":       NewScaleMatrix(2, 0.5, 3),
+		"mirror":      NewScaleMatrix(1, 1, -1),
+	}
+	for name, matrix := range transforms {
+		moved := plane.Transformed(matrix)
+		if d := moved.Distance(matrix.TransformVertex(NewVertex(4, -2, 1))); math.Abs(d) > 1e-9 {
+			t.Errorf("%s: expected points on the plane to stay on it, got distance %f", name, d)
+		}
+		if moved.Distance(matrix.TransformVertex(front)) <= 0 || moved.Distance(matrix.TransformVertex(back)) >= 0 {
+			t.Errorf("%s: expected the sides of the plane to be kept", name)
+		}
+	}
+}
+
+func TestPlane_IsAABBBehind(t *testing.T) {
+	plane := NewPlaneThroughPoint(NewVertex(0, 0, 0), NewVector(1, -1, 0))
+	cases := []struct {
+		box    AABB
+		behind bool
+	}{
+		{NewAABB(NewVertex(-3, 1, -1), NewVertex(-2, 2, 1)), true},
+		{NewAABB(NewVertex(-3, -1, -1), NewVertex(0.5, 2, 1)), false},
+		{NewAABB(NewVertex(1, -2, 0), NewVertex(2, -1, 1)), false},
+		{EmptyAABB(), true},
+	}
+	for i, c := range cases {
+		if got := plane.IsAABBBehind(c.box); got != c.behind {
+			t.Errorf("Case %d: expected behind %v, got %v", i, c.behind, got)
+		}
+	}
+}
@@ -0,0 +1,659 @@
+// ply.go
+package geom
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PLYFormat selects the body encoding of a PLY file
+type PLYFormat int
+
+const (
+	PLYASCII PLYFormat = iota
+	PLYBinaryLittleEndian
+	PLYBinaryBigEndian
+)
+
+// String returns the name used in the PLY "format" header line
+func (f PLYFormat) String() string {
+	switch f {
+	case PLYASCII:
+		return "ascii"
+	case PLYBinaryLittleEndian:
+		return "binary_little_endian"
+	case PLYBinaryBigEndian:
+		return "binary_big_endian"
+	default:
+		return "unknown"
+	}
+}
+
+const plyFormatName = "ply"
+
+var plyTypeNames = map[string]ScalarType{
+	"char": ScalarInt8, "int8": ScalarInt8,
+	"uchar": ScalarUint8, "uint8": ScalarUint8,
+	"short": ScalarInt16, "int16": ScalarInt16,
+	"ushort": ScalarUint16, "uint16": ScalarUint16,
+	"int": ScalarInt32, "int32": ScalarInt32,
+	"uint": ScalarUint32, "uint32": ScalarUint32,
+	"float": ScalarFloat32, "float32": ScalarFloat32,
+	"double": ScalarFloat64, "float64": ScalarFloat64,
+}
+
+func plyTypeName(t ScalarType) string {
+	switch t {
+	case ScalarInt8:
+		return "char"
+	case ScalarUint8:
+		return "uchar"
+	case ScalarInt16:
+		return "short"
+	case ScalarUint16:
+		return "ushort"
+	case ScalarInt32:
+		return "int"
+	case ScalarUint32:
+		return "uint"
+	case ScalarFloat32:
+		return "float"
+	default:
+		return "double"
+	}
+}
+
+func scalarSize(t ScalarType) int {
+	switch t {
+	case ScalarInt8, ScalarUint8:
+		return 1
+	case ScalarInt16, ScalarUint16:
+		return 2
+	case ScalarInt32, ScalarUint32, ScalarFloat32:
+		return 4
+	default:
+		return 8
+	}
+}
+
+type plyProperty struct {
+	name      string
+	valueType ScalarType
+	isList    bool
+	countType ScalarType
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+type plyHeader struct {
+	format   PLYFormat
+	elements []plyElement
+}
+
+// ReadPLY decodes an ASCII or binary PLY stream. Standard vertex properties
+// (x/y/z, nx/ny/nz, red/green/blue/alpha) map onto the mesh and remember their
+// types for WritePLY, other scalar vertex properties are kept as vertex
+// attributes, and face vertex_indices polygons are triangulated. Other
+// elements, other face properties and vertex list properties are skipped;
+// use ReadPLYWithWarnings to report them.
+func ReadPLY(r io.Reader) (*Mesh, error) {
+	mesh, _, err := ReadPLYWithWarnings(r)
+	return mesh, err
+}
+
+// ReadPLYWithWarnings decodes a PLY stream like ReadPLY and also returns one
+// message per skipped element or property
+func ReadPLYWithWarnings(r io.Reader) (*Mesh, []string, error) {
+	reader := bufio.NewReader(r)
+	header, err := readPLYHeader(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var values plyValueReader
+	switch header.format {
+	case PLYASCII:
+		scanner := bufio.NewScanner(reader)
+		scanner.Split(bufio.ScanWords)
+		values = &plyASCIIReader{scanner: scanner}
+	case PLYBinaryLittleEndian:
+		values = &plyBinaryReader{reader: reader, order: binary.LittleEndian}
+	default:
+		values = &plyBinaryReader{reader: reader, order: binary.BigEndian}
+	}
+
+	mesh := &Mesh{}
+	var warnings []string
+	for _, element := range header.elements {
+		switch element.name {
+		case "vertex":
+			err = readPLYVertices(mesh, element, values)
+			for _, property := range element.properties {
+				if property.isList {
+					warnings = append(warnings, fmt.Sprintf("vertex list property %q was skipped", property.name))
+				}
+			}
+		case "face":
+			var indices int
+			indices, err = readPLYFaces(mesh, element, values)
+			for i, property := range element.properties {
+				if i != indices {
+					warnings = append(warnings, fmt.Sprintf("face property %q was skipped", property.name))
+				}
+			}
+		default:
+			for i := 0; i < element.count && err == nil; i++ {
+				_, err = readPLYRow(element, values)
+			}
+			warnings = append(warnings, fmt.Sprintf("element %q with %d entries was skipped", element.name, element.count))
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return mesh, warnings, nil
+}
+
+func readPLYHeader(reader *bufio.Reader) (plyHeader, error) {
+	header := plyHeader{format: -1}
+	line := 0
+
+	for {
+		text, err := reader.ReadString('\n')
+		if err == io.EOF && text == "" {
+			return header, truncatedError(plyFormatName, line, "missing \"end_header\"")
+		}
+		if err != nil && err != io.EOF {
+			return header, err
+		}
+		line++
+
+		fields := strings.Fields(text)
+		if line == 1 {
+			if len(fields) != 1 || fields[0] != "ply" {
+				return header, malformedError(plyFormatName, line, "missing \"ply\" magic")
+			}
+			continue
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "format":
+			if len(fields) != 3 {
+				return header, malformedError(plyFormatName, line, "invalid format line")
+			}
+			switch fields[1] {
+			case "ascii":
+				header.format = PLYASCII
+			case "binary_little_endian":
+				header.format = PLYBinaryLittleEndian
+			case "binary_big_endian":
+				header.format = PLYBinaryBigEndian
+			default:
+				return header, malformedError(plyFormatName, line, "unknown format %q", fields[1])
+			}
+		case "comment", "obj_info":
+		case "element":
+			if len(fields) != 3 {
+				return header, malformedError(plyFormatName, line, "invalid element line")
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return header, malformedError(plyFormatName, line, "invalid element count %q", fields[2])
+			}
+			header.elements = append(header.elements, plyElement{name: fields[1], count: count})
+		case "property":
+			if len(header.elements) == 0 {
+				return header, malformedError(plyFormatName, line, "property before element")
+			}
+			property, err := parsePLYProperty(fields[1:])
+			if err != nil {
+				return header, malformedError(plyFormatName, line, "%v", err)
+			}
+			element := &header.elements[len(header.elements)-1]
+			element.properties = append(element.properties, property)
+		case "end_header":
+			if header.format < 0 {
+				return header, malformedError(plyFormatName, line, "missing format line")
+			}
+			return header, nil
+		default:
+			return header, malformedError(plyFormatName, line, "unexpected header keyword %q", fields[0])
+		}
+
+		if err == io.EOF {
+			return header, truncatedError(plyFormatName, line, "missing \"end_header\"")
+		}
+	}
+}
+
+func parsePLYProperty(fields []string) (plyProperty, error) {
+	if len(fields) == 4 && fields[0] == "list" {
+		countType, ok1 := plyTypeNames[fields[1]]
+		valueType, ok2 := plyTypeNames[fields[2]]
+		if !ok1 || !ok2 {
+			return plyProperty{}, fmt.Errorf("unknown list types %q %q", fields[1], fields[2])
+		}
+		return plyProperty{name: fields[3], valueType: valueType, isList: true, countType: countType}, nil
+	}
+	if len(fields) != 2 {
+		return plyProperty{}, fmt.Errorf("invalid property line")
+	}
+	valueType, ok := plyTypeNames[fields[0]]
+	if !ok {
+		return plyProperty{}, fmt.Errorf("unknown property type %q", fields[0])
+	}
+	return plyProperty{name: fields[1], valueType: valueType}, nil
+}
+
+// readPLYRow reads one element instance; list properties produce a slice, scalars a single value
+func readPLYRow(element plyElement, values plyValueReader) ([][]float64, error) {
+	row := make([][]float64, len(element.properties))
+	for i, property := range element.properties {
+		if !property.isList {
+			value, err := values.read(property.valueType)
+			if err != nil {
+				return nil, err
+			}
+			row[i] = []float64{value}
+			continue
+		}
+
+		count, err := values.read(property.countType)
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 || count > math.MaxInt32 || count != math.Trunc(count) {
+			return nil, malformedError(plyFormatName, 0, "invalid list length %v in %s.%s", count, element.name, property.name)
+		}
+		// The list grows as values are read, so a length the data cannot back
+		// ends with a truncated error instead of a huge allocation
+		var list []float64
+		for k := 0; k < int(count); k++ {
+			value, err := values.read(property.valueType)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		row[i] = list
+	}
+	return row, nil
+}
+
+func readPLYVertices(mesh *Mesh, element plyElement, values plyValueReader) error {
+	position := [3]int{-1, -1, -1}
+	normal := [3]int{-1, -1, -1}
+	color := [4]int{-1, -1, -1, -1}
+	var attributes []int
+
+	for i, property := range element.properties {
+		if property.isList {
+			continue
+		}
+		switch property.name {
+		case "x":
+			position[0] = i
+		case "y":
+			position[1] = i
+		case "z":
+			position[2] = i
+		case "nx":
+			normal[0] = i
+		case "ny":
+			normal[1] = i
+		case "nz":
+			normal[2] = i
+		case "red":
+			color[0] = i
+		case "green":
+			color[1] = i
+		case "blue":
+			color[2] = i
+		case "alpha":
+			color[3] = i
+		default:
+			attributes = append(attributes, i)
+		}
+	}
+	if position[0] < 0 || position[1] < 0 || position[2] < 0 {
+		return malformedError(plyFormatName, 0, "vertex element needs x, y and z properties")
+	}
+	hasNormals := normal[0] >= 0 && normal[1] >= 0 && normal[2] >= 0
+	hasColors := color[0] >= 0 && color[1] >= 0 && color[2] >= 0
+
+	storage := vertexStorage{position: element.properties[position[0]].valueType, normal: ScalarFloat32, color: ScalarUint8}
+	if hasNormals {
+		storage.normal = element.properties[normal[0]].valueType
+	}
+	if hasColors {
+		storage.color = element.properties[color[0]].valueType
+	}
+	mesh.myStorage = &storage
+
+	// Integer color channels span their full range, float channels are already in [0, 1]
+	colorChannel := func(row [][]float64, index int) float64 {
+		if index < 0 {
+			return 1
+		}
+		switch element.properties[index].valueType {
+		case ScalarUint8:
+			return row[index][0] / math.MaxUint8
+		case ScalarUint16:
+			return row[index][0] / math.MaxUint16
+		default:
+			return row[index][0]
+		}
+	}
+
+	first := mesh.VertexNumber()
+	attributeValues := make([][]float64, len(attributes))
+	for i := 0; i < element.count; i++ {
+		row, err := readPLYRow(element, values)
+		if err != nil {
+			return err
+		}
+
+		index := mesh.AddVertex(NewVertex(row[position[0]][0], row[position[1]][0], row[position[2]][0]))
+		if hasNormals {
+			_ = mesh.SetVertexNormal(index, NewVector(row[normal[0]][0], row[normal[1]][0], row[normal[2]][0]))
+		}
+		if hasColors {
+			_ = mesh.SetVertexColor(index, NewColor(
+				colorChannel(row, color[0]),
+				colorChannel(row, color[1]),
+				colorChannel(row, color[2]),
+				colorChannel(row, color[3]),
+			))
+		}
+		for k, property := range attributes {
+			attributeValues[k] = append(attributeValues[k], row[property][0])
+		}
+	}
+
+	for k, property := range attributes {
+		values := append(make([]float64, first), attributeValues[k]...)
+		if err := mesh.SetVertexAttribute(element.properties[property].name, element.properties[property].valueType, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPLYFaces adds the faces and returns the index of the vertex_indices property
+func readPLYFaces(mesh *Mesh, element plyElement, values plyValueReader) (int, error) {
+	indicesProperty := -1
+	for i, property := range element.properties {
+		if property.isList && (property.name == "vertex_indices" || property.name == "vertex_index") {
+			indicesProperty = i
+		}
+	}
+	if indicesProperty < 0 {
+		return 0, malformedError(plyFormatName, 0, "face element needs a vertex_indices list")
+	}
+
+	for i := 0; i < element.count; i++ {
+		row, err := readPLYRow(element, values)
+		if err != nil {
+			return 0, err
+		}
+
+		list := row[indicesProperty]
+		if len(list) < 3 {
+			return 0, malformedError(plyFormatName, 0, "face %d has %d vertices", i, len(list))
+		}
+		indices := make([]int, len(list))
+		polygon := make([]Vertex, len(list))
+		for k, value := range list {
+			if value != math.Trunc(value) || math.Abs(value) > math.MaxInt32 {
+				return 0, malformedError(plyFormatName, 0, "face %d: invalid vertex index %v", i, value)
+			}
+			indices[k] = int(value)
+			vertex, err := mesh.Vertex(indices[k])
+			if err != nil {
+				return 0, malformedError(plyFormatName, 0, "face %d: %v", i, err)
+			}
+			polygon[k] = vertex
+		}
+
+		for _, triangle := range TriangulatePolygon(polygon) {
+			if _, err := mesh.AddFace(indices[triangle[0]], indices[triangle[1]], indices[triangle[2]]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return indicesProperty, nil
+}
+
+type plyValueReader interface {
+	read(valueType ScalarType) (float64, error)
+}
+
+type plyASCIIReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *plyASCIIReader) read(valueType ScalarType) (float64, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return 0, err
+		}
+		return 0, truncatedError(plyFormatName, 0, "unexpected end of data")
+	}
+	value, err := strconv.ParseFloat(r.scanner.Text(), 64)
+	if err != nil {
+		return 0, malformedError(plyFormatName, 0, "invalid %s value %q", valueType, r.scanner.Text())
+	}
+	return value, nil
+}
+
+type plyBinaryReader struct {
+	reader *bufio.Reader
+	order  binary.ByteOrder
+	buffer [8]byte
+}
+
+func (r *plyBinaryReader) read(valueType ScalarType) (float64, error) {
+	data := r.buffer[:scalarSize(valueType)]
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, truncatedError(plyFormatName, 0, "unexpected end of data")
+		}
+		return 0, err
+	}
+
+	switch valueType {
+	case ScalarInt8:
+		return float64(int8(data[0])), nil
+	case ScalarUint8:
+		return float64(data[0]), nil
+	case ScalarInt16:
+		return float64(int16(r.order.Uint16(data))), nil
+	case ScalarUint16:
+		return float64(r.order.Uint16(data)), nil
+	case ScalarInt32:
+		return float64(int32(r.order.Uint32(data))), nil
+	case ScalarUint32:
+		return float64(r.order.Uint32(data)), nil
+	case ScalarFloat32:
+		return float64(math.Float32frombits(r.order.Uint32(data))), nil
+	default:
+		return math.Float64frombits(r.order.Uint64(data)), nil
+	}
+}
+
+// WritePLY encodes the mesh as PLY. Positions, normals, colors and vertex
+// attributes are written with the type they were loaded with; meshes not read
+// from PLY get float positions and normals and uchar colors.
+func WritePLY(w io.Writer, m *Mesh, format PLYFormat) error {
+	buf := bufio.NewWriter(w)
+
+	storage := vertexStorage{position: ScalarFloat32, normal: ScalarFloat32, color: ScalarUint8}
+	if m.myStorage != nil {
+		storage = *m.myStorage
+	}
+	positionType, normalType, colorType := plyTypeName(storage.position), plyTypeName(storage.normal), plyTypeName(storage.color)
+
+	hasAlpha := false
+	for _, color := range m.myColors {
+		if color.A != 1 {
+			hasAlpha = true
+			break
+		}
+	}
+
+	fmt.Fprintf(buf, "ply\n")
+	fmt.Fprintf(buf, "format %s 1.0\n", format)
+	fmt.Fprintf(buf, "comment written by go4/geom\n")
+	fmt.Fprintf(buf, "element vertex %d\n", len(m.myVertices))
+	fmt.Fprintf(buf, "property %[1]s x\nproperty %[1]s y\nproperty %[1]s z\n", positionType)
+	if m.myNormals != nil {
+		fmt.Fprintf(buf, "property %[1]s nx\nproperty %[1]s ny\nproperty %[1]s nz\n", normalType)
+	}
+	if m.myColors != nil {
+		fmt.Fprintf(buf, "property %[1]s red\nproperty %[1]s green\nproperty %[1]s blue\n", colorType)
+		if hasAlpha {
+			fmt.Fprintf(buf, "property %s alpha\n", colorType)
+		}
+	}
+	for _, attribute := range m.myAttributes {
+		fmt.Fprintf(buf, "property %s %s\n", plyTypeName(attribute.Type), attribute.Name)
+	}
+	fmt.Fprintf(buf, "element face %d\n", len(m.myFaces))
+	fmt.Fprintf(buf, "property list uchar int vertex_indices\n")
+	fmt.Fprintf(buf, "end_header\n")
+
+	var values plyValueWriter
+	switch format {
+	case PLYASCII:
+		values = &plyASCIIWriter{writer: buf}
+	case PLYBinaryLittleEndian:
+		values = &plyBinaryWriter{writer: buf, order: binary.LittleEndian}
+	case PLYBinaryBigEndian:
+		values = &plyBinaryWriter{writer: buf, order: binary.BigEndian}
+	default:
+		return fmt.Errorf("unknown PLY format: %d", format)
+	}
+
+	// Integer color channels span their full range, as when reading
+	colorChannel := func(value float64) float64 {
+		switch storage.color {
+		case ScalarUint8:
+			return math.Round(math.Max(0, math.Min(1, value)) * math.MaxUint8)
+		case ScalarUint16:
+			return math.Round(math.Max(0, math.Min(1, value)) * math.MaxUint16)
+		default:
+			return value
+		}
+	}
+
+	for i, v := range m.myVertices {
+		values.write(storage.position, v.X())
+		values.write(storage.position, v.Y())
+		values.write(storage.position, v.Z())
+		if m.myNormals != nil {
+			values.write(storage.normal, m.myNormals[i].X())
+			values.write(storage.normal, m.myNormals[i].Y())
+			values.write(storage.normal, m.myNormals[i].Z())
+		}
+		if m.myColors != nil {
+			color := m.myColors[i]
+			values.write(storage.color, colorChannel(color.R))
+			values.write(storage.color, colorChannel(color.G))
+			values.write(storage.color, colorChannel(color.B))
+			if hasAlpha {
+				values.write(storage.color, colorChannel(color.A))
+			}
+		}
+		for _, attribute := range m.myAttributes {
+			values.write(attribute.Type, attribute.Values[i])
+		}
+		values.endRow()
+	}
+
+	for _, face := range m.myFaces {
+		values.write(ScalarUint8, 3)
+		for _, index := range face.myVertexIndices {
+			values.write(ScalarInt32, float64(index))
+		}
+		values.endRow()
+	}
+
+	return buf.Flush()
+}
+
+// plyValueWriter writes into a bufio.Writer, which keeps the first error for Flush
+type plyValueWriter interface {
+	write(valueType ScalarType, value float64)
+	endRow()
+}
+
+type plyASCIIWriter struct {
+	writer *bufio.Writer
+	inRow  bool
+}
+
+func (w *plyASCIIWriter) write(valueType ScalarType, value float64) {
+	if w.inRow {
+		w.writer.WriteByte(' ')
+	}
+	w.inRow = true
+
+	switch valueType {
+	case ScalarFloat32:
+		w.writer.WriteString(strconv.FormatFloat(value, 'g', -1, 32))
+	case ScalarFloat64:
+		w.writer.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	default:
+		w.writer.WriteString(strconv.FormatInt(int64(math.Round(value)), 10))
+	}
+}
+
+func (w *plyASCIIWriter) endRow() {
+	w.writer.WriteByte('\n')
+	w.inRow = false
+}
+
+type plyBinaryWriter struct {
+	writer *bufio.Writer
+	order  binary.ByteOrder
+	buffer [8]byte
+}
+
+func (w *plyBinaryWriter) write(valueType ScalarType, value float64) {
+	data := w.buffer[:scalarSize(valueType)]
+	switch valueType {
+	case ScalarInt8:
+		data[0] = byte(int8(math.Round(value)))
+	case ScalarUint8:
+		data[0] = byte(math.Round(value))
+	case ScalarInt16:
+		w.order.PutUint16(data, uint16(int16(math.Round(value))))
+	case ScalarUint16:
+		w.order.PutUint16(data, uint16(math.Round(value)))
+	case ScalarInt32:
+		w.order.PutUint32(data, uint32(int32(math.Round(value))))
+	case ScalarUint32:
+		w.order.PutUint32(data, uint32(math.Round(value)))
+	case ScalarFloat32:
+		w.order.PutUint32(data, math.Float32bits(float32(value)))
+	default:
+		w.order.PutUint64(data, math.Float64bits(value))
+	}
+	w.writer.Write(data)
+}
+
+func (w *plyBinaryWriter) endRow() {}
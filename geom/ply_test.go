@@ -0,0 +1,229 @@
+package geom
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+const scannerPLY = `ply
+format ascii 1.0
+comment scanner export
+element vertex 4
+property float x
+property float y
+property float z
+property float nx
+property float ny
+property float nz
+property uchar red
+property uchar green
+property uchar blue
+property float confidence
+property ushort scan_id
+element face 1
+property list uchar int vertex_indices
+element camera 1
+property float view_px
+end_header
+0 0 0 0 0 1 255 0 0 0.5 7
+1 0 0 0 0 1 0 255 0 0.75 7
+1 1 0 0 0 1 0 0 255 1 8
+0 1 0 0 0 1 255 255 255 0.25 8
+4 0 1 2 3
+1.5
+`
+
+func TestReadPLY_ASCII(t *testing.T) {
+	mesh, err := ReadPLY(strings.NewReader(scannerPLY))
+	if err != nil {
+		t.Fatalf("ReadPLY failed: %v", err)
+	}
+
+	if mesh.VertexNumber() != 4 || mesh.FaceNumber() != 2 {
+		t.Fatalf("Expected 4 vertices and 2 faces, got %d and %d", mesh.VertexNumber(), mesh.FaceNumber())
+	}
+
+	normal, _ := mesh.VertexNormal(2)
+	if !normal.Equals(NewVector(0, 0, 1)) {
+		t.Errorf("Expected normal (0, 0, 1), got %v", normal)
+	}
+	color, _ := mesh.VertexColor(1)
+	if !color.Equals(NewColor(0, 1, 0, 1)) {
+		t.Errorf("Expected green vertex color, got %v", color)
+	}
+
+	confidence, ok := mesh.VertexAttribute("confidence")
+	if !ok {
+		t.Fatalf("Expected confidence attribute to be kept")
+	}
+	if confidence.Type != ScalarFloat32 || confidence.Values[1] != 0.75 {
+		t.Errorf("Unexpected confidence attribute %+v", confidence)
+	}
+	scanID, ok := mesh.VertexAttribute("scan_id")
+	if !ok || scanID.Type != ScalarUint16 || scanID.Values[3] != 8 {
+		t.Errorf("Unexpected scan_id attribute %+v", scanID)
+	}
+}
+
+func TestPLY_RoundTrip(t *testing.T) {
+	original, err := ReadPLY(strings.NewReader(scannerPLY))
+	if err != nil {
+		t.Fatalf("ReadPLY failed: %v", err)
+	}
+
+	for _, format := range []PLYFormat{PLYASCII, PLYBinaryLittleEndian, PLYBinaryBigEndian} {
+		t.Run(format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WritePLY(&buf, original, format); err != nil {
+				t.Fatalf("WritePLY failed: %v", err)
+			}
+
+			loaded, err := ReadPLY(&buf)
+			if err != nil {
+				t.Fatalf("ReadPLY failed: %v", err)
+			}
+			assertMeshesEqual(t, original, loaded)
+
+			names := loaded.VertexAttributeNames()
+			if len(names) != 2 || names[0] != "confidence" || names[1] != "scan_id" {
+				t.Fatalf("Expected attributes to be written back, got %v", names)
+			}
+			for _, name := range names {
+				want, _ := original.VertexAttribute(name)
+				got, _ := loaded.VertexAttribute(name)
+				if want.Type != got.Type {
+					t.Errorf("%s: expected type %v, got %v", name, want.Type, got.Type)
+				}
+				for i := range want.Values {
+					if want.Values[i] != got.Values[i] {
+						t.Errorf("%s[%d]: expected %v, got %v", name, i, want.Values[i], got.Values[i])
+					}
+				}
+			}
+
+			for i := 0; i < original.VertexNumber(); i++ {
+				want, _ := original.VertexColor(i)
+				got, _ := loaded.VertexColor(i)
+				if !want.Equals(got) {
+					t.Errorf("Vertex %d: expected color %v, got %v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+const precisePLY = `ply
+format ascii 1.0
+element vertex 3
+property double x
+property double y
+property double z
+property float red
+property float green
+property float blue
+property list uchar int neighbours
+element face 1
+property list uchar int vertex_indices
+property uchar flags
+end_header
+1000000.123456789 0 0 0.1 0.2 0.3 0
+0 1 0 0.4 0.5 0.6 1 0
+0 0 1 0.7 0.8 0.9 2 0 1
+3 0 1 2 5
+`
+
+func TestPLY_RoundTripTypes(t *testing.T) {
+	original, warnings, err := ReadPLYWithWarnings(strings.NewReader(precisePLY))
+	if err != nil {
+		t.Fatalf("ReadPLYWithWarnings failed: %v", err)
+	}
+	expected := []string{`vertex list property "neighbours" was skipped`, `face property "flags" was skipped`}
+	if strings.Join(warnings, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected warnings %q, got %q", expected, warnings)
+	}
+
+	for _, format := range []PLYFormat{PLYASCII, PLYBinaryLittleEndian, PLYBinaryBigEndian} {
+		var buf bytes.Buffer
+		if err := WritePLY(&buf, original, format); err != nil {
+			t.Fatalf("%v: WritePLY failed: %v", format, err)
+		}
+		if header := buf.String(); !strings.Contains(header, "property double x\n") || !strings.Contains(header, "property float red\n") {
+			t.Errorf("%v: expected double positions and float colors, got\n%s", format, header[:strings.Index(header, "end_header")])
+		}
+
+		loaded, err := ReadPLY(&buf)
+		if err != nil {
+			t.Fatalf("%v: ReadPLY failed: %v", format, err)
+		}
+		for i := 0; i < original.VertexNumber(); i++ {
+			want, _ := original.Vertex(i)
+			got, _ := loaded.Vertex(i)
+			if want != got {
+				t.Errorf("%v: vertex %d: expected %v, got %v", format, i, want, got)
+			}
+			wantColor, _ := original.VertexColor(i)
+			gotColor, _ := loaded.VertexColor(i)
+			// Float channels keep far more precision than the 1/255 steps of uchar
+			if math.Abs(wantColor.R-gotColor.R) > 1e-6 || math.Abs(wantColor.G-gotColor.G) > 1e-6 || math.Abs(wantColor.B-gotColor.B) > 1e-6 {
+				t.Errorf("%v: vertex %d: expected color %v, got %v", format, i, wantColor, gotColor)
+			}
+		}
+	}
+
+	if _, warnings, _ := ReadPLYWithWarnings(strings.NewReader(scannerPLY)); len(warnings) != 1 || !strings.Contains(warnings[0], `"camera"`) {
+		t.Errorf("Expected a warning about the camera element, got %q", warnings)
+	}
+}
+
+func TestMesh_VertexAttributeFollowsVertices(t *testing.T) {
+	mesh := &Mesh{}
+	mesh.AddVertex(NewVertex(0, 0, 0))
+	if err := mesh.SetVertexAttribute("weight", ScalarFloat64, []float64{2}); err != nil {
+		t.Fatalf("SetVertexAttribute failed: %v", err)
+	}
+	mesh.AddVertex(NewVertex(1, 0, 0))
+
+	weight, _ := mesh.VertexAttribute("weight")
+	if len(weight.Values) != 2 {
+		t.Errorf("Expected attribute to grow with vertices, got %v", weight.Values)
+	}
+	if err := mesh.SetVertexAttribute("weight", ScalarFloat64, []float64{1}); err == nil {
+		t.Error("Expected error for attribute length mismatch, got nil")
+	}
+}
+
+func TestReadPLY_Errors(t *testing.T) {
+	var binaryCube bytes.Buffer
+	_ = WritePLY(&binaryCube, CreateCube(1), PLYBinaryLittleEndian)
+
+	cases := []struct {
+		name     string
+		input    string
+		expected error
+	}{
+		{"no magic", "obj\n", ErrMalformed},
+		{"no end_header", "ply\nformat ascii 1.0\nelement vertex 1\n", ErrTruncated},
+		{"unknown type", "ply\nformat ascii 1.0\nelement vertex 1\nproperty quad x\nend_header\n", ErrMalformed},
+		{"missing z", "ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nproperty float y\nend_header\n0 0\n", ErrMalformed},
+		{"short ascii body", "ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nend_header\n0 0 0\n1 1\n", ErrTruncated},
+		{"face index out of range", "ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n0 0 0\n3 0 1 2\n", ErrMalformed},
+		{"short binary body", binaryCube.String()[:binaryCube.Len()-5], ErrTruncated},
+		{"huge list length", "ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list double int vertex_indices\nend_header\n0 0 0\n1e300 0 0 0\n", ErrMalformed},
+		{"binary list length beyond data", "ply\nformat binary_little_endian 1.0\nelement face 1\nproperty list uint int vertex_indices\nend_header\n\xff\xff\xff\x7f\x00\x00\x00\x00", ErrTruncated},
+		{"binary list length out of range", "ply\nformat binary_little_endian 1.0\nelement face 1\nproperty list uint int vertex_indices\nend_header\n\xfe\xff\xff\xff", ErrMalformed},
+		{"fractional face index", "ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar float vertex_indices\nend_header\n0 0 0\n1 0 0\n0 1 0\n3 0 1.5 2\n", ErrMalformed},
+		{"face index out of int range", "ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar double vertex_indices\nend_header\n0 0 0\n1 0 0\n0 1 0\n3 0 1 1e20\n", ErrMalformed},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadPLY(strings.NewReader(test.input))
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 // Package geom provides geometric primitives for 2D and 3D modeling and visualization.
 package geom
 
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
 // CreateCube creates a cube mesh with the specified size
 func CreateCube(size float64) *Mesh {
 	mesh := &Mesh{}
@@ -59,3 +65,293 @@ func CreateTetrahedron(size float64) *Mesh {
 
 	return mesh
 }
+
+// Primitives below are centered at the origin with Z as the up axis. Segment
+// and ring counts below the smallest meaningful value are raised to it.
+
+// CreateUVSphere creates a sphere from segments meridians and rings parallel bands
+func CreateUVSphere(radius float64, segments, rings int) *Mesh {
+	rings = max(rings, 2)
+	profile := make([][2]float64, rings+1)
+	for i := range profile {
+		phi := math.Pi * float64(i) / float64(rings)
+		profile[i] = [2]float64{radius * math.Sin(phi), radius * math.Cos(phi)}
+	}
+	profile[0][0], profile[rings][0] = 0, 0
+	return createLathe(profile, segments, false)
+}
+
+// CreateIcosphere creates a sphere by splitting every triangle of an
+// icosahedron into four, subdivisions times, and projecting onto the sphere
+func CreateIcosphere(radius float64, subdivisions int) *Mesh {
+	mesh := CreateIcosahedron(radius)
+	for level := 0; level < subdivisions; level++ {
+		midpoints := make(map[[2]int]int)
+		midpoint := func(a, b int) int {
+			key := [2]int{min(a, b), max(a, b)}
+			if index, ok := midpoints[key]; ok {
+				return index
+			}
+			mid := NewVectorFromVertex(mesh.myVertices[a])
+			mid.Add(NewVectorFromVertex(mesh.myVertices[b]))
+			mid.Normalize()
+			mid.Scale(radius)
+			index := mesh.AddVertex(Vertex{mid.myCoords})
+			midpoints[key] = index
+			return index
+		}
+
+		faces := mesh.myFaces
+		mesh.myFaces = make([]Triangle, 0, 4*len(faces))
+		for _, face := range faces {
+			a, b, c := face.myVertexIndices[0], face.myVertexIndices[1], face.myVertexIndices[2]
+			ab, bc, ca := midpoint(a, b), midpoint(b, c), midpoint(c, a)
+			_, _ = mesh.AddFace(a, ab, ca)
+			_, _ = mesh.AddFace(b, bc, ab)
+			_, _ = mesh.AddFace(c, ca, bc)
+			_, _ = mesh.AddFace(ab, bc, ca)
+		}
+	}
+	return mesh
+}
+
+// CreateCylinder creates a cylinder along Z, optionally closed by caps
+func CreateCylinder(radius, height float64, segments int, caps bool) *Mesh {
+	profile := [][2]float64{{radius, height / 2}, {radius, -height / 2}}
+	if caps {
+		profile = append([][2]float64{{0, height / 2}}, append(profile, [2]float64{0, -height / 2})...)
+	}
+	return createLathe(profile, segments, false)
+}
+
+// CreateCone creates a cone with its base at -height/2 and its apex at height/2
+func CreateCone(radius, height float64, segments int, cap bool) *Mesh {
+	profile := [][2]float64{{0, height / 2}, {radius, -height / 2}}
+	if cap {
+		profile = append(profile, [2]float64{0, -height / 2})
+	}
+	return createLathe(profile, segments, false)
+}
+
+// CreateTorus creates a torus around Z. The tube of radius minorRadius
+// circles the axis at majorRadius.
+func CreateTorus(majorRadius, minorRadius float64, majorSegments, minorSegments int) *Mesh {
+	minorSegments = max(minorSegments, 3)
+	profile := make([][2]float64, minorSegments)
+	for i := range profile {
+		// Clockwise in the (radius, z) plane, so the outside runs downward like the other profiles
+		angle := -2 * math.Pi * float64(i) / float64(minorSegments)
+		profile[i] = [2]float64{majorRadius + minorRadius*math.Cos(angle), minorRadius * math.Sin(angle)}
+	}
+	return createLathe(profile, majorSegments, true)
+}
+
+// CreatePlane creates a grid in the XY plane facing +Z, split into
+// segmentsX by segmentsY quads of two triangles each
+func CreatePlane(width, depth float64, segmentsX, segmentsY int) *Mesh {
+	segmentsX, segmentsY = max(segmentsX, 1), max(segmentsY, 1)
+	mesh := &Mesh{}
+	for j := 0; j <= segmentsY; j++ {
+		for i := 0; i <= segmentsX; i++ {
+			mesh.AddVertex(NewVertex(
+				width*(float64(i)/float64(segmentsX)-0.5),
+				depth*(float64(j)/float64(segmentsY)-0.5),
+				0,
+			))
+		}
+	}
+	for j := 0; j < segmentsY; j++ {
+		for i := 0; i < segmentsX; i++ {
+			a := j*(segmentsX+1) + i
+			addQuad(mesh, a, a+1, a+segmentsX+2, a+segmentsX+1)
+		}
+	}
+	return mesh
+}
+
+// CreateCapsule creates a cylinder of the given length along Z capped by two
+// hemispheres, each made of rings bands
+func CreateCapsule(radius, length float64, segments, rings int) *Mesh {
+	rings = max(rings, 1)
+	if length <= 0 {
+		return CreateUVSphere(radius, segments, 2*rings)
+	}
+
+	var profile [][2]float64
+	for _, hemisphere := range []struct{ offset, start float64 }{{length / 2, 0}, {-length / 2, math.Pi / 2}} {
+		for i := 0; i <= rings; i++ {
+			phi := hemisphere.start + math.Pi/2*float64(i)/float64(rings)
+			profile = append(profile, [2]float64{radius * math.Sin(phi), hemisphere.offset + radius*math.Cos(phi)})
+		}
+	}
+	profile[0][0], profile[len(profile)-1][0] = 0, 0
+	return createLathe(profile, segments, false)
+}
+
+// CreateOctahedron creates a regular octahedron with vertices at the given distance from the center
+func CreateOctahedron(radius float64) *Mesh {
+	mesh := &Mesh{}
+	for _, v := range []Vertex{
+		NewVertex(radius, 0, 0), NewVertex(-radius, 0, 0),
+		NewVertex(0, radius, 0), NewVertex(0, -radius, 0),
+		NewVertex(0, 0, radius), NewVertex(0, 0, -radius),
+	} {
+		mesh.AddVertex(v)
+	}
+	for _, x := range []int{0, 1} {
+		for _, y := range []int{2, 3} {
+			for _, z := range []int{4, 5} {
+				addOutwardFace(mesh, x, y, z)
+			}
+		}
+	}
+	return mesh
+}
+
+// CreateIcosahedron creates a regular icosahedron with vertices at the given distance from the center
+func CreateIcosahedron(radius float64) *Mesh {
+	mesh := &Mesh{}
+	for _, direction := range goldenRectangleVertices(1, math.Phi) {
+		direction.Normalize()
+		direction.Scale(radius)
+		mesh.AddVertex(Vertex{direction.myCoords})
+	}
+
+	// Faces join the vertex triples at the edge length 2 of the unscaled golden rectangles
+	edge := 2 * radius / math.Sqrt(1+math.Phi*math.Phi)
+	isEdge := func(a, b int) bool {
+		return math.Abs(mesh.myVertices[a].Distance(mesh.myVertices[b])-edge) < 1e-6*radius
+	}
+	for a := 0; a < 12; a++ {
+		for b := a + 1; b < 12; b++ {
+			for c := b + 1; c < 12; c++ {
+				if isEdge(a, b) && isEdge(b, c) && isEdge(a, c) {
+					addOutwardFace(mesh, a, b, c)
+				}
+			}
+		}
+	}
+	return mesh
+}
+
+// CreateDodecahedron creates a regular dodecahedron with vertices at the given
+// distance from the center. Each pentagon is split into three triangles.
+func CreateDodecahedron(radius float64) *Mesh {
+	directions := goldenRectangleVertices(1/math.Phi, math.Phi)
+	for _, x := range []float64{-1, 1} {
+		for _, y := range []float64{-1, 1} {
+			for _, z := range []float64{-1, 1} {
+				directions = append(directions, NewVector(x, y, z))
+			}
+		}
+	}
+
+	mesh := &Mesh{}
+	for _, direction := range directions {
+		direction.Normalize()
+		direction.Scale(radius)
+		mesh.AddVertex(Vertex{direction.myCoords})
+	}
+
+	// The pentagons face the vertices of the dual icosahedron
+	for _, normal := range goldenRectangleVertices(math.Phi, 1) {
+		normal.Normalize()
+		pentagon := make([]int, len(mesh.myVertices))
+		for i := range pentagon {
+			pentagon[i] = i
+		}
+		height := func(v int) float64 {
+			return normal.Dot(NewVectorFromVertex(mesh.myVertices[v]))
+		}
+		slices.SortFunc(pentagon, func(a, b int) int {
+			return cmp.Compare(height(b), height(a))
+		})
+		pentagon = pentagon[:5]
+
+		// Order the corners around the face normal
+		center := NewVectorFromVertex(mesh.myVertices[pentagon[0]])
+		reference := normal.Cross(center)
+		angle := func(v int) float64 {
+			direction := NewVectorFromVertex(mesh.myVertices[v])
+			return math.Atan2(reference.Dot(direction), center.Dot(direction)-height(v)*normal.Dot(center))
+		}
+		slices.SortFunc(pentagon, func(a, b int) int {
+			return cmp.Compare(angle(a), angle(b))
+		})
+		for i := 1; i < 4; i++ {
+			addOutwardFace(mesh, pentagon[0], pentagon[i], pentagon[i+1])
+		}
+	}
+	return mesh
+}
+
+// goldenRectangleVertices returns the corners (0, ±a, ±b) and their cyclic permutations
+func goldenRectangleVertices(a, b float64) []Vector {
+	var res []Vector
+	for _, sa := range []float64{-1, 1} {
+		for _, sb := range []float64{-1, 1} {
+			res = append(res,
+				NewVector(0, sa*a, sb*b),
+				NewVector(sa*a, sb*b, 0),
+				NewVector(sb*b, 0, sa*a),
+			)
+		}
+	}
+	return res
+}
+
+// createLathe revolves a profile of (radius, z) points around the Z axis.
+// The profile runs downward along the outside of the surface; points with
+// zero radius become single pole vertices. A closed profile joins its last
+// point back to the first.
+func createLathe(profile [][2]float64, segments int, closed bool) *Mesh {
+	segments = max(segments, 3)
+	mesh := &Mesh{}
+
+	rings := make([][]int, len(profile))
+	for k, point := range profile {
+		if point[0] == 0 {
+			pole := mesh.AddVertex(NewVertex(0, 0, point[1]))
+			rings[k] = slices.Repeat([]int{pole}, segments)
+			continue
+		}
+		rings[k] = make([]int, segments)
+		for j := range rings[k] {
+			theta := 2 * math.Pi * float64(j) / float64(segments)
+			rings[k][j] = mesh.AddVertex(NewVertex(point[0]*math.Cos(theta), point[0]*math.Sin(theta), point[1]))
+		}
+	}
+
+	bands := len(profile) - 1
+	if closed {
+		bands = len(profile)
+	}
+	for k := 0; k < bands; k++ {
+		upper, lower := rings[k], rings[(k+1)%len(profile)]
+		for j := 0; j < segments; j++ {
+			next := (j + 1) % segments
+			if upper[j] != upper[next] {
+				_, _ = mesh.AddFace(upper[j], lower[j], upper[next])
+			}
+			if lower[j] != lower[next] {
+				_, _ = mesh.AddFace(upper[next], lower[j], lower[next])
+			}
+		}
+	}
+	return mesh
+}
+
+// addQuad adds the quad (a, b, c, d), given counterclockwise as seen from the front, as two triangles
+func addQuad(mesh *Mesh, a, b, c, d int) {
+	_, _ = mesh.AddFace(a, b, c)
+	_, _ = mesh.AddFace(a, c, d)
+}
+
+// addOutwardFace adds a face of a convex solid centered at the origin, winding it to face away from the center
+func addOutwardFace(mesh *Mesh, a, b, c int) {
+	normal := ComputeNormal(mesh.myVertices[a], mesh.myVertices[b], mesh.myVertices[c])
+	if normal.Dot(NewVectorFromVertex(mesh.myVertices[a])) < 0 {
+		b, c = c, b
+	}
+	_, _ = mesh.AddFace(a, b, c)
+}
@@ -0,0 +1,137 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrimitives_ClosedAndOutward(t *testing.T) {
+	tests := []struct {
+		name     string
+		mesh     *Mesh
+		vertices int
+		faces    int
+		volume   float64 // exact volume, or 0 to only check the sign
+	}{
+		{"uv sphere", CreateUVSphere(1, 16, 8), 2 + 16*7, 2 * 16 * 7, 0},
+		{"icosphere", CreateIcosphere(1, 2), 162, 320, 0},
+		{"cylinder", CreateCylinder(1, 2, 12, true), 2 + 24, 12 * 4, 12 * math.Sin(2*math.Pi/12)},
+		{"cone", CreateCone(1, 3, 10, true), 2 + 10, 20, 10 * math.Sin(2*math.Pi/10) / 2},
+		{"torus", CreateTorus(2, 0.5, 24, 12), 24 * 12, 2 * 24 * 12, 0},
+		{"capsule", CreateCapsule(0.5, 2, 12, 4), 2 + 12*8, 2 * 12 * 8, 0},
+		{"flat capsule", CreateCapsule(0.5, 0, 12, 4), 2 + 12*7, 2 * 12 * 7, 0},
+		{"octahedron", CreateOctahedron(1), 6, 8, 4.0 / 3},
+		{"dodecahedron", CreateDodecahedron(1), 20, 36, 0},
+		{"icosahedron", CreateIcosahedron(1), 12, 20, 0},
+	}
+
+	for _, tt := range tests {
+		if tt.mesh.VertexNumber() != tt.vertices || tt.mesh.FaceNumber() != tt.faces {
+			t.Errorf("%s: expected %d vertices and %d faces, got %d and %d",
+				tt.name, tt.vertices, tt.faces, tt.mesh.VertexNumber(), tt.mesh.FaceNumber())
+		}
+
+		report := tt.mesh.Validate()
+		if !report.IsValid() {
+			t.Errorf("%s: expected a valid closed mesh, got\n%v", tt.name, report)
+		}
+
+		volume := tt.mesh.SignedVolume()
+		if volume <= 0 || (tt.volume != 0 && math.Abs(volume-tt.volume) > 1e-9) {
+			t.Errorf("%s: expected positive volume %v, got %v", tt.name, tt.volume, volume)
+		}
+	}
+}
+
+func TestPrimitives_ApproachSmoothVolumes(t *testing.T) {
+	sphere := 4.0 / 3 * math.Pi
+	for name, volume := range map[string]float64{
+		"uv sphere": CreateUVSphere(1, 128, 64).SignedVolume(),
+		"icosphere": CreateIcosphere(1, 5).SignedVolume(),
+	} {
+		if math.Abs(volume-sphere)/sphere > 0.01 {
+			t.Errorf("%s: expected a volume near %v, got %v", name, sphere, volume)
+		}
+	}
+
+	torus := 2 * math.Pi * math.Pi * 2 * 0.5 * 0.5
+	if volume := CreateTorus(2, 0.5, 128, 64).SignedVolume(); math.Abs(volume-torus)/torus > 0.01 {
+		t.Errorf("torus: expected a volume near %v, got %v", torus, volume)
+	}
+
+	capsule := math.Pi*0.25*2 + 4.0/3*math.Pi*0.125
+	if volume := CreateCapsule(0.5, 2, 128, 32).SignedVolume(); math.Abs(volume-capsule)/capsule > 0.01 {
+		t.Errorf("capsule: expected a volume near %v, got %v", capsule, volume)
+	}
+}
+
+func TestPrimitives_Radii(t *testing.T) {
+	for name, mesh := range map[string]*Mesh{
+		"uv sphere":    CreateUVSphere(2, 8, 4),
+		"icosphere":    CreateIcosphere(2, 3),
+		"octahedron":   CreateOctahedron(2),
+		"dodecahedron": CreateDodecahedron(2),
+		"icosahedron":  CreateIcosahedron(2),
+	} {
+		for v := 0; v < mesh.VertexNumber(); v++ {
+			vertex, _ := mesh.Vertex(v)
+			if distance := NewVectorFromVertex(vertex).Length(); math.Abs(distance-2) > 1e-9 {
+				t.Fatalf("%s: vertex %d at distance %v, expected 2", name, v, distance)
+			}
+		}
+	}
+
+	// Platonic solids have equal edges
+	for name, mesh := range map[string]*Mesh{
+		"dodecahedron": CreateDodecahedron(1),
+		"icosahedron":  CreateIcosahedron(1),
+	} {
+		topology := NewHalfEdgeMesh(mesh)
+		shortest, longest := math.Inf(1), 0.0
+		for e := 0; e < topology.EdgeNumber(); e++ {
+			edge := topology.Edge(e)
+			a, _ := mesh.Vertex(edge.V0)
+			b, _ := mesh.Vertex(edge.V1)
+			shortest, longest = math.Min(shortest, a.Distance(b)), math.Max(longest, a.Distance(b))
+		}
+		// The dodecahedron also has the pentagon diagonals from its triangulation
+		if name == "icosahedron" && longest-shortest > 1e-9 {
+			t.Errorf("%s: edges range from %v to %v", name, shortest, longest)
+		}
+		if name == "dodecahedron" && math.Abs(longest/shortest-math.Phi) > 1e-9 {
+			t.Errorf("%s: expected diagonals of phi times the edge, got ratio %v", name, longest/shortest)
+		}
+	}
+}
+
+func TestPrimitives_OpenShapes(t *testing.T) {
+	plane := CreatePlane(4, 2, 4, 2)
+	if plane.VertexNumber() != 15 || plane.FaceNumber() != 16 {
+		t.Fatalf("Expected 15 vertices and 16 faces, got %d and %d", plane.VertexNumber(), plane.FaceNumber())
+	}
+	for f := 0; f < plane.FaceNumber(); f++ {
+		normal, _ := plane.Normal(f)
+		assertVectorNear(t, "plane normal", NewVector(0, 0, 1), normal)
+	}
+	if area := plane.SurfaceArea(); math.Abs(area-8) > 1e-9 {
+		t.Errorf("Expected plane area 8, got %v", area)
+	}
+	box := plane.BoundingBox()
+	assertVectorNear(t, "plane size", NewVector(4, 2, 0), box.Size())
+
+	tube := CreateCylinder(1, 2, 8, false)
+	report := tube.Validate()
+	if len(report.Holes) != 2 || len(report.InconsistentEdges) != 0 || len(report.NonManifoldEdges) != 0 {
+		t.Errorf("Expected an open tube with two rims, got\n%v", report)
+	}
+
+	cone := CreateCone(1, 1, 8, false)
+	if holes := cone.Validate().Holes; len(holes) != 1 || len(holes[0]) != 8 {
+		t.Errorf("Expected an open cone base of 8 edges, got %v", holes)
+	}
+
+	// Counts below the minimum are raised
+	if sphere := CreateUVSphere(1, 1, 1); sphere.FaceNumber() != 2*3*1 {
+		t.Errorf("Expected the smallest sphere to have 6 faces, got %d", sphere.FaceNumber())
+	}
+}
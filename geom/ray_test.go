@@ -0,0 +1,153 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRay_IntersectTriangle(t *testing.T) {
+	v0, v1, v2 := NewVertex(0, 0, 0), NewVertex(1, 0, 0), NewVertex(0, 1, 0)
+
+	ray := NewRay(NewVertex(0.25, 0.5, 2), NewVector(0, 0, -4))
+	distance, u, v, hit := ray.IntersectTriangle(v0, v1, v2)
+	if !hit {
+		t.Fatal("Expected hit, got miss")
+	}
+	if math.Abs(distance-2) > 1e-12 || math.Abs(u-0.25) > 1e-12 || math.Abs(v-0.5) > 1e-12 {
+		t.Errorf("Expected distance 2 and barycentrics (0.25, 0.5), got %f (%f, %f)", distance, u, v)
+	}
+
+	misses := map[string]Ray{
+		"outside":  NewRay(NewVertex(1, 1, 2), NewVector(0, 0, -1)),
+		"behind":   NewRay(NewVertex(0.25, 0.25, -1), NewVector(0, 0, -1)),
+		"parallel": NewRay(NewVertex(-1, 0.25, 0), NewVector(1, 0, 0)),
+		"zero":     NewRay(NewVertex(0.25, 0.25, 1), Vector{}),
+	}
+	for name, ray := range misses {
+		if _, _, _, hit := ray.IntersectTriangle(v0, v1, v2); hit {
+			t.Errorf("%s: expected miss, got hit", name)
+		}
+	}
+}
+
+func TestRay_IntersectAABB(t *testing.T) {
+	box := NewAABB(NewVertex(-1, -1, -1), NewVertex(1, 1, 1))
+
+	enter, exit, hit := NewRay(NewVertex(-5, 0, 0), NewVector(1, 0, 0)).IntersectAABB(box)
+	if !hit || math.Abs(enter-4) > 1e-12 || math.Abs(exit-6) > 1e-12 {
+		t.Errorf("Expected entry at 4 and exit at 6, got %f, %f, %v", enter, exit, hit)
+	}
+	if enter, _, hit := NewRay(NewVertex(0, 0, 0), NewVector(0, 1, 0)).IntersectAABB(box); !hit || enter != 0 {
+		t.Errorf("Expected ray from inside to enter at 0, got %f, %v", enter, hit)
+	}
+	if _, _, hit := NewRay(NewVertex(-5, 2, 0), NewVector(1, 0, 0)).IntersectAABB(box); hit {
+		t.Error("Expected parallel ray outside the slab to miss")
+	}
+}
+
+func TestMesh_IntersectCube(t *testing.T) {
+	cube := CreateCube(2)
+	ray := NewRay(NewVertex(0.2, 0.3, 10), NewVector(0, 0, -1))
+
+	hit, ok := cube.Intersect(ray)
+	if !ok {
+		t.Fatal("Expected hit, got miss")
+	}
+	if math.Abs(hit.Distance-9) > 1e-12 {
+		t.Errorf("Expected distance 9, got %f", hit.Distance)
+	}
+	assertVectorNear(t, "hit point", NewVector(0.2, 0.3, 1), NewVectorFromVertex(hit.Point))
+	assertVectorNear(t, "hit normal", NewVector(0, 0, 1), hit.Normal)
+	if !hit.FrontFacing {
+		t.Error("Expected the ray to hit the outside of the cube")
+	}
+
+	// The barycentric weights reproduce the hit point
+	var point Coords3d
+	for k := 0; k < 3; k++ {
+		v, _ := cube.VertexInFace(hit.Face, k)
+		c := v.myCoords
+		c.Scale(hit.Barycentric[k])
+		point.Add(c)
+	}
+	assertVectorNear(t, "barycentric point", NewVectorFromVertex(hit.Point), Vector{point})
+
+	all := cube.IntersectAll(ray)
+	if len(all) != 2 {
+		t.Fatalf("Expected entry and exit hits, got %d", len(all))
+	}
+	if all[0].Face != hit.Face || math.Abs(all[1].Distance-11) > 1e-12 || all[1].FrontFacing {
+		t.Errorf("Unexpected hits %+v", all)
+	}
+
+	if _, ok := cube.Intersect(NewRay(NewVertex(5, 5, 10), NewVector(0, 0, -1))); ok {
+		t.Error("Expected ray beside the cube to miss")
+	}
+}
+
+func TestMesh_IntersectIsDeterministicOnEdges(t *testing.T) {
+	// The ray passes through the diagonal shared by the two triangles of the front face
+	cube := CreateCube(2)
+	ray := NewRay(NewVertex(0, 0, 10), NewVector(0, 0, -1))
+
+	first, ok := cube.Intersect(ray)
+	if !ok {
+		t.Fatal("Expected hit, got miss")
+	}
+	for i := 0; i < 10; i++ {
+		again, _ := cube.Intersect(ray)
+		if again.Face != first.Face {
+			t.Fatalf("Expected face %d every time, got %d", first.Face, again.Face)
+		}
+	}
+
+	all := cube.IntersectAll(ray)
+	for i := 1; i < len(all); i++ {
+		if all[i].Distance == all[i-1].Distance && all[i].Face < all[i-1].Face {
+			t.Errorf("Expected equal distances ordered by face index, got %+v", all)
+		}
+	}
+}
+
+func TestMesh_IntersectTetrahedron(t *testing.T) {
+	tetra := CreateTetrahedron(2)
+	ray := NewRay(NewVertex(0.1, 0.1, 10), NewVector(0, 0, -1))
+
+	hits := tetra.IntersectAll(ray)
+	if len(hits) != 2 {
+		t.Fatalf("Expected 2 hits through the tetrahedron, got %d", len(hits))
+	}
+
+	// The ray enters through the face x + y + z = 1
+	assertVectorNear(t, "entry", NewVector(0.1, 0.1, 0.8), NewVectorFromVertex(hits[0].Point))
+	if !hits[0].FrontFacing || hits[1].FrontFacing {
+		t.Errorf("Expected to enter from outside and leave from inside, got %+v", hits)
+	}
+	if hits[1].Distance <= hits[0].Distance {
+		t.Errorf("Expected hits ordered by distance, got %f and %f", hits[0].Distance, hits[1].Distance)
+	}
+
+	nearest, _ := tetra.Intersect(ray)
+	if nearest.Face != hits[0].Face {
+		t.Errorf("Expected nearest hit on face %d, got %d", hits[0].Face, nearest.Face)
+	}
+}
+
+func TestMesh_IntersectInterpolatesVertexNormals(t *testing.T) {
+	mesh := &Mesh{}
+	v0 := mesh.AddVertex(NewVertex(0, 0, 0))
+	v1 := mesh.AddVertex(NewVertex(1, 0, 0))
+	v2 := mesh.AddVertex(NewVertex(0, 1, 0))
+	_, _ = mesh.AddFace(v0, v1, v2)
+	_ = mesh.SetVertexNormal(v0, NewVector(0, 0, 1))
+	_ = mesh.SetVertexNormal(v1, NewVector(1, 0, 0))
+	_ = mesh.SetVertexNormal(v2, NewVector(0, 0, 1))
+
+	hit, ok := mesh.Intersect(NewRay(NewVertex(0.5, 0, 1), NewVector(0, 0, -1)))
+	if !ok {
+		t.Fatal("Expected hit, got miss")
+	}
+	expected := NewVector(1, 0, 1)
+	expected.Normalize()
+	assertVectorNear(t, "interpolated normal", expected, hit.Normal)
+}
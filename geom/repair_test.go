@@ -0,0 +1,198 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+// explode returns a copy of the mesh where every face has its own three vertices,
+// as read from an STL file
+func explode(mesh *Mesh) *Mesh {
+	soup := &Mesh{}
+	for f := 0; f < mesh.FaceNumber(); f++ {
+		var indices [3]int
+		for k := range indices {
+			v, _ := mesh.VertexInFace(f, k)
+			indices[k] = soup.AddVertex(v)
+		}
+		_, _ = soup.AddFace(indices[0], indices[1], indices[2])
+	}
+	return soup
+}
+
+func TestWeld(t *testing.T) {
+	soup := explode(CreateCube(2))
+	_ = soup.SetVertexColor(3, NewColor(1, 0, 0, 1))
+
+	merged, err := soup.Weld(DefaultTolerance)
+	if err != nil {
+		t.Fatalf("Weld failed: %v", err)
+	}
+	if merged != 36-8 || soup.VertexNumber() != 8 {
+		t.Fatalf("Expected 28 merged and 8 remaining vertices, got %d and %d", merged, soup.VertexNumber())
+	}
+	if !soup.Validate().IsValid() {
+		t.Errorf("Expected a valid cube after welding, got\n%v", soup.Validate())
+	}
+	if !soup.HasVertexColors() || soup.myColors[3] != NewColor(1, 0, 0, 1) {
+		t.Errorf("Expected the color of the kept vertex to survive, got %v", soup.myColors)
+	}
+
+	// Vertices further apart than the tolerance stay separate
+	nearby := &Mesh{}
+	nearby.AddVertex(NewVertex(0, 0, 0))
+	nearby.AddVertex(NewVertex(0.05, 0, 0))
+	nearby.AddVertex(NewVertex(0.2, 0, 0))
+	if merged, _ := nearby.Weld(0.1); merged != 1 || nearby.VertexNumber() != 2 {
+		t.Errorf("Expected one merge at tolerance 0.1, got %d merges and %d vertices", merged, nearby.VertexNumber())
+	}
+
+	// Zero tolerance merges positive and negative zero, as mirroring produces both
+	signed := &Mesh{}
+	signed.AddVertex(NewVertex(0, 1, 0))
+	signed.AddVertex(NewVertex(math.Copysign(0, -1), 1, math.Copysign(0, -1)))
+	if merged, _ := signed.Weld(0); merged != 1 || signed.VertexNumber() != 1 {
+		t.Errorf("Expected -0 and +0 to merge, got %d merges and %d vertices", merged, signed.VertexNumber())
+	}
+
+	if _, err := nearby.Weld(-1); err == nil {
+		t.Errorf("Expected an error for a negative tolerance")
+	}
+	if _, err := nearby.Weld(math.NaN()); err == nil {
+		t.Errorf("Expected an error for a NaN tolerance")
+	}
+}
+
+func TestRemoveDegenerateAndDuplicateFaces(t *testing.T) {
+	mesh := CreateCube(2)
+	indices, _ := mesh.FaceIndices(3)
+	_, _ = mesh.AddFace(indices[1], indices[0], indices[2])
+	_, _ = mesh.AddFace(0, 0, 1)
+	middle := mesh.AddVertex(NewVertex(0, -1, -1))
+	_, _ = mesh.AddFace(0, middle, 1)
+
+	if removed := mesh.RemoveDegenerateFaces(); removed != 2 {
+		t.Errorf("Expected 2 degenerate faces, got %d", removed)
+	}
+	if removed := mesh.RemoveDuplicateFaces(); removed != 1 {
+		t.Errorf("Expected 1 duplicate face, got %d", removed)
+	}
+	if removed := mesh.RemoveUnusedVertices(); removed != 1 {
+		t.Errorf("Expected 1 unused vertex, got %d", removed)
+	}
+	if !mesh.Validate().IsValid() {
+		t.Errorf("Expected the original cube, got\n%v", mesh.Validate())
+	}
+}
+
+func TestUnifyOrientation(t *testing.T) {
+	mesh := CreateCube(2)
+	for _, f := range []int{1, 4, 7} {
+		face := &mesh.myFaces[f]
+		face.myVertexIndices[0], face.myVertexIndices[1] = face.myVertexIndices[1], face.myVertexIndices[0]
+	}
+	if flipped := mesh.UnifyOrientation(); flipped != 3 {
+		t.Errorf("Expected 3 flipped faces, got %d", flipped)
+	}
+	if report := mesh.Validate(); !report.IsValid() || mesh.SignedVolume() <= 0 {
+		t.Errorf("Expected a consistent outward cube, got volume %v\n%v", mesh.SignedVolume(), report)
+	}
+
+	// A fully inverted component is turned outward
+	inverted := CreateTetrahedron(1)
+	for f := range inverted.myFaces {
+		face := &inverted.myFaces[f]
+		face.myVertexIndices[1], face.myVertexIndices[2] = face.myVertexIndices[2], face.myVertexIndices[1]
+		face.myNormal.Scale(-1)
+	}
+	if inverted.SignedVolume() >= 0 {
+		t.Fatalf("Expected an inverted tetrahedron")
+	}
+	if flipped := inverted.UnifyOrientation(); flipped != 4 || inverted.SignedVolume() <= 0 {
+		t.Errorf("Expected all 4 faces flipped outward, got %d and volume %v", flipped, inverted.SignedVolume())
+	}
+	normal, _ := inverted.Normal(0)
+	v1, _ := inverted.VertexInFace(0, 0)
+	v2, _ := inverted.VertexInFace(0, 1)
+	v3, _ := inverted.VertexInFace(0, 2)
+	if !normal.Equals(ComputeNormal(v1, v2, v3)) {
+		t.Errorf("Expected the face normal to follow the new winding")
+	}
+}
+
+func TestFillHoles(t *testing.T) {
+	mesh := CreateCube(2)
+	red := mesh.AddMaterial(NewMaterial("red"))
+	for f := 0; f < mesh.FaceNumber(); f++ {
+		_ = mesh.SetFaceMaterial(f, red)
+	}
+	mesh.myFaces = append(mesh.myFaces[:2], mesh.myFaces[4:]...)
+
+	if filled := mesh.FillHoles(3); filled != 0 {
+		t.Errorf("Expected a 4-edge hole to be too large, got %d fills", filled)
+	}
+	if filled := mesh.FillHoles(4); filled != 1 || mesh.FaceNumber() != 12 {
+		t.Fatalf("Expected one hole filled with 2 faces, got %d fills and %d faces", filled, mesh.FaceNumber())
+	}
+	if report := mesh.Validate(); !report.IsValid() {
+		t.Errorf("Expected a closed cube, got\n%v", report)
+	}
+	if math.Abs(mesh.SignedVolume()-8) > 1e-9 {
+		t.Errorf("Expected volume 8, got %v", mesh.SignedVolume())
+	}
+	if material, _ := mesh.FaceMaterial(11); material != red {
+		t.Errorf("Expected the patch to take the adjacent material, got %d", material)
+	}
+}
+
+func TestFillHoles_OpenSheet(t *testing.T) {
+	// The rim of a sheet bounds the whole surface rather than a hole in it
+	for _, mesh := range []*Mesh{CreatePlane(10, 10, 1, 1), CreatePlane(10, 10, 4, 4)} {
+		faces := mesh.FaceNumber()
+		report, err := mesh.Repair(DefaultRepairConfig())
+		if err != nil {
+			t.Fatalf("Repair failed: %v", err)
+		}
+		if report.FilledHoles != 0 || mesh.FaceNumber() != faces {
+			t.Errorf("Expected the open plane to keep its %d faces, got %d (%+v)", faces, mesh.FaceNumber(), report)
+		}
+	}
+}
+
+func TestRepair(t *testing.T) {
+	// An exploded, partly inverted cube with a missing side and junk faces
+	mesh := explode(CreateCube(2))
+	mesh.myFaces = mesh.myFaces[2:]
+	for _, f := range []int{0, 5} {
+		face := &mesh.myFaces[f]
+		face.myVertexIndices[1], face.myVertexIndices[2] = face.myVertexIndices[2], face.myVertexIndices[1]
+	}
+	_, _ = mesh.AddFace(0, 1, 0)
+	mesh.myFaces = append(mesh.myFaces, mesh.myFaces[3])
+	mesh.AddVertex(NewVertex(10, 10, 10))
+
+	report, err := mesh.Repair(DefaultRepairConfig())
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+
+	if report.FilledHoles != 1 || report.AddedFaces != 2 {
+		t.Errorf("Expected one hole filled with 2 faces, got %+v", report)
+	}
+	if report.DegenerateFaces != 1 || report.DuplicateFaces != 1 {
+		t.Errorf("Expected one degenerate and one duplicate face, got %+v", report)
+	}
+	if report.FlippedFaces != 2 {
+		t.Errorf("Expected 2 flipped faces, got %+v", report)
+	}
+	if mesh.VertexNumber() != 8 || mesh.FaceNumber() != 12 {
+		t.Errorf("Expected 8 vertices and 12 faces, got %d and %d", mesh.VertexNumber(), mesh.FaceNumber())
+	}
+	if validation := mesh.Validate(); !validation.IsValid() || math.Abs(mesh.SignedVolume()-8) > 1e-9 {
+		t.Errorf("Expected a valid cube of volume 8, got %v\n%v", mesh.SignedVolume(), validation)
+	}
+
+	if _, err := mesh.Repair(RepairConfig{MaxHoleEdges: -1}); err == nil {
+		t.Errorf("Expected an error for a negative hole size")
+	}
+}
@@ -0,0 +1,285 @@
+// stl.go
+package geom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	stlFormatName      = "stl"
+	stlHeaderSize      = 80
+	stlBinaryPrefix    = stlHeaderSize + 4 // header + uint32 triangle count
+	stlBinaryFacetSize = 50                // normal, 3 vertices (12 float32) + uint16 attribute
+)
+
+// ReadSTL decodes an ASCII or binary STL stream into a mesh.
+// The encoding is detected automatically. Duplicate vertices are welded
+// into shared indices and facet normals stored in the file are preserved.
+func ReadSTL(r io.Reader) (*Mesh, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBinarySTL(data) {
+		return readBinarySTL(data)
+	}
+	return readASCIISTL(data)
+}
+
+// isBinarySTL guesses the STL encoding. Some exporters start binary headers
+// with "solid", so a size that matches the binary layout exactly wins over the keyword.
+func isBinarySTL(data []byte) bool {
+	if len(data) >= stlBinaryPrefix {
+		count := binary.LittleEndian.Uint32(data[stlHeaderSize:stlBinaryPrefix])
+		if uint64(stlBinaryPrefix)+uint64(count)*stlBinaryFacetSize == uint64(len(data)) {
+			return true
+		}
+	}
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return !bytes.HasPrefix(trimmed, []byte("solid"))
+}
+
+func readBinarySTL(data []byte) (*Mesh, error) {
+	if len(data) < stlBinaryPrefix {
+		return nil, truncatedError(stlFormatName, 0, "binary header needs %d bytes, got %d", stlBinaryPrefix, len(data))
+	}
+
+	count := binary.LittleEndian.Uint32(data[stlHeaderSize:stlBinaryPrefix])
+	expected := uint64(stlBinaryPrefix) + uint64(count)*stlBinaryFacetSize
+	if uint64(len(data)) < expected {
+		return nil, truncatedError(stlFormatName, 0, "header declares %d facets (%d bytes), got %d bytes", count, expected, len(data))
+	}
+
+	mesh := &Mesh{}
+	welder := newVertexWelder(mesh)
+	readFloat := func(offset int) float64 {
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset:])))
+	}
+
+	for i := 0; i < int(count); i++ {
+		offset := stlBinaryPrefix + i*stlBinaryFacetSize
+		normal := NewVector(readFloat(offset), readFloat(offset+4), readFloat(offset+8))
+
+		var indices [3]int
+		for k := 0; k < 3; k++ {
+			base := offset + 12 + k*12
+			indices[k] = welder.add(NewVertex(readFloat(base), readFloat(base+4), readFloat(base+8)))
+		}
+
+		if err := addSTLFacet(mesh, indices, normal); err != nil {
+			return nil, err
+		}
+	}
+
+	return mesh, nil
+}
+
+func readASCIISTL(data []byte) (*Mesh, error) {
+	mesh := &Mesh{}
+	welder := newVertexWelder(mesh)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	// next returns the fields of the next non-empty line
+	next := func() ([]string, bool) {
+		for scanner.Scan() {
+			line++
+			fields := strings.Fields(scanner.Text())
+			if len(fields) > 0 {
+				return fields, true
+			}
+		}
+		return nil, false
+	}
+	expect := func(keywords ...string) ([]string, error) {
+		fields, ok := next()
+		if !ok {
+			return nil, truncatedError(stlFormatName, line, "expected %q, got end of file", strings.Join(keywords, " "))
+		}
+		if len(fields) < len(keywords) {
+			return nil, malformedError(stlFormatName, line, "expected %q, got %q", strings.Join(keywords, " "), strings.Join(fields, " "))
+		}
+		for i, keyword := range keywords {
+			if !strings.EqualFold(fields[i], keyword) {
+				return nil, malformedError(stlFormatName, line, "expected %q, got %q", strings.Join(keywords, " "), strings.Join(fields, " "))
+			}
+		}
+		return fields[len(keywords):], nil
+	}
+	parseTriple := func(fields []string) (float64, float64, float64, error) {
+		if len(fields) != 3 {
+			return 0, 0, 0, malformedError(stlFormatName, line, "expected 3 coordinates, got %d", len(fields))
+		}
+		var values [3]float64
+		for i, field := range fields {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return 0, 0, 0, malformedError(stlFormatName, line, "invalid number %q", field)
+			}
+			values[i] = value
+		}
+		return values[0], values[1], values[2], nil
+	}
+
+	if _, err := expect("solid"); err != nil {
+		return nil, err
+	}
+
+	for {
+		fields, ok := next()
+		if !ok {
+			return nil, truncatedError(stlFormatName, line, "missing \"endsolid\"")
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "endsolid":
+			// Several solids may be concatenated in one file
+			fields, ok := next()
+			if !ok {
+				return mesh, scanner.Err()
+			}
+			if !strings.EqualFold(fields[0], "solid") {
+				return nil, malformedError(stlFormatName, line, "unexpected %q after \"endsolid\"", fields[0])
+			}
+		case "facet":
+			if len(fields) < 2 || !strings.EqualFold(fields[1], "normal") {
+				return nil, malformedError(stlFormatName, line, "expected \"facet normal\"")
+			}
+			nx, ny, nz, err := parseTriple(fields[2:])
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := expect("outer", "loop"); err != nil {
+				return nil, err
+			}
+
+			var indices [3]int
+			for k := 0; k < 3; k++ {
+				coords, err := expect("vertex")
+				if err != nil {
+					return nil, err
+				}
+				x, y, z, err := parseTriple(coords)
+				if err != nil {
+					return nil, err
+				}
+				indices[k] = welder.add(NewVertex(x, y, z))
+			}
+
+			if _, err := expect("endloop"); err != nil {
+				return nil, err
+			}
+			if _, err := expect("endfacet"); err != nil {
+				return nil, err
+			}
+
+			if err := addSTLFacet(mesh, indices, NewVector(nx, ny, nz)); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, malformedError(stlFormatName, line, "unexpected keyword %q", fields[0])
+		}
+	}
+}
+
+// addSTLFacet adds a face and keeps the stored normal unless the file left it zeroed.
+func addSTLFacet(mesh *Mesh, indices [3]int, normal Vector) error {
+	faceIndex, err := mesh.AddFace(indices[0], indices[1], indices[2])
+	if err != nil {
+		return err
+	}
+	if normal.Length() > 0 {
+		return mesh.SetFaceNormal(faceIndex, normal)
+	}
+	return nil
+}
+
+// WriteSTLBinary encodes the mesh as binary STL.
+func WriteSTLBinary(w io.Writer, m *Mesh) error {
+	buf := bufio.NewWriter(w)
+
+	var header [stlHeaderSize]byte
+	copy(header[:], "binary STL written by go4/geom")
+	if _, err := buf.Write(header[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.LittleEndian, uint32(len(m.myFaces))); err != nil {
+		return err
+	}
+
+	var facet [stlBinaryFacetSize]byte
+	putFloat := func(offset int, value float64) {
+		binary.LittleEndian.PutUint32(facet[offset:], math.Float32bits(float32(value)))
+	}
+	for _, face := range m.myFaces {
+		putFloat(0, face.myNormal.X())
+		putFloat(4, face.myNormal.Y())
+		putFloat(8, face.myNormal.Z())
+		for k, index := range face.myVertexIndices {
+			v := m.myVertices[index]
+			base := 12 + k*12
+			putFloat(base, v.X())
+			putFloat(base+4, v.Y())
+			putFloat(base+8, v.Z())
+		}
+		if _, err := buf.Write(facet[:]); err != nil {
+			return err
+		}
+	}
+
+	return buf.Flush()
+}
+
+// WriteSTLASCII encodes the mesh as ASCII STL using the given solid name.
+func WriteSTLASCII(w io.Writer, m *Mesh, name string) error {
+	buf := bufio.NewWriter(w)
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'e', -1, 64)
+	}
+
+	fmt.Fprintf(buf, "solid %s\n", name)
+	for _, face := range m.myFaces {
+		fmt.Fprintf(buf, "  facet normal %s %s %s\n", format(face.myNormal.X()), format(face.myNormal.Y()), format(face.myNormal.Z()))
+		fmt.Fprintf(buf, "    outer loop\n")
+		for _, index := range face.myVertexIndices {
+			v := m.myVertices[index]
+			fmt.Fprintf(buf, "      vertex %s %s %s\n", format(v.X()), format(v.Y()), format(v.Z()))
+		}
+		fmt.Fprintf(buf, "    endloop\n")
+		fmt.Fprintf(buf, "  endfacet\n")
+	}
+	fmt.Fprintf(buf, "endsolid %s\n", name)
+
+	return buf.Flush()
+}
+
+// vertexWelder merges vertices with identical coordinates into one mesh index.
+type vertexWelder struct {
+	mesh    *Mesh
+	indices map[Coords3d]int
+}
+
+func newVertexWelder(mesh *Mesh) *vertexWelder {
+	return &vertexWelder{
+		mesh:    mesh,
+		indices: make(map[Coords3d]int),
+	}
+}
+
+func (w *vertexWelder) add(v Vertex) int {
+	if index, ok := w.indices[v.myCoords]; ok {
+		return index
+	}
+	index := w.mesh.AddVertex(v)
+	w.indices[v.myCoords] = index
+	return index
+}
@@ -0,0 +1,171 @@
+package geom
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func assertMeshesEqual(t *testing.T, expected, actual *Mesh) {
+	t.Helper()
+
+	if actual.VertexNumber() != expected.VertexNumber() {
+		t.Fatalf("Expected %d vertices, got %d", expected.VertexNumber(), actual.VertexNumber())
+	}
+	if actual.FaceNumber() != expected.FaceNumber() {
+		t.Fatalf("Expected %d faces, got %d", expected.FaceNumber(), actual.FaceNumber())
+	}
+
+	for i := 0; i < expected.FaceNumber(); i++ {
+		for k := 0; k < 3; k++ {
+			want, _ := expected.VertexInFace(i, k)
+			got, _ := actual.VertexInFace(i, k)
+			if !want.myCoords.Equals(got.myCoords) {
+				t.Errorf("Face %d vertex %d: expected %v, got %v", i, k, want.myCoords, got.myCoords)
+			}
+		}
+		wantNormal, _ := expected.Normal(i)
+		gotNormal, _ := actual.Normal(i)
+		if !wantNormal.Equals(gotNormal) {
+			t.Errorf("Face %d normal: expected %v, got %v", i, wantNormal, gotNormal)
+		}
+	}
+}
+
+func TestSTL_BinaryRoundTrip(t *testing.T) {
+	cube := CreateCube(200)
+
+	var buf bytes.Buffer
+	if err := WriteSTLBinary(&buf, cube); err != nil {
+		t.Fatalf("WriteSTLBinary failed: %v", err)
+	}
+	if buf.Len() != stlBinaryPrefix+cube.FaceNumber()*stlBinaryFacetSize {
+		t.Errorf("Unexpected binary size %d", buf.Len())
+	}
+
+	loaded, err := ReadSTL(&buf)
+	if err != nil {
+		t.Fatalf("ReadSTL failed: %v", err)
+	}
+	assertMeshesEqual(t, cube, loaded)
+}
+
+func TestSTL_ASCIIRoundTrip(t *testing.T) {
+	tetra := CreateTetrahedron(260)
+
+	var buf bytes.Buffer
+	if err := WriteSTLASCII(&buf, tetra, "tetra"); err != nil {
+		t.Fatalf("WriteSTLASCII failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "solid tetra") {
+		t.Errorf("Expected ASCII output to start with solid name, got %q", buf.String()[:20])
+	}
+
+	loaded, err := ReadSTL(&buf)
+	if err != nil {
+		t.Fatalf("ReadSTL failed: %v", err)
+	}
+	assertMeshesEqual(t, tetra, loaded)
+}
+
+func TestSTL_PreservesStoredNormals(t *testing.T) {
+	input := `solid test
+facet normal 0 0 -1
+  outer loop
+    vertex 0 0 0
+    vertex 1 0 0
+    vertex 0 1 0
+  endloop
+endfacet
+endsolid test
+`
+	mesh, err := ReadSTL(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadSTL failed: %v", err)
+	}
+
+	// Файл хранит нормаль, противоположную порядку обхода вершин
+	normal, _ := mesh.Normal(0)
+	if !normal.Equals(NewVector(0, 0, -1)) {
+		t.Errorf("Expected stored normal (0, 0, -1), got %v", normal)
+	}
+}
+
+func TestSTL_BinaryHeaderStartingWithSolid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSTLBinary(&buf, CreateCube(2)); err != nil {
+		t.Fatalf("WriteSTLBinary failed: %v", err)
+	}
+	data := buf.Bytes()
+	copy(data, "solid but actually binary")
+
+	mesh, err := ReadSTL(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadSTL failed: %v", err)
+	}
+	if mesh.FaceNumber() != 12 {
+		t.Errorf("Expected 12 faces, got %d", mesh.FaceNumber())
+	}
+}
+
+func TestSTL_WeldsDuplicateVertices(t *testing.T) {
+	input := `solid quad
+facet normal 0 0 1
+  outer loop
+    vertex 0 0 0
+    vertex 1 0 0
+    vertex 1 1 0
+  endloop
+endfacet
+facet normal 0 0 1
+  outer loop
+    vertex 0 0 0
+    vertex 1 1 0
+    vertex 0 1 0
+  endloop
+endfacet
+endsolid quad
+`
+	mesh, err := ReadSTL(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadSTL failed: %v", err)
+	}
+	if mesh.VertexNumber() != 4 {
+		t.Errorf("Expected 4 welded vertices, got %d", mesh.VertexNumber())
+	}
+	if mesh.FaceNumber() != 2 {
+		t.Errorf("Expected 2 faces, got %d", mesh.FaceNumber())
+	}
+}
+
+func TestSTL_Errors(t *testing.T) {
+	var binaryCube bytes.Buffer
+	_ = WriteSTLBinary(&binaryCube, CreateCube(1))
+
+	cases := []struct {
+		name     string
+		input    []byte
+		expected error
+	}{
+		{"binary too short for header", []byte("abc"), ErrTruncated},
+		{"binary missing facets", binaryCube.Bytes()[:binaryCube.Len()-10], ErrTruncated},
+		{"ascii missing endsolid", []byte("solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\n"), ErrTruncated},
+		{"ascii bad number", []byte("solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 zero 0\n"), ErrMalformed},
+		{"ascii wrong keyword", []byte("solid x\nfacet normal 0 0 1\ninner loop\n"), ErrMalformed},
+		{"ascii two coordinates", []byte("solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0\n"), ErrMalformed},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadSTL(bytes.NewReader(test.input))
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, err)
+			}
+			var formatErr *FormatError
+			if !errors.As(err, &formatErr) {
+				t.Errorf("Expected *FormatError, got %T", err)
+			}
+		})
+	}
+}
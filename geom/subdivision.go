@@ -0,0 +1,651 @@
+// subdivision.go
+package geom
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// SubdivisionScheme selects the refinement rules of Mesh.Subdivide
+type SubdivisionScheme int
+
+const (
+	// SubdivisionLoop splits every triangle into four
+	SubdivisionLoop SubdivisionScheme = iota
+	// SubdivisionCatmullClark splits every polygon into quads, which are triangulated at the end
+	SubdivisionCatmullClark
+)
+
+// String returns the string representation of the scheme
+func (s SubdivisionScheme) String() string {
+	switch s {
+	case SubdivisionLoop:
+		return "Loop"
+	case SubdivisionCatmullClark:
+		return "Catmull-Clark"
+	default:
+		return "unknown"
+	}
+}
+
+// SubdivisionBoundary selects how border edges of open meshes are refined
+type SubdivisionBoundary int
+
+const (
+	// BoundarySmooth treats borders as creases, which rounds their corners
+	BoundarySmooth SubdivisionBoundary = iota
+	// BoundaryFixed keeps border vertices in place and splits border edges at
+	// their midpoints, so the outline does not change
+	BoundaryFixed
+)
+
+// SubdivisionConfig controls Mesh.Subdivide
+type SubdivisionConfig struct {
+	Scheme SubdivisionScheme
+	// Number of refinement steps; 0 leaves the mesh unchanged
+	Levels   int
+	Boundary SubdivisionBoundary
+	// Edges given by their two vertex indices that stay sharp at every level
+	Creases [][2]int
+	// Edges whose faces meet at an angle above this in radians are sharp as
+	// well; math.Pi keeps every edge smooth
+	CreaseAngle float64
+}
+
+func DefaultSubdivisionConfig() SubdivisionConfig {
+	return SubdivisionConfig{
+		Scheme:      SubdivisionLoop,
+		Levels:      2,
+		Boundary:    BoundarySmooth,
+		CreaseAngle: math.Pi,
+	}
+}
+
+// Subdivide refines the mesh towards a smooth surface. Loop subdivision works on
+// the triangles directly. Catmull–Clark first merges pairs of coplanar triangles
+// that form a convex quad, such as the sides of CreateCube, so that quad cages
+// subdivide symmetrically.
+//
+// Sharp edges (creases, borders and edges with more than two faces) are refined
+// as curves, and vertices where more than two of them meet stay in place. Faces
+// keep their material and group, UVs, colors and attributes are interpolated
+// linearly, and vertex normals are cleared since they no longer fit the surface.
+// Vertices at the same position are refined as one, staying split only where
+// their UVs, colors or attributes differ. Faces that repeat a position are dropped.
+func (m *Mesh) Subdivide(config SubdivisionConfig) error {
+	if config.Scheme < SubdivisionLoop || config.Scheme > SubdivisionCatmullClark {
+		return fmt.Errorf("unknown subdivision scheme %d", config.Scheme)
+	}
+	if config.Boundary < BoundarySmooth || config.Boundary > BoundaryFixed {
+		return fmt.Errorf("unknown subdivision boundary rule %d", config.Boundary)
+	}
+	if config.Levels < 0 {
+		return fmt.Errorf("subdivision levels must not be negative, got %d", config.Levels)
+	}
+	if !(config.CreaseAngle >= 0) {
+		return fmt.Errorf("crease angle must be a non-negative number, got %v", config.CreaseAngle)
+	}
+
+	cage, err := newSubdivisionCage(m, config)
+	if err != nil {
+		return err
+	}
+	if config.Levels == 0 {
+		return nil
+	}
+	for level := 0; level < config.Levels; level++ {
+		cage = cage.subdivide(config)
+	}
+	cage.writeTo(m)
+	return nil
+}
+
+// subdivisionCage is a polygon mesh with the per-vertex data that is carried
+// through the subdivision levels
+type subdivisionCage struct {
+	points []Vector
+	// UV, color and attribute values, interpolated linearly. Faces refer to
+	// them per corner, so seams of the data do not split the surface.
+	data      [][]float64
+	faces     [][]int
+	corners   [][]int
+	materials []int
+	groups    []int
+	// Data of the points without faces
+	pointData []int
+	// Sorted vertex pairs of the crease edges
+	sharp map[[2]int]bool
+}
+
+// cageEdge is an undirected cage edge with A < B and the faces that use it
+type cageEdge struct {
+	A, B  int
+	Faces []int
+}
+
+func newSubdivisionCage(m *Mesh, config SubdivisionConfig) (*subdivisionCage, error) {
+	cage := &subdivisionCage{
+		data:  packVertexData(m),
+		sharp: make(map[[2]int]bool),
+	}
+
+	// Vertices split at one position, e.g. along normal or UV seams, become
+	// one point, and those with equal data share it
+	var positions []Vertex
+	pointOf := make([]int, len(m.myVertices))
+	dataOf := make([]int, len(m.myVertices))
+	lookup := make(map[Coords3d]int)
+	var variants [][]int
+	for v, vertex := range m.myVertices {
+		// Adding zero turns -0 into +0
+		key := Coords3d{vertex.myCoords.X + 0, vertex.myCoords.Y + 0, vertex.myCoords.Z + 0}
+		p, ok := lookup[key]
+		if !ok {
+			p = len(positions)
+			lookup[key] = p
+			positions = append(positions, vertex)
+			cage.points = append(cage.points, NewVectorFromVertex(vertex))
+			variants = append(variants, nil)
+		}
+		pointOf[v] = p
+
+		dataOf[v] = v
+		if cage.data == nil {
+			continue
+		}
+		for _, u := range variants[p] {
+			if slices.Equal(cage.data[u], cage.data[v]) {
+				dataOf[v] = u
+				break
+			}
+		}
+		if dataOf[v] == v {
+			variants[p] = append(variants[p], v)
+		}
+	}
+	if cage.data != nil {
+		cage.pointData = make([]int, len(positions))
+		for p := range positions {
+			cage.pointData[p] = variants[p][0]
+		}
+	}
+
+	var valid []int
+	triangles := make([][3]int, len(m.myFaces))
+	corners := make([][3]int, len(m.myFaces))
+	normals := make([]Vector, len(m.myFaces))
+	edgeFaces := make(map[[2]int][]int)
+	for f, face := range m.myFaces {
+		for k, v := range face.myVertexIndices {
+			triangles[f][k] = pointOf[v]
+			corners[f][k] = dataOf[v]
+		}
+		if len(uniqueIndices(triangles[f])) < 3 {
+			continue
+		}
+		valid = append(valid, f)
+		normals[f] = ComputeNormal(positions[triangles[f][0]], positions[triangles[f][1]], positions[triangles[f][2]])
+		for k, a := range triangles[f] {
+			b := triangles[f][(k+1)%3]
+			key := [2]int{min(a, b), max(a, b)}
+			edgeFaces[key] = append(edgeFaces[key], f)
+		}
+	}
+	if cage.data == nil {
+		corners = nil
+	}
+
+	for _, crease := range config.Creases {
+		if min(crease[0], crease[1]) < 0 || max(crease[0], crease[1]) >= len(m.myVertices) {
+			return nil, fmt.Errorf("crease (%d, %d) is not an edge of the mesh", crease[0], crease[1])
+		}
+		a, b := pointOf[crease[0]], pointOf[crease[1]]
+		key := [2]int{min(a, b), max(a, b)}
+		if _, ok := edgeFaces[key]; !ok {
+			return nil, fmt.Errorf("crease (%d, %d) is not an edge of the mesh", crease[0], crease[1])
+		}
+		cage.sharp[key] = true
+	}
+	if config.CreaseAngle < math.Pi {
+		threshold := math.Cos(config.CreaseAngle)
+		for key, faces := range edgeFaces {
+			if len(faces) != 2 {
+				continue
+			}
+			a, b := normals[faces[0]], normals[faces[1]]
+			// Degenerate faces have no direction to compare
+			if a.Length() > 0 && b.Length() > 0 && a.Dot(b) < threshold {
+				cage.sharp[key] = true
+			}
+		}
+	}
+
+	paired := make(map[int]bool)
+	for _, f := range valid {
+		if paired[f] {
+			continue
+		}
+		paired[f] = true
+		polygon := triangles[f][:]
+		var polygonCorners []int
+		if corners != nil {
+			polygonCorners = corners[f][:]
+		}
+		if config.Scheme == SubdivisionCatmullClark {
+			if g, quad, quadCorners, ok := quadPartner(m, positions, triangles, corners, f, edgeFaces, cage.sharp, paired); ok {
+				paired[g] = true
+				polygon, polygonCorners = quad, quadCorners
+			}
+		}
+		cage.faces = append(cage.faces, polygon)
+		if corners != nil {
+			cage.corners = append(cage.corners, polygonCorners)
+		}
+		cage.materials = append(cage.materials, m.myFaces[f].myMaterial)
+		cage.groups = append(cage.groups, m.myFaces[f].myGroup)
+	}
+	return cage, nil
+}
+
+// quadPartner returns the face that forms a planar convex quad with face f
+// across the longest edge of both, together with the quad and its corner data
+// in winding order. Faces whose data differs along that edge are not paired.
+func quadPartner(m *Mesh, positions []Vertex, triangles, corners [][3]int, f int, edgeFaces map[[2]int][]int, sharp map[[2]int]bool, paired map[int]bool) (int, []int, []int, bool) {
+	i := longestEdgeFirst(positions, triangles[f])
+	a, b, c := triangles[f][i], triangles[f][(i+1)%3], triangles[f][(i+2)%3]
+	key := [2]int{min(a, b), max(a, b)}
+	faces := edgeFaces[key]
+	if len(faces) != 2 || sharp[key] {
+		return 0, nil, nil, false
+	}
+	g := faces[0]
+	if g == f {
+		g = faces[1]
+	}
+	if paired[g] || m.myFaces[g].myMaterial != m.myFaces[f].myMaterial || m.myFaces[g].myGroup != m.myFaces[f].myGroup {
+		return 0, nil, nil, false
+	}
+	j := longestEdgeFirst(positions, triangles[g])
+	if triangles[g][j] != b || triangles[g][(j+1)%3] != a {
+		return 0, nil, nil, false
+	}
+	d := triangles[g][(j+2)%3]
+
+	var quadCorners []int
+	if corners != nil {
+		if corners[g][j] != corners[f][(i+1)%3] || corners[g][(j+1)%3] != corners[f][i] {
+			return 0, nil, nil, false
+		}
+		quadCorners = []int{corners[f][i], corners[g][(j+2)%3], corners[f][(i+1)%3], corners[f][(i+2)%3]}
+	}
+
+	quad := []int{a, d, b, c}
+	normal := ComputeNormal(positions[a], positions[b], positions[c])
+	for k := range quad {
+		p0 := positions[quad[k]]
+		p1 := positions[quad[(k+1)%4]]
+		p2 := positions[quad[(k+2)%4]]
+		if corner := ComputeNormal(p0, p1, p2); corner.Dot(normal) < 1-1e-6 {
+			return 0, nil, nil, false
+		}
+	}
+	return g, quad, quadCorners, true
+}
+
+// longestEdgeFirst returns the corner of the triangle that starts its longest edge
+func longestEdgeFirst(positions []Vertex, triangle [3]int) int {
+	best, longest := 0, -1.0
+	for k := range triangle {
+		a := positions[triangle[k]]
+		if length := a.Distance(positions[triangle[(k+1)%3]]); length > longest {
+			best, longest = k, length
+		}
+	}
+	return best
+}
+
+// subdivide applies one refinement step and returns the new cage. Vertices keep
+// their indices, followed by one vertex per edge and, for Catmull–Clark, one per face.
+func (c *subdivisionCage) subdivide(config SubdivisionConfig) *subdivisionCage {
+	var edges []cageEdge
+	lookup := make(map[[2]int]int)
+	vertexEdges := make([][]int, len(c.points))
+	vertexFaces := make([][]int, len(c.points))
+	for f, polygon := range c.faces {
+		for k, a := range polygon {
+			b := polygon[(k+1)%len(polygon)]
+			key := [2]int{min(a, b), max(a, b)}
+			e, ok := lookup[key]
+			if !ok {
+				e = len(edges)
+				lookup[key] = e
+				edges = append(edges, cageEdge{A: key[0], B: key[1]})
+				vertexEdges[a] = append(vertexEdges[a], e)
+				vertexEdges[b] = append(vertexEdges[b], e)
+			}
+			edges[e].Faces = append(edges[e].Faces, f)
+			vertexFaces[a] = append(vertexFaces[a], f)
+		}
+	}
+	isSharp := func(e int) bool {
+		return len(edges[e].Faces) != 2 || c.sharp[[2]int{edges[e].A, edges[e].B}]
+	}
+
+	var facePoints []Vector
+	if config.Scheme == SubdivisionCatmullClark {
+		facePoints = make([]Vector, len(c.faces))
+		for f, polygon := range c.faces {
+			facePoints[f] = c.average(polygon)
+		}
+	}
+
+	edgePoints := make([]Vector, len(edges))
+	for e, edge := range edges {
+		point := c.average([]int{edge.A, edge.B})
+		if !isSharp(e) {
+			f0, f1 := edge.Faces[0], edge.Faces[1]
+			if config.Scheme == SubdivisionLoop {
+				// 3/8 of each endpoint and 1/8 of each opposite vertex
+				opposite := c.average([]int{oppositeVertex(c.faces[f0], edge), oppositeVertex(c.faces[f1], edge)})
+				point.Scale(3.0 / 4)
+				opposite.Scale(1.0 / 4)
+				point.Add(opposite)
+			} else {
+				centers := facePoints[f0].Added(facePoints[f1])
+				point.Add(centers.Multiplied(0.5))
+				point.Scale(0.5)
+			}
+		}
+		edgePoints[e] = point
+	}
+
+	vertexPoints := make([]Vector, len(c.points))
+	for v, p := range c.points {
+		var creases []int
+		fixed := false
+		for _, e := range vertexEdges[v] {
+			if isSharp(e) {
+				creases = append(creases, edges[e].A+edges[e].B-v)
+			}
+			if len(edges[e].Faces) == 1 && config.Boundary == BoundaryFixed {
+				fixed = true
+			}
+		}
+
+		n := float64(len(vertexEdges[v]))
+		switch {
+		case fixed || len(creases) > 2 || n == 0:
+			vertexPoints[v] = p
+		case len(creases) == 2:
+			// Cubic B-spline rule along the crease
+			point := c.average(creases)
+			point.Scale(1.0 / 4)
+			point.Add(p.Multiplied(3.0 / 4))
+			vertexPoints[v] = point
+		case config.Scheme == SubdivisionLoop:
+			cosine := 3.0/8 + math.Cos(2*math.Pi/n)/4
+			beta := (5.0/8 - cosine*cosine) / n
+			var neighbors Vector
+			for _, e := range vertexEdges[v] {
+				neighbors.Add(c.points[edges[e].A+edges[e].B-v])
+			}
+			neighbors.Scale(beta)
+			neighbors.Add(p.Multiplied(1 - n*beta))
+			vertexPoints[v] = neighbors
+		default:
+			// (F + 2R + (n-3)P) / n with the averages F of the face points
+			// and R of the edge midpoints
+			var faces, midpoints Vector
+			for _, f := range vertexFaces[v] {
+				faces.Add(facePoints[f])
+			}
+			faces.Scale(1 / float64(len(vertexFaces[v])))
+			for _, e := range vertexEdges[v] {
+				midpoints.Add(c.average([]int{edges[e].A, edges[e].B}))
+			}
+			midpoints.Scale(2 / n)
+			point := p.Multiplied(n - 3)
+			point.Add(faces)
+			point.Add(midpoints)
+			point.Scale(1 / n)
+			vertexPoints[v] = point
+		}
+	}
+
+	vertexNumber := len(c.points)
+	next := &subdivisionCage{
+		points:    append(append(vertexPoints, edgePoints...), facePoints...),
+		pointData: c.pointData,
+		sharp:     make(map[[2]int]bool),
+	}
+
+	// The data is split at the corner data of each edge, which only differs
+	// from the points along seams
+	dataLookup := make(map[[2]int]int)
+	faceData := 0
+	if c.data != nil {
+		next.data = append([][]float64(nil), c.data...)
+		for _, corners := range c.corners {
+			for k, a := range corners {
+				b := corners[(k+1)%len(corners)]
+				key := [2]int{min(a, b), max(a, b)}
+				if _, ok := dataLookup[key]; !ok {
+					dataLookup[key] = len(next.data)
+					next.data = append(next.data, c.averageData([]int{a, b}))
+				}
+			}
+		}
+		faceData = len(next.data)
+		if config.Scheme == SubdivisionCatmullClark {
+			for _, corners := range c.corners {
+				next.data = append(next.data, c.averageData(corners))
+			}
+		}
+	}
+
+	for key := range c.sharp {
+		middle := vertexNumber + lookup[key]
+		next.sharp[[2]int{key[0], middle}] = true
+		next.sharp[[2]int{key[1], middle}] = true
+	}
+
+	for f, polygon := range c.faces {
+		middle := func(k int) int {
+			a, b := polygon[k%len(polygon)], polygon[(k+1)%len(polygon)]
+			return vertexNumber + lookup[[2]int{min(a, b), max(a, b)}]
+		}
+		middleData := func(k int) int {
+			corners := c.corners[f]
+			a, b := corners[k%len(corners)], corners[(k+1)%len(corners)]
+			return dataLookup[[2]int{min(a, b), max(a, b)}]
+		}
+		var children, childCorners [][]int
+		if config.Scheme == SubdivisionLoop {
+			ab, bc, ca := middle(0), middle(1), middle(2)
+			children = [][]int{
+				{polygon[0], ab, ca},
+				{polygon[1], bc, ab},
+				{polygon[2], ca, bc},
+				{ab, bc, ca},
+			}
+			if c.data != nil {
+				corners := c.corners[f]
+				ab, bc, ca := middleData(0), middleData(1), middleData(2)
+				childCorners = [][]int{
+					{corners[0], ab, ca},
+					{corners[1], bc, ab},
+					{corners[2], ca, bc},
+					{ab, bc, ca},
+				}
+			}
+		} else {
+			center := vertexNumber + len(edges) + f
+			for k, v := range polygon {
+				children = append(children, []int{v, middle(k), center, middle(k + len(polygon) - 1)})
+				if c.data != nil {
+					childCorners = append(childCorners, []int{c.corners[f][k], middleData(k), faceData + f, middleData(k + len(polygon) - 1)})
+				}
+			}
+		}
+		for i, child := range children {
+			next.faces = append(next.faces, child)
+			if c.data != nil {
+				next.corners = append(next.corners, childCorners[i])
+			}
+			next.materials = append(next.materials, c.materials[f])
+			next.groups = append(next.groups, c.groups[f])
+		}
+	}
+	return next
+}
+
+// oppositeVertex returns the vertex of a triangle that is not on the edge
+func oppositeVertex(triangle []int, edge cageEdge) int {
+	for _, v := range triangle {
+		if v != edge.A && v != edge.B {
+			return v
+		}
+	}
+	return edge.A
+}
+
+func (c *subdivisionCage) average(indices []int) Vector {
+	var sum Vector
+	for _, v := range indices {
+		sum.Add(c.points[v])
+	}
+	sum.Scale(1 / float64(len(indices)))
+	return sum
+}
+
+func (c *subdivisionCage) averageData(indices []int) []float64 {
+	sum := make([]float64, len(c.data[indices[0]]))
+	for _, v := range indices {
+		for i, value := range c.data[v] {
+			sum[i] += value
+		}
+	}
+	for i := range sum {
+		sum[i] /= float64(len(indices))
+	}
+	return sum
+}
+
+// writeTo replaces the geometry of the mesh by the triangulated cage. Each
+// point becomes one vertex per distinct data of its corners, so the seams of
+// the data stay split.
+func (c *subdivisionCage) writeTo(m *Mesh) {
+	variants := make([][]int, len(c.points))
+	for f, polygon := range c.faces {
+		for k, p := range polygon {
+			if c.data == nil {
+				variants[p] = []int{0}
+			} else if !slices.Contains(variants[p], c.corners[f][k]) {
+				variants[p] = append(variants[p], c.corners[f][k])
+			}
+		}
+	}
+
+	var vertices []Vertex
+	var data [][]float64
+	index := make(map[[2]int]int)
+	for p, point := range c.points {
+		if variants[p] == nil {
+			variants[p] = []int{0}
+			if c.data != nil {
+				variants[p][0] = c.pointData[p]
+			}
+		}
+		for _, d := range variants[p] {
+			index[[2]int{p, d}] = len(vertices)
+			vertices = append(vertices, NewVertex(point.X(), point.Y(), point.Z()))
+			if c.data != nil {
+				data = append(data, c.data[d])
+			}
+		}
+	}
+
+	var uvs []Vertex2d
+	var colors []Color
+	if m.myUVs != nil {
+		uvs = make([]Vertex2d, len(vertices))
+	}
+	if m.myColors != nil {
+		colors = make([]Color, len(vertices))
+	}
+	attributes := make([]VertexAttribute, len(m.myAttributes))
+	for i, attribute := range m.myAttributes {
+		attributes[i] = VertexAttribute{Name: attribute.Name, Type: attribute.Type, Values: make([]float64, len(vertices))}
+	}
+	for v, values := range data {
+		if uvs != nil {
+			uvs[v] = NewVertex2d(values[0], values[1])
+			values = values[2:]
+		}
+		if colors != nil {
+			colors[v] = NewColor(values[0], values[1], values[2], values[3])
+			values = values[4:]
+		}
+		for i := range attributes {
+			value := values[i]
+			if attributes[i].Type != ScalarFloat64 && attributes[i].Type != ScalarFloat32 {
+				value = math.Round(value)
+			}
+			attributes[i].Values[v] = value
+		}
+	}
+
+	var faces []Triangle
+	for f, polygon := range c.faces {
+		corner := func(k int) int {
+			if c.data == nil {
+				return index[[2]int{polygon[k], 0}]
+			}
+			return index[[2]int{polygon[k], c.corners[f][k]}]
+		}
+		for k := 1; k+1 < len(polygon); k++ {
+			indices := [3]int{corner(0), corner(k), corner(k + 1)}
+			faces = append(faces, Triangle{
+				myVertexIndices: indices,
+				myNormal:        ComputeNormal(vertices[indices[0]], vertices[indices[1]], vertices[indices[2]]),
+				myMaterial:      c.materials[f],
+				myGroup:         c.groups[f],
+			})
+		}
+	}
+
+	m.myVertices = vertices
+	m.myFaces = faces
+	m.myNormals = nil
+	m.myUVs = uvs
+	m.myColors = colors
+	if m.myAttributes != nil {
+		m.myAttributes = attributes
+	}
+}
+
+// packVertexData returns the UV, color and attribute values of every vertex
+// as one slice, or nil if the mesh has none
+func packVertexData(m *Mesh) [][]float64 {
+	if m.myUVs == nil && m.myColors == nil && len(m.myAttributes) == 0 {
+		return nil
+	}
+	data := make([][]float64, len(m.myVertices))
+	for v := range data {
+		var values []float64
+		if m.myUVs != nil {
+			uv := m.myUVs[v]
+			values = append(values, uv.X(), uv.Y())
+		}
+		if m.myColors != nil {
+			color := m.myColors[v]
+			values = append(values, color.R, color.G, color.B, color.A)
+		}
+		for _, attribute := range m.myAttributes {
+			values = append(values, attribute.Values[v])
+		}
+		data[v] = values
+	}
+	return data
+}
@@ -0,0 +1,240 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSubdivide_Loop(t *testing.T) {
+	mesh := CreateOctahedron(1)
+	volume := mesh.SignedVolume()
+
+	config := DefaultSubdivisionConfig()
+	if err := mesh.Subdivide(config); err != nil {
+		t.Fatalf("Subdivide failed: %v", err)
+	}
+	// Each level adds a vertex per edge and splits every face into four
+	if mesh.VertexNumber() != 6+12+48 || mesh.FaceNumber() != 8*16 {
+		t.Fatalf("Expected 66 vertices and 128 faces, got %d and %d", mesh.VertexNumber(), mesh.FaceNumber())
+	}
+	if report := mesh.Validate(); !report.IsValid() {
+		t.Errorf("Expected a valid closed mesh, got\n%v", report)
+	}
+	// Loop surfaces lie inside the convex hull of the cage
+	if smooth := mesh.SignedVolume(); smooth <= 0 || smooth >= volume {
+		t.Errorf("Expected a volume between 0 and %v, got %v", volume, smooth)
+	}
+
+	unchanged := CreateOctahedron(1)
+	if err := unchanged.Subdivide(SubdivisionConfig{Levels: 0}); err != nil || unchanged.FaceNumber() != 8 {
+		t.Errorf("Expected zero levels to keep the mesh, got %d faces and error %v", unchanged.FaceNumber(), err)
+	}
+}
+
+func TestSubdivide_CatmullClarkCube(t *testing.T) {
+	cube := CreateCube(2)
+	config := SubdivisionConfig{Scheme: SubdivisionCatmullClark, Levels: 1, CreaseAngle: math.Pi}
+	if err := cube.Subdivide(config); err != nil {
+		t.Fatalf("Subdivide failed: %v", err)
+	}
+
+	// The cube sides are treated as six quads: 8 corners, 12 edge points and
+	// 6 face points, with 24 quads split into two triangles each
+	if cube.VertexNumber() != 26 || cube.FaceNumber() != 48 {
+		t.Fatalf("Expected 26 vertices and 48 faces, got %d and %d", cube.VertexNumber(), cube.FaceNumber())
+	}
+	if report := cube.Validate(); !report.IsValid() {
+		t.Errorf("Expected a valid closed mesh, got\n%v", report)
+	}
+
+	// A corner moves to (F + 2R) / 3 with F = 1/3 and R = 2/3 on every axis
+	for v := 0; v < 8; v++ {
+		vertex, _ := cube.Vertex(v)
+		for _, coordinate := range []float64{vertex.X(), vertex.Y(), vertex.Z()} {
+			if math.Abs(math.Abs(coordinate)-5.0/9) > 1e-12 {
+				t.Fatalf("Corner %d: expected coordinates of magnitude 5/9, got %v", v, vertex)
+			}
+		}
+	}
+
+	config.Levels = 2
+	smooth := CreateCube(2)
+	_ = smooth.Subdivide(config)
+	if smooth.VertexNumber() != 98 || smooth.FaceNumber() != 192 {
+		t.Errorf("Expected 98 vertices and 192 faces, got %d and %d", smooth.VertexNumber(), smooth.FaceNumber())
+	}
+}
+
+func TestSubdivide_SplitVertices(t *testing.T) {
+	// Computing normals splits the cube at its edges into 24 vertices, which
+	// still refine as one closed surface
+	cube := CreateCube(2)
+	if _, err := cube.ComputeVertexNormals(DefaultVertexNormalConfig()); err != nil {
+		t.Fatalf("ComputeVertexNormals failed: %v", err)
+	}
+	if err := cube.Subdivide(DefaultSubdivisionConfig()); err != nil {
+		t.Fatalf("Subdivide failed: %v", err)
+	}
+	if cube.VertexNumber() != 98 || cube.FaceNumber() != 192 {
+		t.Fatalf("Expected 98 vertices and 192 faces, got %d and %d", cube.VertexNumber(), cube.FaceNumber())
+	}
+	if report := cube.Validate(); !report.IsValid() {
+		t.Errorf("Expected a valid closed mesh, got\n%v", report)
+	}
+
+	// Colors that differ per side keep the vertices along the cube edges split
+	colored := CreateCube(2)
+	_, _ = colored.ComputeVertexNormals(DefaultVertexNormalConfig())
+	for v := 0; v < colored.VertexNumber(); v++ {
+		normal, _ := colored.VertexNormal(v)
+		_ = colored.SetVertexColor(v, NewColor(normal.X(), normal.Y(), normal.Z(), 1))
+	}
+	if err := colored.Subdivide(DefaultSubdivisionConfig()); err != nil {
+		t.Fatalf("Subdivide failed: %v", err)
+	}
+	// 8 corners on 3 sides, 3 points on each of the 12 edges on 2 sides and
+	// 54 points inside the sides
+	if colored.VertexNumber() != 8*3+12*3*2+54 || colored.FaceNumber() != 192 {
+		t.Fatalf("Expected 150 vertices and 192 faces, got %d and %d", colored.VertexNumber(), colored.FaceNumber())
+	}
+	if _, err := colored.Weld(0); err != nil {
+		t.Fatalf("Weld failed: %v", err)
+	}
+	if report := colored.Validate(); !report.IsValid() {
+		t.Errorf("Expected the sides to meet at the seams, got\n%v", report)
+	}
+}
+
+func TestSubdivide_Creases(t *testing.T) {
+	// With every cube edge sharp, corners stay and edges remain straight
+	cube := CreateCube(2)
+	config := SubdivisionConfig{Scheme: SubdivisionCatmullClark, Levels: 2, CreaseAngle: math.Pi / 4}
+	if err := cube.Subdivide(config); err != nil {
+		t.Fatalf("Subdivide failed: %v", err)
+	}
+	if math.Abs(cube.SignedVolume()-8) > 1e-9 {
+		t.Errorf("Expected the creased cube to keep volume 8, got %v", cube.SignedVolume())
+	}
+
+	// A crease loop around the top side keeps its corners at the top
+	top := CreateCube(2)
+	var corners []int
+	for v := 0; v < top.VertexNumber(); v++ {
+		if vertex, _ := top.Vertex(v); vertex.Z() > 0 {
+			corners = append(corners, v)
+		}
+	}
+	var creases [][2]int
+	topology := NewHalfEdgeMesh(top)
+	for i, a := range corners {
+		for _, b := range corners[i+1:] {
+			va, _ := top.Vertex(a)
+			vb, _ := top.Vertex(b)
+			if _, ok := topology.FindEdge(a, b); ok && math.Abs(va.Distance(vb)-2) < 1e-12 {
+				creases = append(creases, [2]int{a, b})
+			}
+		}
+	}
+	if len(creases) != 4 {
+		t.Fatalf("Expected 4 top side edges, got %v", creases)
+	}
+
+	if err := top.Subdivide(SubdivisionConfig{Scheme: SubdivisionLoop, Levels: 1, Creases: creases, CreaseAngle: math.Pi}); err != nil {
+		t.Fatalf("Subdivide failed: %v", err)
+	}
+	for _, v := range corners {
+		if vertex, _ := top.Vertex(v); math.Abs(vertex.Z()-1) > 1e-12 {
+			t.Errorf("Expected creased corner %d to stay at the top, got %v", v, vertex)
+		}
+	}
+	if vertex, _ := top.Vertex(0); vertex.Z() <= -1+1e-9 {
+		t.Errorf("Expected a smooth bottom corner to move inward, got %v", vertex)
+	}
+}
+
+func TestSubdivide_Boundary(t *testing.T) {
+	fixed := createGrid(4)
+	for v := 0; v < fixed.VertexNumber(); v++ {
+		vertex, _ := fixed.Vertex(v)
+		_ = fixed.SetVertexUV(v, NewVertex2d(vertex.X(), vertex.Y()))
+	}
+	area := fixed.SurfaceArea()
+	box := fixed.BoundingBox()
+
+	config := SubdivisionConfig{Scheme: SubdivisionLoop, Levels: 2, Boundary: BoundaryFixed, CreaseAngle: math.Pi}
+	if err := fixed.Subdivide(config); err != nil {
+		t.Fatalf("Subdivide failed: %v", err)
+	}
+	if math.Abs(fixed.SurfaceArea()-area) > 1e-9 {
+		t.Errorf("Expected fixed borders to keep the area %v, got %v", area, fixed.SurfaceArea())
+	}
+	assertVectorNear(t, "fixed outline", box.Size(), fixed.BoundingBox().Size())
+
+	// A regular flat grid keeps its parametrization, so the interpolated UVs
+	// still match the positions
+	for v := 0; v < fixed.VertexNumber(); v++ {
+		vertex, _ := fixed.Vertex(v)
+		uv, _ := fixed.VertexUV(v)
+		if math.Abs(uv.X()-vertex.X()) > 1e-9 || math.Abs(uv.Y()-vertex.Y()) > 1e-9 || vertex.Z() != 0 {
+			t.Fatalf("Vertex %d at %v has UV %v", v, vertex, uv)
+		}
+	}
+
+	smooth := createGrid(4)
+	config.Boundary = BoundarySmooth
+	_ = smooth.Subdivide(config)
+	if smooth.SurfaceArea() >= area {
+		t.Errorf("Expected smooth borders to round the corners, got area %v of %v", smooth.SurfaceArea(), area)
+	}
+}
+
+func TestSubdivide_KeepsFaceData(t *testing.T) {
+	cube := CreateCube(2)
+	red := cube.AddMaterial(NewMaterial("red"))
+	_ = cube.SetFaceMaterial(5, red)
+	_ = cube.SetVertexColor(0, NewColor(1, 0, 0, 1))
+	_ = cube.SetVertexNormal(0, NewVector(0, 0, 1))
+	_ = cube.SetVertexAttribute("label", ScalarUint8, []float64{1, 2, 3, 4, 5, 6, 7, 8})
+
+	if err := cube.Subdivide(SubdivisionConfig{Scheme: SubdivisionCatmullClark, Levels: 1, CreaseAngle: math.Pi}); err != nil {
+		t.Fatalf("Subdivide failed: %v", err)
+	}
+	reds := 0
+	for f := 0; f < cube.FaceNumber(); f++ {
+		if material, _ := cube.FaceMaterial(f); material == red {
+			reds++
+		}
+	}
+	// Face 5 no longer pairs with the other half of its side, so it is split
+	// as a triangle into 3 quads
+	if reds != 6 {
+		t.Errorf("Expected 6 red faces, got %d", reds)
+	}
+	if cube.HasVertexNormals() || !cube.HasVertexColors() {
+		t.Errorf("Expected vertex normals to be cleared and colors kept")
+	}
+	labels, _ := cube.VertexAttribute("label")
+	for v, value := range labels.Values {
+		if value != math.Round(value) {
+			t.Errorf("Vertex %d: expected an integer label, got %v", v, value)
+		}
+	}
+}
+
+func TestSubdivide_InvalidConfig(t *testing.T) {
+	for name, config := range map[string]SubdivisionConfig{
+		"scheme":       {Scheme: SubdivisionScheme(5)},
+		"boundary":     {Boundary: SubdivisionBoundary(-1)},
+		"levels":       {Levels: -1},
+		"crease angle": {CreaseAngle: math.NaN()},
+		"crease":       {Levels: 1, Creases: [][2]int{{0, 99}}},
+	} {
+		mesh := CreateCube(1)
+		if err := mesh.Subdivide(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if mesh.FaceNumber() != 12 {
+			t.Errorf("%s: expected the mesh to stay unchanged", name)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// transform.go
+package geom
+
+// Transform applies the matrix to every vertex of the mesh. Face and vertex
+// normals are transformed with the normal matrix, and when the transform
+// mirrors (negative determinant) the winding of every face is reversed so
+// that faces keep pointing outward.
+func (m *Mesh) Transform(matrix Matrix4) {
+	for i, v := range m.myVertices {
+		m.myVertices[i] = matrix.TransformVertex(v)
+	}
+	for i, normal := range m.myNormals {
+		m.myNormals[i] = matrix.TransformNormal(normal)
+	}
+
+	flipWinding := matrix.Determinant3() < 0
+	for i := range m.myFaces {
+		face := &m.myFaces[i]
+		face.myNormal = matrix.TransformNormal(face.myNormal)
+		if flipWinding {
+			face.myVertexIndices[1], face.myVertexIndices[2] = face.myVertexIndices[2], face.myVertexIndices[1]
+		}
+	}
+}
+
+// Transformed returns a transformed copy of the mesh, leaving m unchanged
+func (m *Mesh) Transformed(matrix Matrix4) *Mesh {
+	res := m.Clone()
+	res.Transform(matrix)
+	return res
+}
+
+// Translate moves the mesh by offset
+func (m *Mesh) Translate(offset Vector) {
+	m.Transform(NewTranslationMatrix(offset))
+}
+
+// Rotate rotates the mesh around the origin
+func (m *Mesh) Rotate(rotation Quaternion) {
+	m.Transform(rotation.Matrix())
+}
+
+// Scale scales the mesh relative to the origin
+func (m *Mesh) Scale(x, y, z float64) {
+	m.Transform(NewScaleMatrix(x, y, z))
+}
@@ -0,0 +1,247 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func assertVectorNear(t *testing.T, name string, expected, actual Vector) {
+	t.Helper()
+	if actual.Subtracted(expected).Length() > 1e-9 {
+		t.Errorf("%s: expected %v, got %v", name, expected, actual)
+	}
+}
+
+func TestMatrix4_ComposeAndInvert(t *testing.T) {
+	rotation := NewQuaternionFromAxisAngle(NewVector(0, 0, 1), math.Pi/2)
+	m := ComposeMatrix4(NewVector(10, 0, 0), rotation, NewVector(2, 2, 2))
+
+	// Scale, then rotate 90° around Z, then translate
+	moved := m.TransformVertex(NewVertex(1, 0, 0))
+	assertVectorNear(t, "transformed vertex", NewVector(10, 2, 0), NewVectorFromVertex(moved))
+
+	inverse, err := m.Inverted()
+	if err != nil {
+		t.Fatalf("Inverted failed: %v", err)
+	}
+	if !m.Multiplied(inverse).Equals(IdentityMatrix4()) {
+		t.Errorf("Expected m * m^-1 to be the identity, got %v", m.Multiplied(inverse))
+	}
+	back := inverse.TransformVertex(moved)
+	assertVectorNear(t, "round trip", NewVector(1, 0, 0), NewVectorFromVertex(back))
+
+	if _, err := NewScaleMatrix(1, 0, 1).Inverted(); err == nil {
+		t.Error("Expected error for singular matrix, got nil")
+	}
+}
+
+func TestMatrix4_InvertsSmallScales(t *testing.T) {
+	// Millimeter models scaled to meters have a determinant of 1e-9 and below
+	for _, scale := range []float64{1e-3, 1e-5} {
+		m := ComposeMatrix4(NewVector(1e6, 0, 0), IdentityQuaternion(), NewVector(scale, scale, scale))
+		inverse, err := m.Inverted()
+		if err != nil {
+			t.Fatalf("Scale %g: Inverted failed: %v", scale, err)
+		}
+		back := inverse.TransformVertex(m.TransformVertex(NewVertex(1, 2, 3)))
+		assertVectorNear(t, "round trip", NewVector(1, 2, 3), NewVectorFromVertex(back))
+	}
+	if _, err := NewScaleMatrix(1e-200, 1e-200, 1e-200).Inverted(); err == nil {
+		t.Error("Expected error for a determinant that underflows, got nil")
+	}
+}
+
+func TestMatrix4_TransposeAndLayouts(t *testing.T) {
+	values := [16]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	m := NewMatrix4(values)
+	if m.At(0, 3) != 4 || m.At(3, 0) != 13 {
+		t.Errorf("Expected row-major layout, got %v", m)
+	}
+	if m.Transposed().At(0, 3) != 13 {
+		t.Errorf("Expected transposed element 13, got %v", m.Transposed().At(0, 3))
+	}
+	if !NewMatrix4ColumnMajor(m.ColumnMajor()).Equals(m) {
+		t.Error("Expected column-major round trip to give the same matrix")
+	}
+}
+
+func TestMatrix4_TransformNormal(t *testing.T) {
+	// Squash along X: the plane x = y tilts, its normal must stay perpendicular
+	m := NewScaleMatrix(0.5, 1, 1)
+	tangent := m.TransformVector(NewVector(1, 1, 0))
+	normal := m.TransformNormal(NewVector(1, -1, 0))
+	if math.Abs(tangent.Dot(normal)) > 1e-9 {
+		t.Errorf("Expected transformed normal %v to stay perpendicular to %v", normal, tangent)
+	}
+	if math.Abs(normal.Length()-1) > 1e-9 {
+		t.Errorf("Expected unit normal, got length %f", normal.Length())
+	}
+
+	mirrored := NewScaleMatrix(-1, 1, 1).TransformNormal(NewVector(1, 0, 0))
+	assertVectorNear(t, "mirrored normal", NewVector(-1, 0, 0), mirrored)
+}
+
+func TestMatrix4_Decompose(t *testing.T) {
+	rotation := NewQuaternionFromEuler(0.3, -0.2, 1.1)
+	m := ComposeMatrix4(NewVector(1, 2, 3), rotation, NewVector(2, 3, 4))
+
+	translation, gotRotation, scale := m.Decompose()
+	assertVectorNear(t, "translation", NewVector(1, 2, 3), translation)
+	assertVectorNear(t, "scale", NewVector(2, 3, 4), scale)
+	if !gotRotation.EqualsRotation(rotation) {
+		t.Errorf("Expected rotation %v, got %v", rotation, gotRotation)
+	}
+}
+
+func TestNewLookAtMatrix(t *testing.T) {
+	view, err := NewLookAtMatrix(NewVertex(0, 0, 5), NewVertex(0, 0, 0), NewVector(0, 1, 0))
+	if err != nil {
+		t.Fatalf("NewLookAtMatrix failed: %v", err)
+	}
+	origin := view.TransformVertex(NewVertex(0, 0, 0))
+	assertVectorNear(t, "target", NewVector(0, 0, -5), NewVectorFromVertex(origin))
+	right := view.TransformVertex(NewVertex(1, 0, 5))
+	assertVectorNear(t, "right", NewVector(1, 0, 0), NewVectorFromVertex(right))
+
+	if _, err := NewLookAtMatrix(NewVertex(0, 0, 5), NewVertex(0, 0, 0), NewVector(0, 0, 1)); err == nil {
+		t.Error("Expected error for up vector parallel to the view direction, got nil")
+	}
+}
+
+func TestProjectionMatrices(t *testing.T) {
+	perspective, err := NewPerspectiveMatrix(math.Pi/2, 2, 1, 100)
+	if err != nil {
+		t.Fatalf("NewPerspectiveMatrix failed: %v", err)
+	}
+	near := perspective.TransformVertex(NewVertex(2, 1, -1))
+	assertVectorNear(t, "near corner", NewVector(1, 1, -1), NewVectorFromVertex(near))
+	far := perspective.TransformVertex(NewVertex(0, 0, -100))
+	assertVectorNear(t, "far center", NewVector(0, 0, 1), NewVectorFromVertex(far))
+
+	if _, err := NewPerspectiveMatrix(math.Pi/2, 1, 0, 10); err == nil {
+		t.Error("Expected error for zero near plane, got nil")
+	}
+
+	ortho, err := NewOrthographicMatrix(-2, 2, -1, 1, 1, 3)
+	if err != nil {
+		t.Fatalf("NewOrthographicMatrix failed: %v", err)
+	}
+	corner := ortho.TransformVertex(NewVertex(2, -1, -3))
+	assertVectorNear(t, "ortho corner", NewVector(1, -1, 1), NewVectorFromVertex(corner))
+}
+
+func TestQuaternion_RotationMatchesMatrix(t *testing.T) {
+	q := NewQuaternionFromAxisAngle(NewVector(1, 1, 0), 0.7)
+	v := NewVector(0.3, -2, 5)
+	assertVectorNear(t, "rotated vector", q.Matrix().TransformVector(v), q.RotateVector(v))
+
+	axis, angle := q.AxisAngle()
+	expectedAxis := NewVector(1, 1, 0)
+	expectedAxis.Normalize()
+	assertVectorNear(t, "axis", expectedAxis, axis)
+	if math.Abs(angle-0.7) > 1e-9 {
+		t.Errorf("Expected angle 0.7, got %f", angle)
+	}
+
+	back := q.Multiplied(q.Conjugated())
+	if !back.Equals(IdentityQuaternion()) {
+		t.Errorf("Expected q * q* to be the identity, got %v", back)
+	}
+	if !NewQuaternionFromMatrix(q.Matrix()).EqualsRotation(q) {
+		t.Errorf("Expected rotation to survive matrix conversion")
+	}
+}
+
+func TestQuaternion_Euler(t *testing.T) {
+	q := NewQuaternionFromEuler(0.4, -0.3, 2.0)
+	x, y, z := q.Euler()
+	if math.Abs(x-0.4) > 1e-9 || math.Abs(y+0.3) > 1e-9 || math.Abs(z-2.0) > 1e-9 {
+		t.Errorf("Expected (0.4, -0.3, 2.0), got (%f, %f, %f)", x, y, z)
+	}
+
+	// X is applied first and turns (0, 1, 0) into (0, 0, 1), which the Z rotation leaves alone
+	rotated := NewQuaternionFromEuler(math.Pi/2, 0, math.Pi/2).RotateVector(NewVector(0, 1, 0))
+	assertVectorNear(t, "euler order", NewVector(0, 0, 1), rotated)
+
+	locked := NewQuaternionFromEuler(0, math.Pi/2, 0.5)
+	_, y, _ = locked.Euler()
+	if math.Abs(y-math.Pi/2) > 1e-6 {
+		t.Errorf("Expected gimbal-locked pitch π/2, got %f", y)
+	}
+}
+
+func TestQuaternion_Slerp(t *testing.T) {
+	from := IdentityQuaternion()
+	to := NewQuaternionFromAxisAngle(NewVector(0, 0, 1), math.Pi/2)
+
+	half := from.Slerp(to, 0.5)
+	expected := NewQuaternionFromAxisAngle(NewVector(0, 0, 1), math.Pi/4)
+	if !half.EqualsRotation(expected) {
+		t.Errorf("Expected %v, got %v", expected, half)
+	}
+	if !from.Slerp(to, 0).EqualsRotation(from) || !from.Slerp(to, 1).EqualsRotation(to) {
+		t.Error("Expected slerp endpoints to match the inputs")
+	}
+
+	// The negated target is the same rotation, slerp must take the short way
+	negated := NewQuaternion(-to.X(), -to.Y(), -to.Z(), -to.W())
+	if !from.Slerp(negated, 0.5).EqualsRotation(expected) {
+		t.Errorf("Expected shortest-arc interpolation, got %v", from.Slerp(negated, 0.5))
+	}
+}
+
+func TestMesh_Transformed(t *testing.T) {
+	cube := CreateCube(2)
+	rotation := NewQuaternionFromAxisAngle(NewVector(0, 0, 1), math.Pi/2)
+	moved := cube.Transformed(ComposeMatrix4(NewVector(5, 0, 0), rotation, NewVector(1, 1, 1)))
+
+	original, _ := cube.Vertex(0)
+	if !NewVectorFromVertex(original).Equals(NewVector(1, -1, -1)) {
+		t.Errorf("Expected Transformed to leave the source mesh unchanged, got %v", original)
+	}
+
+	for f := 0; f < moved.FaceNumber(); f++ {
+		stored, _ := moved.Normal(f)
+		v1, _ := moved.VertexInFace(f, 0)
+		v2, _ := moved.VertexInFace(f, 1)
+		v3, _ := moved.VertexInFace(f, 2)
+		assertVectorNear(t, "face normal", ComputeNormal(v1, v2, v3), stored)
+	}
+
+	v, _ := moved.Vertex(0)
+	assertVectorNear(t, "moved vertex", NewVector(6, 1, -1), NewVectorFromVertex(v))
+}
+
+func TestMesh_TransformMirrorKeepsOutwardWinding(t *testing.T) {
+	cube := CreateCube(2)
+	cube.Scale(-1, 2, 1)
+
+	for f := 0; f < cube.FaceNumber(); f++ {
+		stored, _ := cube.Normal(f)
+		v1, _ := cube.VertexInFace(f, 0)
+		v2, _ := cube.VertexInFace(f, 1)
+		v3, _ := cube.VertexInFace(f, 2)
+		geometric := ComputeNormal(v1, v2, v3)
+		assertVectorNear(t, "mirrored face normal", geometric, stored)
+
+		// Outward: the normal points away from the cube center
+		center := NewVectorFromVertex(v1)
+		center.Add(NewVectorFromVertex(v2))
+		center.Add(NewVectorFromVertex(v3))
+		if center.Dot(geometric) <= 0 {
+			t.Errorf("Face %d points inward after mirroring", f)
+		}
+	}
+}
+
+func TestMesh_TransformVertexNormals(t *testing.T) {
+	mesh := &Mesh{}
+	mesh.AddVertex(NewVertex(0, 0, 0))
+	_ = mesh.SetVertexNormal(0, NewVector(1, 1, 0))
+	mesh.Scale(2, 1, 1)
+
+	normal, _ := mesh.VertexNormal(0)
+	expected := NewVector(1, 2, 0)
+	expected.Normalize()
+	assertVectorNear(t, "vertex normal", expected, normal)
+}
@@ -0,0 +1,116 @@
+// triangle_queries.go
+package geom
+
+import "math"
+
+// ClosestPointOnTriangle returns the point of triangle (a, b, c) nearest to p
+// and its barycentric weights for a, b and c (Ericson, "Real-Time Collision Detection" 5.1.5)
+func ClosestPointOnTriangle(p, a, b, c Vertex) (Vertex, [3]float64) {
+	ab := NewVectorFromVertices(a, b)
+	ac := NewVectorFromVertices(a, c)
+	ap := NewVectorFromVertices(a, p)
+
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a, [3]float64{1, 0, 0}
+	}
+
+	bp := NewVectorFromVertices(b, p)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b, [3]float64{0, 1, 0}
+	}
+
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return barycentricPoint(a, b, c, [3]float64{1 - v, v, 0})
+	}
+
+	cp := NewVectorFromVertices(c, p)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c, [3]float64{0, 0, 1}
+	}
+
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return barycentricPoint(a, b, c, [3]float64{1 - w, 0, w})
+	}
+
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return barycentricPoint(a, b, c, [3]float64{0, 1 - w, w})
+	}
+
+	denominator := va + vb + vc
+	if denominator == 0 {
+		// Degenerate triangle: every region test failed only through rounding
+		return a, [3]float64{1, 0, 0}
+	}
+	v := vb / denominator
+	w := vc / denominator
+	return barycentricPoint(a, b, c, [3]float64{1 - v - w, v, w})
+}
+
+func barycentricPoint(a, b, c Vertex, weights [3]float64) (Vertex, [3]float64) {
+	return NewVertex(
+		weights[0]*a.myCoords.X+weights[1]*b.myCoords.X+weights[2]*c.myCoords.X,
+		weights[0]*a.myCoords.Y+weights[1]*b.myCoords.Y+weights[2]*c.myCoords.Y,
+		weights[0]*a.myCoords.Z+weights[1]*b.myCoords.Z+weights[2]*c.myCoords.Z,
+	), weights
+}
+
+// TriangleOverlapsAABB reports whether triangle (a, b, c) and the box share a
+// point, using the separating axis test of Akenine-Möller
+func TriangleOverlapsAABB(a, b, c Vertex, box AABB) bool {
+	if box.IsEmpty() {
+		return false
+	}
+
+	center := box.Center().myCoords
+	half := box.Size()
+	half.Scale(0.5)
+	extent := half.myCoords
+
+	// Move the box to the origin
+	v := [3]Coords3d{a.myCoords, b.myCoords, c.myCoords}
+	for i := range v {
+		v[i].Subtract(center)
+	}
+	edges := [3]Coords3d{
+		v[1].Subtracted(v[0]),
+		v[2].Subtracted(v[1]),
+		v[0].Subtracted(v[2]),
+	}
+
+	separated := func(axis Coords3d) bool {
+		p0 := axis.X*v[0].X + axis.Y*v[0].Y + axis.Z*v[0].Z
+		p1 := axis.X*v[1].X + axis.Y*v[1].Y + axis.Z*v[1].Z
+		p2 := axis.X*v[2].X + axis.Y*v[2].Y + axis.Z*v[2].Z
+		r := extent.X*math.Abs(axis.X) + extent.Y*math.Abs(axis.Y) + extent.Z*math.Abs(axis.Z)
+		return math.Min(p0, math.Min(p1, p2)) > r || math.Max(p0, math.Max(p1, p2)) < -r
+	}
+
+	// Cross products of the box axes with the triangle edges
+	for _, e := range edges {
+		for _, axis := range []Coords3d{{0, -e.Z, e.Y}, {e.Z, 0, -e.X}, {-e.Y, e.X, 0}} {
+			if separated(axis) {
+				return false
+			}
+		}
+	}
+
+	// Box face normals
+	for _, axis := range []Coords3d{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		if separated(axis) {
+			return false
+		}
+	}
+
+	// Triangle plane
+	normal := Vector{edges[0]}.Cross(Vector{edges[1]})
+	return !separated(normal.myCoords)
+}
@@ -0,0 +1,148 @@
+// triangulate.go
+package geom
+
+import "math"
+
+// TriangulatePolygon splits a simple, roughly planar polygon into triangles.
+// Convex polygons are fanned, concave ones are ear-clipped. The returned triples
+// index into polygon and keep its winding.
+func TriangulatePolygon(polygon []Vertex) [][3]int {
+	n := len(polygon)
+	if n < 3 {
+		return nil
+	}
+	if n == 3 {
+		return [][3]int{{0, 1, 2}}
+	}
+
+	points := projectPolygon(polygon)
+	orientation := 1.0
+	if signedArea2d(points) < 0 {
+		orientation = -1.0
+	}
+
+	if isConvexPolygon2d(points, orientation) {
+		return fanTriangulation(makeRange(n))
+	}
+
+	remaining := makeRange(n)
+	triangles := make([][3]int, 0, n-2)
+	for len(remaining) > 3 {
+		earFound := false
+		for i := range remaining {
+			prev := remaining[(i+len(remaining)-1)%len(remaining)]
+			cur := remaining[i]
+			next := remaining[(i+1)%len(remaining)]
+			if !isEar(points, remaining, prev, cur, next, orientation) {
+				continue
+			}
+
+			triangles = append(triangles, [3]int{prev, cur, next})
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			earFound = true
+			break
+		}
+
+		// Self-intersecting or degenerate input: fan whatever is left
+		if !earFound {
+			break
+		}
+	}
+
+	return append(triangles, fanTriangulation(remaining)...)
+}
+
+// newellNormal returns the non-normalized normal of a polygon; robust for non-planar input
+func newellNormal(polygon []Vertex) Vector {
+	var x, y, z float64
+	for i := range polygon {
+		cur := polygon[i].myCoords
+		next := polygon[(i+1)%len(polygon)].myCoords
+		x += (cur.Y - next.Y) * (cur.Z + next.Z)
+		y += (cur.Z - next.Z) * (cur.X + next.X)
+		z += (cur.X - next.X) * (cur.Y + next.Y)
+	}
+	return NewVector(x, y, z)
+}
+
+// projectPolygon drops the dominant axis of the polygon normal
+func projectPolygon(polygon []Vertex) []Coords2d {
+	normal := newellNormal(polygon)
+	ax, ay, az := math.Abs(normal.X()), math.Abs(normal.Y()), math.Abs(normal.Z())
+
+	points := make([]Coords2d, len(polygon))
+	for i, v := range polygon {
+		switch {
+		case az >= ax && az >= ay:
+			points[i] = Coords2d{v.myCoords.X, v.myCoords.Y}
+		case ay >= ax:
+			points[i] = Coords2d{v.myCoords.Z, v.myCoords.X}
+		default:
+			points[i] = Coords2d{v.myCoords.Y, v.myCoords.Z}
+		}
+	}
+	return points
+}
+
+func signedArea2d(points []Coords2d) float64 {
+	area := 0.0
+	for i := range points {
+		cur := points[i]
+		next := points[(i+1)%len(points)]
+		area += cur.X*next.Y - next.X*cur.Y
+	}
+	return area / 2
+}
+
+func cross2d(a, b, c Coords2d) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func isConvexPolygon2d(points []Coords2d, orientation float64) bool {
+	n := len(points)
+	for i := range points {
+		if orientation*cross2d(points[i], points[(i+1)%n], points[(i+2)%n]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isEar(points []Coords2d, remaining []int, prev, cur, next int, orientation float64) bool {
+	a, b, c := points[prev], points[cur], points[next]
+	if orientation*cross2d(a, b, c) <= DefaultTolerance {
+		return false
+	}
+
+	for _, index := range remaining {
+		if index == prev || index == cur || index == next {
+			continue
+		}
+		p := points[index]
+		if p.Equals(a) || p.Equals(b) || p.Equals(c) {
+			continue
+		}
+		if orientation*cross2d(a, b, p) >= 0 &&
+			orientation*cross2d(b, c, p) >= 0 &&
+			orientation*cross2d(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func fanTriangulation(indices []int) [][3]int {
+	triangles := make([][3]int, 0, len(indices))
+	for i := 1; i+1 < len(indices); i++ {
+		triangles = append(triangles, [3]int{indices[0], indices[i], indices[i+1]})
+	}
+	return triangles
+}
+
+func makeRange(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
@@ -0,0 +1,250 @@
+// validate.go
+package geom
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ValidationReport lists the defects found by Mesh.Validate. Every list is in
+// ascending order and empty for a clean mesh.
+type ValidationReport struct {
+	// Edges used by more than two faces
+	NonManifoldEdges []Edge
+	// Vertices on a non-manifold edge or whose faces form more than one fan
+	NonManifoldVertices []int
+	// Edges used by a single face, and the vertex loops they form
+	BoundaryEdges []Edge
+	Holes         [][]int
+	// Edges whose two faces traverse them in the same direction
+	InconsistentEdges []Edge
+	// Faces with repeated vertex indices or zero area
+	DegenerateFaces []int
+	// Faces using the same three vertices as an earlier face, in any order
+	DuplicateFaces []int
+	// Vertices not referenced by any face
+	UnusedVertices []int
+	// Vertices with a NaN or infinite coordinate
+	InvalidVertices []int
+	// Pairs of faces whose interiors cross; the lower index comes first
+	SelfIntersections [][2]int
+}
+
+// IsValid reports whether no defect was found
+func (r ValidationReport) IsValid() bool {
+	return len(r.OffendingFaces()) == 0 && len(r.OffendingVertices()) == 0
+}
+
+// IsWatertight reports whether the surface is closed and manifold
+func (r ValidationReport) IsWatertight() bool {
+	return len(r.BoundaryEdges) == 0 && len(r.NonManifoldEdges) == 0 && len(r.NonManifoldVertices) == 0
+}
+
+// OffendingFaces returns every face involved in a defect
+func (r ValidationReport) OffendingFaces() []int {
+	var faces []int
+	for _, edges := range [][]Edge{r.NonManifoldEdges, r.BoundaryEdges, r.InconsistentEdges} {
+		for _, edge := range edges {
+			faces = append(faces, edge.Faces...)
+		}
+	}
+	faces = append(faces, r.DegenerateFaces...)
+	faces = append(faces, r.DuplicateFaces...)
+	for _, pair := range r.SelfIntersections {
+		faces = append(faces, pair[0], pair[1])
+	}
+	slices.Sort(faces)
+	return slices.Compact(faces)
+}
+
+// OffendingVertices returns every vertex involved in a defect
+func (r ValidationReport) OffendingVertices() []int {
+	var vertices []int
+	for _, edges := range [][]Edge{r.NonManifoldEdges, r.BoundaryEdges, r.InconsistentEdges} {
+		for _, edge := range edges {
+			vertices = append(vertices, edge.V0, edge.V1)
+		}
+	}
+	vertices = append(vertices, r.NonManifoldVertices...)
+	vertices = append(vertices, r.UnusedVertices...)
+	vertices = append(vertices, r.InvalidVertices...)
+	slices.Sort(vertices)
+	return slices.Compact(vertices)
+}
+
+// String summarizes the report with one line per kind of defect
+func (r ValidationReport) String() string {
+	if r.IsValid() {
+		return "valid mesh"
+	}
+
+	var lines []string
+	add := func(count int, what string) {
+		if count > 0 {
+			lines = append(lines, fmt.Sprintf("%d %s", count, what))
+		}
+	}
+	add(len(r.NonManifoldEdges), "non-manifold edges")
+	add(len(r.NonManifoldVertices), "non-manifold vertices")
+	add(len(r.BoundaryEdges), "boundary edges")
+	add(len(r.Holes), "holes")
+	add(len(r.InconsistentEdges), "inconsistently oriented edges")
+	add(len(r.DegenerateFaces), "degenerate faces")
+	add(len(r.DuplicateFaces), "duplicate faces")
+	add(len(r.UnusedVertices), "unused vertices")
+	add(len(r.InvalidVertices), "invalid vertices")
+	add(len(r.SelfIntersections), "self-intersecting face pairs")
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks the mesh for topological and geometric defects. Coplanar
+// overlapping faces and faces that merely touch are not reported as
+// self-intersections.
+func (m *Mesh) Validate() ValidationReport {
+	var report ValidationReport
+	topology := NewHalfEdgeMesh(m)
+
+	edges := func(indices []int) []Edge {
+		var res []Edge
+		for _, edge := range indices {
+			res = append(res, topology.Edge(edge))
+		}
+		return res
+	}
+	report.NonManifoldEdges = edges(topology.NonManifoldEdges())
+	report.NonManifoldVertices = topology.NonManifoldVertices()
+	report.BoundaryEdges = edges(topology.BoundaryEdges())
+	report.Holes = topology.BoundaryLoops()
+	report.InconsistentEdges = edges(topology.InconsistentEdges())
+	for _, edgeList := range [][]Edge{report.NonManifoldEdges, report.BoundaryEdges, report.InconsistentEdges} {
+		sort.Slice(edgeList, func(i, j int) bool {
+			return edgeList[i].V0 < edgeList[j].V0 || (edgeList[i].V0 == edgeList[j].V0 && edgeList[i].V1 < edgeList[j].V1)
+		})
+	}
+
+	for v, vertex := range m.myVertices {
+		if len(topology.myVertexFaces[v]) == 0 {
+			report.UnusedVertices = append(report.UnusedVertices, v)
+		}
+		if !isFinite(vertex) {
+			report.InvalidVertices = append(report.InvalidVertices, v)
+		}
+	}
+
+	// Faces that take part in the self-intersection test
+	testable := &Mesh{myVertices: m.myVertices}
+	var testableFaces []int
+
+	seen := make(map[[3]int]bool, len(m.myFaces))
+	for f, face := range m.myFaces {
+		key := face.myVertexIndices
+		slices.Sort(key[:])
+		if seen[key] {
+			report.DuplicateFaces = append(report.DuplicateFaces, f)
+			continue
+		}
+		seen[key] = true
+
+		v1, v2, v3 := m.faceVertices(face)
+		if !isFinite(v1) || !isFinite(v2) || !isFinite(v3) {
+			continue
+		}
+		if isDegenerateTriangle(key, v1, v2, v3) {
+			report.DegenerateFaces = append(report.DegenerateFaces, f)
+			continue
+		}
+		testable.myFaces = append(testable.myFaces, face)
+		testableFaces = append(testableFaces, f)
+	}
+
+	report.SelfIntersections = selfIntersections(testable)
+	for i, pair := range report.SelfIntersections {
+		report.SelfIntersections[i] = [2]int{testableFaces[pair[0]], testableFaces[pair[1]]}
+	}
+
+	return report
+}
+
+func isFinite(v Vertex) bool {
+	for _, c := range []float64{v.myCoords.X, v.myCoords.Y, v.myCoords.Z} {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDegenerateTriangle reports repeated indices, or an area that is negligible
+// compared to the squared length of the longest side
+func isDegenerateTriangle(sortedIndices [3]int, v1, v2, v3 Vertex) bool {
+	if sortedIndices[0] == sortedIndices[1] || sortedIndices[1] == sortedIndices[2] {
+		return true
+	}
+	side1 := NewVectorFromVertices(v1, v2)
+	side2 := NewVectorFromVertices(v1, v3)
+	side3 := NewVectorFromVertices(v2, v3)
+	longest := max(side1.Length(), side2.Length(), side3.Length())
+	return side1.Cross(side2).Length() <= DefaultTolerance*longest*longest
+}
+
+// selfIntersections returns the pairs of faces whose interiors cross, found
+// by testing the edges of each face against the overlapping faces of a BVH
+func selfIntersections(m *Mesh) [][2]int {
+	bvh := NewBVHWithDefaults(m)
+
+	var pairs [][2]int
+	for f, face := range m.myFaces {
+		v1, v2, v3 := m.faceVertices(face)
+		box := NewAABB(v1, v2)
+		box.Extend(v3)
+
+		for _, other := range bvh.Overlapping(box) {
+			if other <= f || sharedVertices(face, m.myFaces[other]) >= 2 {
+				continue
+			}
+			w1, w2, w3 := m.faceVertices(m.myFaces[other])
+			if trianglesCross([3]Vertex{v1, v2, v3}, [3]Vertex{w1, w2, w3}) {
+				pairs = append(pairs, [2]int{f, other})
+			}
+		}
+	}
+	return pairs
+}
+
+func sharedVertices(a, b Triangle) int {
+	count := 0
+	for _, v := range a.myVertexIndices {
+		if slices.Contains(b.myVertexIndices[:], v) {
+			count++
+		}
+	}
+	return count
+}
+
+// trianglesCross reports whether an edge of either triangle pierces the
+// interior of the other. Contacts at edges and vertices within the tolerance
+// are ignored, so faces sharing a single vertex do not count as crossing.
+func trianglesCross(a, b [3]Vertex) bool {
+	return edgesPierce(a, b) || edgesPierce(b, a)
+}
+
+func edgesPierce(edges, triangle [3]Vertex) bool {
+	const tolerance = 1e-9
+	for k := 0; k < 3; k++ {
+		start, end := edges[k], edges[(k+1)%3]
+		length := start.Distance(end)
+		ray := NewRayThrough(start, end)
+		distance, u, v, hit := ray.IntersectTriangle(triangle[0], triangle[1], triangle[2])
+		if !hit {
+			continue
+		}
+		if distance > tolerance*length && distance < (1-tolerance)*length &&
+			u > tolerance && v > tolerance && u+v < 1-tolerance {
+			return true
+		}
+	}
+	return false
+}
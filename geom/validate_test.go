@@ -0,0 +1,122 @@
+package geom
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestValidate_CleanMeshes(t *testing.T) {
+	for name, mesh := range map[string]*Mesh{
+		"cube":        CreateCube(2),
+		"tetrahedron": CreateTetrahedron(1),
+	} {
+		report := mesh.Validate()
+		if !report.IsValid() || !report.IsWatertight() {
+			t.Errorf("%s: expected a valid watertight mesh, got\n%v", name, report)
+		}
+		if report.String() != "valid mesh" {
+			t.Errorf("%s: unexpected summary %q", name, report.String())
+		}
+	}
+}
+
+func TestValidate_OpenGrid(t *testing.T) {
+	report := createGrid(2).Validate()
+
+	if report.IsWatertight() {
+		t.Errorf("Expected an open grid not to be watertight")
+	}
+	if len(report.BoundaryEdges) != 8 || len(report.Holes) != 1 {
+		t.Errorf("Expected 8 boundary edges in one hole, got %d and %v", len(report.BoundaryEdges), report.Holes)
+	}
+	if got := report.OffendingVertices(); slices.Contains(got, 4) || len(got) != 8 {
+		t.Errorf("Expected the 8 rim vertices, got %v", got)
+	}
+	if len(report.SelfIntersections) != 0 || len(report.DegenerateFaces) != 0 {
+		t.Errorf("Expected no geometric defects, got\n%v", report)
+	}
+}
+
+func TestValidate_Defects(t *testing.T) {
+	mesh := CreateCube(2)
+	cubeFaces := mesh.FaceNumber()
+
+	unused := mesh.AddVertex(NewVertex(5, 5, 5))
+	invalid := mesh.AddVertex(NewVertex(math.NaN(), 0, 0))
+	collinear := mesh.AddVertex(NewVertex(0, -1, -1))
+
+	indices, _ := mesh.FaceIndices(0)
+	duplicate, _ := mesh.AddFace(indices[2], indices[0], indices[1])
+	// A sliver along the bottom edge from vertex 0 to vertex 1 through its midpoint
+	degenerate, _ := mesh.AddFace(0, collinear, 1)
+	nan, _ := mesh.AddFace(invalid, 0, 1)
+
+	report := mesh.Validate()
+
+	if !slices.Equal(report.UnusedVertices, []int{unused}) {
+		t.Errorf("Expected unused vertex %d, got %v", unused, report.UnusedVertices)
+	}
+	if !slices.Equal(report.InvalidVertices, []int{invalid}) {
+		t.Errorf("Expected invalid vertex %d, got %v", invalid, report.InvalidVertices)
+	}
+	if !slices.Equal(report.DuplicateFaces, []int{duplicate}) {
+		t.Errorf("Expected duplicate face %d, got %v", duplicate, report.DuplicateFaces)
+	}
+	if !slices.Equal(report.DegenerateFaces, []int{degenerate}) {
+		t.Errorf("Expected degenerate face %d, got %v", degenerate, report.DegenerateFaces)
+	}
+	if len(report.NonManifoldEdges) == 0 || len(report.NonManifoldVertices) == 0 {
+		t.Errorf("Expected the extra faces on the cube edge to be non-manifold, got\n%v", report)
+	}
+	if faces := report.OffendingFaces(); !slices.Contains(faces, nan) || slices.Contains(faces, cubeFaces-1) {
+		t.Errorf("Expected the added faces but not untouched cube faces, got %v", faces)
+	}
+	if report.IsValid() {
+		t.Errorf("Expected an invalid mesh")
+	}
+}
+
+func TestValidate_InconsistentWinding(t *testing.T) {
+	mesh := &Mesh{}
+	for _, v := range []Vertex{NewVertex(0, 0, 0), NewVertex(1, 0, 0), NewVertex(0, 1, 0), NewVertex(1, 1, 0)} {
+		mesh.AddVertex(v)
+	}
+	_, _ = mesh.AddFace(0, 1, 3)
+	_, _ = mesh.AddFace(0, 2, 3)
+
+	report := mesh.Validate()
+	if len(report.InconsistentEdges) != 1 {
+		t.Fatalf("Expected one inconsistent edge, got %v", report.InconsistentEdges)
+	}
+	if edge := report.InconsistentEdges[0]; edge.V0 != 0 || edge.V1 != 3 || !slices.Equal(edge.Faces, []int{0, 1}) {
+		t.Errorf("Expected edge 0-3 between faces 0 and 1, got %+v", edge)
+	}
+}
+
+func TestValidate_SelfIntersections(t *testing.T) {
+	first := CreateCube(2)
+	second := CreateCube(2)
+	second.Translate(NewVector(0.5, 0.3, 0.2))
+	mesh := MergeMeshes(first, second)
+
+	report := mesh.Validate()
+	if !report.IsWatertight() {
+		t.Errorf("Expected two closed cubes to be watertight, got\n%v", report)
+	}
+	if len(report.SelfIntersections) == 0 {
+		t.Fatalf("Expected overlapping cubes to self-intersect")
+	}
+	for _, pair := range report.SelfIntersections {
+		if pair[0] >= first.FaceNumber() || pair[1] < first.FaceNumber() {
+			t.Errorf("Expected pairs between the two cubes, got %v", pair)
+		}
+	}
+
+	// Separated cubes and faces sharing an edge or a vertex do not intersect
+	apart := CreateCube(2)
+	apart.Translate(NewVector(3, 0, 0))
+	if pairs := MergeMeshes(first, apart).Validate().SelfIntersections; len(pairs) != 0 {
+		t.Errorf("Expected no self-intersections, got %v", pairs)
+	}
+}
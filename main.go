@@ -1,35 +1,152 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"go4/geom"
+	"go4/geom/meshio"
 	"go4/vis"
 	"go4/vis/gui"
+	"math"
+	"os"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 func main() {
-	// Create application with default configuration
-	app, err := vis.NewApplicationWithDefaults()
+	flag.Usage = printUsage
+	listFormats := flag.Bool("formats", false, "list supported mesh formats and exit")
+	repair := flag.Bool("repair", false, "weld, reorient and close holes in the loaded model")
+	decimate := flag.Int("decimate", 0, "simplify the loaded model to at most this many faces")
+	lodLevels := flag.Int("lods", 0, "build this many simplified levels of detail for distant views")
+	renderPath := flag.String("render", "", "render the model headlessly to this .png or .ppm file and exit")
+	shadingName := flag.String("shading", "unlit", "light faces with unlit, flat, gouraud or phong shading")
+	flag.Parse()
+
+	if *listFormats {
+		printFormats()
+		return
+	}
+
+	// Load the model given on the command line, or fall back to a cube
+	config := vis.DefaultApplicationConfig()
+	mesh := geom.CreateCube(200)
+	if flag.NArg() > 0 {
+		loaded, warnings, err := meshio.LoadWithWarnings(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load %s: %v\n", flag.Arg(0), err)
+			printFormats()
+			os.Exit(1)
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", flag.Arg(0), warning)
+		}
+
+		if *repair {
+			report, err := loaded.Repair(geom.DefaultRepairConfig())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to repair %s: %v\n", flag.Arg(0), err)
+				os.Exit(1)
+			}
+			fmt.Printf("Repaired %s: %+v\n", flag.Arg(0), report)
+		}
+
+		if *decimate > 0 {
+			decimation := geom.DefaultDecimationConfig()
+			decimation.TargetFaces = *decimate
+			decimation.MaxError = math.Inf(1)
+			removed, err := loaded.Decimate(decimation)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to decimate %s: %v\n", flag.Arg(0), err)
+				os.Exit(1)
+			}
+			fmt.Printf("Decimated %s: removed %d faces, %d remain\n", flag.Arg(0), removed, loaded.FaceNumber())
+		}
+
+		// The camera orbits the origin, so center the model there and frame it
+		center := loaded.BoundingBox().Center()
+		loaded.Translate(geom.NewVectorFromVertices(center, geom.NewVertex(0, 0, 0)))
+		config.Camera = vis.FramedCameraConfig(config.Camera, loaded.BoundingSphere().Radius())
+		mesh = loaded
+	}
+
+	shading, err := vis.ParseShadingMode(*shadingName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid shading: %v\n", err)
+		os.Exit(1)
+	}
+	config.Renderer.Shading = shading
+	// Smooth shading interpolates vertex normals, so give the model some
+	if (shading == vis.ShadingGouraud || shading == vis.ShadingPhong) && !mesh.HasVertexNormals() {
+		if _, err := mesh.ComputeVertexNormals(geom.DefaultVertexNormalConfig()); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to compute vertex normals: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Render without opening a window for batch jobs
+	if *renderPath != "" {
+		camera, err := vis.NewCamera(config.Camera)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create camera: %v\n", err)
+			os.Exit(1)
+		}
+		scene := vis.NewScene()
+		scene.AddMesh(mesh)
+		img := vis.RenderToImage(scene, camera, config.Renderer, config.Width, config.Height)
+		if err := vis.SaveImage(*renderPath, img); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save %s: %v\n", *renderPath, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Rendered %s\n", *renderPath)
+		return
+	}
+
+	// Create application
+	app, err := vis.NewApplication(config)
 	if err != nil {
 		panic(err)
 	}
 
-	// Create a scene and add a cube
+	// Create a scene and add the mesh
 	scene := vis.NewScene()
-	cube := geom.CreateCube(200)
-	scene.AddMesh(cube)
+	scene.AddMesh(mesh)
 	app.AddScene(scene)
 
+	if *lodLevels > 0 {
+		lodConfig := vis.DefaultLODChainConfig()
+		lodConfig.Levels = *lodLevels
+		lods, err := vis.BuildLODChain(mesh, lodConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to build levels of detail: %v\n", err)
+			os.Exit(1)
+		}
+		_ = scene.SetMeshLODs(0, lods)
+		fmt.Printf("Built %d levels of detail\n", len(lods))
+	}
+
 	// Setup basic navigation GUI
-	setupNavigationGUI(app)
+	setupNavigationGUI(app, config.Camera)
 
 	// Run the application
 	app.Run()
 }
 
-func setupNavigationGUI(app *vis.Application) {
+func printUsage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [model file]\n", os.Args[0])
+	flag.PrintDefaults()
+	printFormats()
+}
+
+func printFormats() {
+	fmt.Fprintln(flag.CommandLine.Output(), "Supported mesh formats:")
+	for _, format := range meshio.Formats() {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s\n", format)
+	}
+}
+
+func setupNavigationGUI(app *vis.Application, cameraConfig vis.CameraConfig) {
 	guiManager := app.GetGUI()
 
 	// Create info panel
@@ -59,11 +176,20 @@ func setupNavigationGUI(app *vis.Application) {
 			camera.GetDistanceToScreen(),
 		)
 		infoPanel.SetSceneCount(len(app.GetScenes()))
+		if scenes := app.GetScenes(); len(scenes) > 0 && scenes[0].MeshCount() > 0 {
+			lods := scenes[0].GetMeshLODs(0)
+			level := app.GetRenderer().SelectLOD(scenes[0], 0)
+			faces := scenes[0].GetMeshes()[0].FaceNumber()
+			if level > 0 {
+				faces = lods[level-1].FaceNumber()
+			}
+			infoPanel.SetLODInfo(level, len(lods), faces)
+		}
 
 		// Handle navigation panel input
 		navPanel.HandleInput(deltaSeconds, gui.NavigationCallbacks{
 			OnReset: func() {
-				cam, _ := vis.NewCamera(vis.DefaultCameraConfig())
+				cam, _ := vis.NewCamera(cameraConfig)
 				app.GetRenderer().SetCamera(cam)
 				camera = cam
 			},
@@ -173,10 +173,8 @@ func (app *Application) Render() {
 	rl.BeginDrawing()
 	rl.ClearBackground(app.config.Renderer.BackgroundColor)
 
-	// Render 3D scenes
-	for _, scene := range app.scenes {
-		app.renderer.Render(scene)
-	}
+	// Render 3D scenes together so that faces are ordered across them
+	app.renderer.RenderScenes(app.scenes)
 
 	// Render GUI on top
 	app.gui.Draw()
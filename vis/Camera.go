@@ -9,10 +9,13 @@ import (
 
 // camera is the default implementation of Camera interface
 type camera struct {
-	radius           float64
-	polarAngle       float64
-	azimuth          float64
-	distanceToScreen float64
+	radius              float64
+	polarAngle          float64
+	azimuth             float64
+	distanceToScreen    float64
+	maxDistanceToScreen float64
+	nearDistance        float64
+	farDistance         float64
 }
 
 // CameraConfig holds configuration for creating a camera
@@ -21,6 +24,12 @@ type CameraConfig struct {
 	PolarAngle       float64
 	Azimuth          float64
 	DistanceToScreen float64
+	// Largest distance to screen zooming in can reach; 0 uses the radius
+	MaxDistanceToScreen float64
+	// Only what lies between these view depths is drawn; 0 uses the default
+	// distance scaled by the radius relative to the default radius
+	NearDistance float64
+	FarDistance  float64
 }
 
 // DefaultCameraConfig returns a default camera configuration
@@ -30,6 +39,8 @@ func DefaultCameraConfig() CameraConfig {
 		PolarAngle:       math.Pi / 4,
 		Azimuth:          math.Pi / 4,
 		DistanceToScreen: 500,
+		NearDistance:     1,
+		FarDistance:      100000,
 	}
 }
 
@@ -41,18 +52,63 @@ func NewCamera(config CameraConfig) (Camera, error) {
 	if config.DistanceToScreen < 0 {
 		return nil, fmt.Errorf("distance to screen must be non-negative, got %f", config.DistanceToScreen)
 	}
-	if config.DistanceToScreen > config.Radius {
-		return nil, fmt.Errorf("distance to screen (%f) cannot exceed radius (%f)", config.DistanceToScreen, config.Radius)
+	maxDistanceToScreen := config.MaxDistanceToScreen
+	if maxDistanceToScreen == 0 {
+		maxDistanceToScreen = config.Radius
+	}
+	if config.DistanceToScreen > maxDistanceToScreen {
+		return nil, fmt.Errorf("distance to screen (%f) cannot exceed %f", config.DistanceToScreen, maxDistanceToScreen)
+	}
+	// Unset clipping distances scale with the orbit like FramedCameraConfig does
+	defaults := DefaultCameraConfig()
+	if config.NearDistance == 0 {
+		config.NearDistance = defaults.NearDistance * config.Radius / defaults.Radius
+	}
+	if config.FarDistance == 0 {
+		config.FarDistance = defaults.FarDistance * config.Radius / defaults.Radius
+	}
+	if config.NearDistance <= 0 {
+		return nil, fmt.Errorf("near distance must be positive, got %f", config.NearDistance)
+	}
+	if config.FarDistance <= config.NearDistance {
+		return nil, fmt.Errorf("far distance (%f) must exceed near distance (%f)", config.FarDistance, config.NearDistance)
 	}
 
 	return &camera{
-		radius:           config.Radius,
-		polarAngle:       config.PolarAngle,
-		azimuth:          config.Azimuth,
-		distanceToScreen: config.DistanceToScreen,
+		radius:              config.Radius,
+		polarAngle:          config.PolarAngle,
+		azimuth:             config.Azimuth,
+		distanceToScreen:    config.DistanceToScreen,
+		maxDistanceToScreen: maxDistanceToScreen,
+		nearDistance:        config.NearDistance,
+		farDistance:         config.FarDistance,
 	}, nil
 }
 
+// framingDistanceFactor is the orbit radius in bounding radii; the default
+// camera looks at the 200-unit cube from about this distance
+const framingDistanceFactor = 6
+
+// FramedCameraConfig adapts config so that a model centered at the origin with
+// the given bounding radius appears about as large as the default cube. The
+// near and far distances scale with the orbit radius. The distance to screen
+// is in pixels rather than world units, so it and the zoom range are kept.
+func FramedCameraConfig(config CameraConfig, boundingRadius float64) CameraConfig {
+	if boundingRadius <= 0 {
+		return config
+	}
+	radius := boundingRadius * framingDistanceFactor
+	if config.Radius > 0 {
+		config.NearDistance *= radius / config.Radius
+		config.FarDistance *= radius / config.Radius
+		if config.MaxDistanceToScreen == 0 {
+			config.MaxDistanceToScreen = config.Radius
+		}
+	}
+	config.Radius = radius
+	return config
+}
+
 // NewCameraWithDefaults creates a new camera with default settings
 func NewCameraWithDefaults() Camera {
 	cam, _ := NewCamera(DefaultCameraConfig())
@@ -83,8 +139,8 @@ func (c *camera) ScaleLinear(distance float64) {
 		c.distanceToScreen = 0
 		return
 	}
-	if newDistance > c.radius {
-		c.distanceToScreen = c.radius
+	if newDistance > c.maxDistanceToScreen {
+		c.distanceToScreen = c.maxDistanceToScreen
 		return
 	}
 	c.distanceToScreen = newDistance
@@ -110,14 +166,32 @@ func (c *camera) GetDistanceToScreen() float64 {
 	return c.distanceToScreen
 }
 
-func (c *camera) fromWorldToView(v geom.Vertex) geom.Vertex {
-	x := -v.X()*math.Sin(c.polarAngle) + v.Y()*math.Cos(c.polarAngle)
-	y := -v.X()*math.Cos(c.azimuth)*math.Cos(c.polarAngle) - v.Y()*math.Cos(c.azimuth)*math.Sin(c.polarAngle) +
-		v.Z()*math.Sin(c.azimuth)
-	z := -v.X()*math.Sin(c.azimuth)*math.Cos(c.polarAngle) - v.Y()*math.Sin(c.azimuth)*math.Sin(c.polarAngle) -
-		v.Z()*math.Cos(c.azimuth) + c.radius
+// GetNearDistance returns the view depth below which nothing is drawn
+func (c *camera) GetNearDistance() float64 {
+	return c.nearDistance
+}
+
+// GetFarDistance returns the view depth beyond which nothing is drawn
+func (c *camera) GetFarDistance() float64 {
+	return c.farDistance
+}
+
+// ViewMatrix maps world space to view space: X to the right, Y up and Z along
+// the line of sight, with the camera on a sphere of the given radius around the origin
+func (c *camera) ViewMatrix() geom.Matrix4 {
+	sinP, cosP := math.Sin(c.polarAngle), math.Cos(c.polarAngle)
+	sinA, cosA := math.Sin(c.azimuth), math.Cos(c.azimuth)
+
+	return geom.NewMatrix4([16]float64{
+		-sinP, cosP, 0, 0,
+		-cosA * cosP, -cosA * sinP, sinA, 0,
+		-sinA * cosP, -sinA * sinP, -cosA, c.radius,
+		0, 0, 0, 1,
+	})
+}
 
-	return geom.NewVertex(x, y, z)
+func (c *camera) fromWorldToView(v geom.Vertex) geom.Vertex {
+	return c.ViewMatrix().TransformVertex(v)
 }
 
 const (
@@ -125,25 +199,25 @@ const (
 )
 
 func (c *camera) fromViewToScreen(v geom.Vertex, screenWidth, screenHeight int) geom.Vector2d {
-	var x, y float64
-
-	// Perspective projection
-	z := v.Z()
-	if math.Abs(z) < minZDistance {
-		// Если точка слишком близка к камере, используем ортогональную проекцию
-		x = v.X()
-		y = v.Y()
-	} else {
+	x, y := projectToScreen(v.X(), v.Y(), v.Z(), c.distanceToScreen, screenWidth, screenHeight)
+	return geom.NewVector2d(x, y)
+}
+
+// projectToScreen maps a view-space point to screen coordinates for a camera
+// with the given distance to the screen
+func projectToScreen(x, y, z, distanceToScreen float64, screenWidth, screenHeight int) (float64, float64) {
+	// Если точка слишком близка к камере, используем ортогональную проекцию
+	if math.Abs(z) >= minZDistance {
 		// Perspective projection
-		x = c.distanceToScreen * v.X() / z
-		y = c.distanceToScreen * v.Y() / z
+		x = distanceToScreen * x / z
+		y = distanceToScreen * y / z
 	}
 
 	// Центрирование на экране
 	x += float64(screenWidth) / 2
 	y = float64(screenHeight)/2 - y
 
-	return geom.NewVector2d(x, y)
+	return x, y
 }
 
 // Transform converts a world-space vertex to screen-space coordinates
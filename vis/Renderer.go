@@ -18,6 +18,14 @@ type RendererConfig struct {
 	DrawFaces          bool
 	DrawEdges          bool
 	UseBackfaceCulling bool
+	// Projected bounding radii in pixels: below each one, the next simplified
+	// level of detail is drawn. Empty always draws the full meshes.
+	LODThresholds []float64
+	// Order in which faces of all scenes are painted
+	DepthSort DepthSortMode
+	// How faces are lit, and the lights used unless faces are unlit
+	Shading  ShadingMode
+	Lighting Lighting
 }
 
 // DefaultRendererConfig returns default renderer configuration
@@ -31,6 +39,10 @@ func DefaultRendererConfig() RendererConfig {
 		DrawFaces:          true,
 		DrawEdges:          true,
 		UseBackfaceCulling: true,
+		LODThresholds:      []float64{60, 30, 15},
+		DepthSort:          DepthSortCentroid,
+		Shading:            ShadingUnlit,
+		Lighting:           DefaultLighting(),
 	}
 }
 
@@ -40,6 +52,8 @@ type renderer struct {
 	screenWidth  int
 	screenHeight int
 	config       RendererConfig
+	frustum      frustum
+	faces        []depthFace // Gathered for the current frame, reused between frames
 }
 
 // NewRenderer creates a new renderer with the given camera and configuration
@@ -57,12 +71,40 @@ func NewRendererWithDefaults(camera Camera) Renderer {
 
 // Render renders a scene
 func (r *renderer) Render(scene Scene) {
+	r.RenderScenes([]Scene{scene})
+}
+
+// RenderScenes gathers the visible faces of all scenes and paints them in the
+// order set by DepthSort, so that nearer faces cover and blend over farther
+// ones regardless of the scene and mesh they belong to. Meshes whose bounding
+// boxes lie outside the view frustum are skipped.
+func (r *renderer) RenderScenes(scenes []Scene) {
+	r.beginFrame()
+	for _, scene := range scenes {
+		for i, mesh := range scene.GetMeshes() {
+			if level := r.SelectLOD(scene, i); level > 0 {
+				mesh = scene.GetMeshLODs(i)[level-1]
+			}
+			if !r.frustum.culls(mesh.BoundingBox()) {
+				r.faces = r.gatherFaces(r.faces, mesh)
+			}
+		}
+	}
+	r.paintFaces(r.faces)
+}
+
+// beginFrame picks up the screen size and camera for a new frame
+func (r *renderer) beginFrame() {
 	r.screenWidth = rl.GetScreenWidth()
 	r.screenHeight = rl.GetScreenHeight()
+	r.frustum = newFrustum(r.camera, r.screenWidth, r.screenHeight)
+	r.faces = r.faces[:0]
+}
 
-	for _, mesh := range scene.GetMeshes() {
-		r.RenderMesh(mesh)
-	}
+// SelectLOD returns the level of detail drawn for the mesh at index in the
+// scene, chosen by the projected size of its bounding sphere
+func (r *renderer) SelectLOD(scene Scene, index int) int {
+	return selectLOD(scene, index, r.camera, r.config.LODThresholds)
 }
 
 // SetCamera sets the camera for rendering
@@ -75,11 +117,24 @@ func (r *renderer) GetCamera() Camera {
 	return r.camera
 }
 
-// RenderMesh renders all faces of the mesh
+// RenderMesh renders all faces of the mesh, ordered by DepthSort
 func (r *renderer) RenderMesh(mesh *geom.Mesh) {
-	faceNumber := mesh.FaceNumber()
+	r.beginFrame()
+	r.faces = r.gatherFaces(r.faces, mesh)
+	r.paintFaces(r.faces)
+}
+
+const (
+	maxColorValue = 255.0
+)
+
+// gatherFaces appends the faces of the mesh in view space, clipped to the view
+// frustum and lit for the shading mode. With backface culling, faces turned
+// away from the camera are left out; faces without area are always left out.
+func (r *renderer) gatherFaces(faces []depthFace, mesh *geom.Mesh) []depthFace {
 	cameraPosition := r.cameraPosition()
-	for i := 0; i < faceNumber; i++ {
+	view := r.camera.ViewMatrix()
+	for i := 0; i < mesh.FaceNumber(); i++ {
 		v1, err1 := mesh.VertexInFace(i, 0)
 		v2, err2 := mesh.VertexInFace(i, 1)
 		v3, err3 := mesh.VertexInFace(i, 2)
@@ -88,52 +143,171 @@ func (r *renderer) RenderMesh(mesh *geom.Mesh) {
 		if err1 != nil || err2 != nil || err3 != nil {
 			continue
 		}
+		if r.config.UseBackfaceCulling && !isFacingCamera(v1, v2, v3, cameraPosition) {
+			continue
+		}
 
-		r.renderFace(v1, v2, v3, cameraPosition)
+		p1 := geom.NewVectorFromVertex(view.TransformVertex(v1))
+		p2 := geom.NewVectorFromVertex(view.TransformVertex(v2))
+		p3 := geom.NewVectorFromVertex(view.TransformVertex(v3))
+		color := faceColor(r.config, r.toScreen(p1), r.toScreen(p2), r.toScreen(p3), r.screenWidth, r.screenHeight)
+		face, ok := newDepthFace(p1, p2, p3, color)
+		if !ok {
+			continue
+		}
+		if lighting := newFaceLighting(r.config, mesh, i, color, cameraPosition); lighting != nil {
+			if lighting.mode == ShadingFlat {
+				face.color = toRLColor(lighting.lit[0], color.A)
+			} else {
+				face.lighting = lighting
+			}
+		}
+		if face, ok = r.frustum.clip(face); ok {
+			faces = append(faces, face)
+		}
 	}
+	return faces
+}
+
+// paintFaces draws the faces and their edges in the order set by DepthSort.
+// Each face's edges are drawn right after it, so nearer faces cover them too.
+func (r *renderer) paintFaces(faces []depthFace) {
+	var screen []rl.Vector2
+	for _, face := range orderFaces(faces, r.config.DepthSort) {
+		screen = screen[:0]
+		for _, point := range face.points {
+			screen = append(screen, r.toScreen(point))
+		}
+
+		if r.config.DrawFaces {
+			for k := 1; k+1 < len(screen); k++ {
+				if face.lighting != nil {
+					r.drawLitTriangle(face, [3]int{0, k, k + 1}, [3]rl.Vector2{screen[0], screen[k], screen[k+1]})
+				} else {
+					drawTriangle(screen[0], screen[k], screen[k+1], face.color)
+				}
+			}
+		}
+
+		if r.config.DrawEdges {
+			for k, isOutline := range face.outline {
+				if isOutline {
+					rl.DrawLineV(screen[k], screen[(k+1)%len(screen)], r.config.EdgeColor)
+				}
+			}
+		}
+	}
+}
+
+// drawTriangle fills the triangle whichever way it is wound on screen, as
+// raylib only draws counter-clockwise triangles and back faces are clockwise
+func drawTriangle(a, b, c rl.Vector2, color rl.Color) {
+	if (b.X-a.X)*(c.Y-a.Y)-(b.Y-a.Y)*(c.X-a.X) > 0 {
+		b, c = c, b
+	}
+	rl.DrawTriangle(a, b, c, color)
 }
 
 const (
-	maxColorValue = 255.0
+	// Largest screen size in pixels of the triangles a Phong shaded face is
+	// cut into, and the most cuts along each edge
+	phongCellSize        = 12.0
+	maxPhongSubdivisions = 16
 )
 
-// renderFace performs backface culling before rendering the triangle and its edges.
-// This prevents invisible (back-facing) faces and lines from being drawn.
-func (r *renderer) renderFace(v1, v2, v3 geom.Vertex, cameraPosition geom.Vector) {
-
-	if r.config.UseBackfaceCulling && !r.checkVisibility(v1, v2, v3, cameraPosition) {
-		return
+// drawLitTriangle fills the triangle of the face with the given corners and
+// their screen positions, interpolating the lit colors of its vertices. For
+// Phong shading it is cut into a grid of small triangles lit at their
+// vertices, since raylib can only interpolate colors.
+func (r *renderer) drawLitTriangle(face depthFace, corners [3]int, screen [3]rl.Vector2) {
+	n := 1
+	if face.lighting.mode == ShadingPhong {
+		size := 0.0
+		for k := range screen {
+			next := screen[(k+1)%3]
+			size = max(size, math.Hypot(float64(next.X-screen[k].X), float64(next.Y-screen[k].Y)))
+		}
+		n = min(max(int(math.Ceil(size/phongCellSize)), 1), maxPhongSubdivisions)
 	}
 
-	// Transform vertices to screen space
-	v2d1, v2d2, v2d3 := r.convertTo2D(v1), r.convertTo2D(v2), r.convertTo2D(v3)
+	// Grid vertex (i, j) lies i/n of the way to the second corner and j/n of
+	// the way to the third
+	type gridVertex struct {
+		screen rl.Vector2
+		color  rl.Color
+	}
+	grid := make([]gridVertex, 0, (n+1)*(n+2)/2)
+	index := func(i, j int) int {
+		return i*(n+1) - i*(i-1)/2 + j
+	}
+	for i := 0; i <= n; i++ {
+		for j := 0; i+j <= n; j++ {
+			local := [3]float64{float64(n-i-j) / float64(n), float64(i) / float64(n), float64(j) / float64(n)}
+			var point geom.Vector
+			var weights [3]float64
+			for k, corner := range corners {
+				point.Add(face.points[corner].Multiplied(local[k]))
+				for w := range weights {
+					weights[w] += local[k] * face.weights[corner][w]
+				}
+			}
+			grid = append(grid, gridVertex{
+				screen: r.toScreen(point),
+				color:  toRLColor(face.lighting.colorAt(weights), face.color.A),
+			})
+		}
+	}
 
-	if r.config.DrawFaces {
-		rl.DrawTriangle(v2d1, v2d2, v2d3, r.getFaceColor(v2d1, v2d2, v2d3))
+	for i := 0; i < n; i++ {
+		for j := 0; i+j < n; j++ {
+			a, b, c := grid[index(i, j)], grid[index(i+1, j)], grid[index(i, j+1)]
+			drawShadedTriangle(a.screen, b.screen, c.screen, a.color, b.color, c.color)
+			if i+j+1 < n {
+				d := grid[index(i+1, j+1)]
+				drawShadedTriangle(b.screen, d.screen, c.screen, b.color, d.color, c.color)
+			}
+		}
 	}
+}
 
-	if r.config.DrawEdges {
-		rl.DrawTriangleLines(v2d1, v2d2, v2d3, r.config.EdgeColor)
+// drawShadedTriangle fills the triangle with the colors of its corners
+// interpolated across it, whichever way it is wound on screen
+func drawShadedTriangle(a, b, c rl.Vector2, colorA, colorB, colorC rl.Color) {
+	if (b.X-a.X)*(c.Y-a.Y)-(b.Y-a.Y)*(c.X-a.X) > 0 {
+		b, c = c, b
+		colorB, colorC = colorC, colorB
+	}
+	rl.Begin(rl.Triangles)
+	for _, corner := range []struct {
+		point rl.Vector2
+		color rl.Color
+	}{{a, colorA}, {b, colorB}, {c, colorC}} {
+		rl.Color4ub(corner.color.R, corner.color.G, corner.color.B, corner.color.A)
+		rl.Vertex2f(corner.point.X, corner.point.Y)
 	}
+	rl.End()
 }
 
-func (r *renderer) getFaceColor(v1, v2, v3 rl.Vector2) rl.Color {
-	faceColor := r.config.FaceColor
-	faceColor.A = r.config.AlphaValue
-	colorScaleFactor := float64(r.screenHeight) + float64(r.screenWidth)
-	if r.config.UseRandomFaceColor {
+// faceColor returns the configured face color, or with UseRandomFaceColor one
+// derived from the screen positions of the corners
+func faceColor(config RendererConfig, v1, v2, v3 rl.Vector2, screenWidth, screenHeight int) rl.Color {
+	faceColor := config.FaceColor
+	faceColor.A = config.AlphaValue
+	colorScaleFactor := float64(screenHeight) + float64(screenWidth)
+	if config.UseRandomFaceColor {
 		faceColor = rl.Color{
 			R: uint8((float64(v1.X) + float64(v1.Y)) / colorScaleFactor * maxColorValue),
 			G: uint8((float64(v2.Y) + float64(v2.X)) / colorScaleFactor * maxColorValue),
 			B: uint8((float64(v3.X) + float64(v3.Y)) / colorScaleFactor * maxColorValue),
-			A: r.config.AlphaValue,
+			A: config.AlphaValue,
 		}
 	}
 
 	return faceColor
 }
 
-func (r *renderer) checkVisibility(v1, v2, v3 geom.Vertex, cameraPosition geom.Vector) bool {
+// isFacingCamera reports whether the front side of the triangle is turned toward the camera
+func isFacingCamera(v1, v2, v3 geom.Vertex, cameraPosition geom.Vector) bool {
 	// Compute normal using original 3D vertices
 	// The camera looks toward -Z by convention
 	edge1 := geom.NewVectorFromVertices(v1, v2)
@@ -149,9 +323,14 @@ func (r *renderer) checkVisibility(v1, v2, v3 geom.Vertex, cameraPosition geom.V
 }
 
 func (r *renderer) cameraPosition() geom.Vector {
-	radius := r.camera.GetRadius()
-	polar := r.camera.GetPolarAngle()
-	azimuth := r.camera.GetAzimuth()
+	return cameraWorldPosition(r.camera)
+}
+
+// cameraWorldPosition returns the position of the camera on its orbit around the origin
+func cameraWorldPosition(camera Camera) geom.Vector {
+	radius := camera.GetRadius()
+	polar := camera.GetPolarAngle()
+	azimuth := camera.GetAzimuth()
 
 	sinAzimuth := math.Sin(azimuth)
 	cosAzimuth := math.Cos(azimuth)
@@ -165,12 +344,12 @@ func (r *renderer) cameraPosition() geom.Vector {
 	return geom.NewVector(x, y, z)
 }
 
-// convertTo2D transforms a 3D vertex to 2D screen coordinates
-func (r *renderer) convertTo2D(v geom.Vertex) rl.Vector2 {
-	vTransformed := r.camera.Transform(v, r.screenWidth, r.screenHeight)
+// toScreen projects a view-space point to screen coordinates
+func (r *renderer) toScreen(point geom.Vector) rl.Vector2 {
+	x, y := projectToScreen(point.X(), point.Y(), point.Z(), r.camera.GetDistanceToScreen(), r.screenWidth, r.screenHeight)
 	return rl.Vector2{
-		X: float32(vTransformed.X()),
-		Y: float32(vTransformed.Y()),
+		X: float32(x),
+		Y: float32(y),
 	}
 }
 
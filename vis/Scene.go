@@ -9,6 +9,7 @@ import (
 // scene is the default implementation of Scene interface
 type scene struct {
 	meshes []*geom.Mesh
+	lods   [][]*geom.Mesh // simplified versions of each mesh, or nil
 }
 
 // NewScene creates a new empty scene
@@ -24,6 +25,7 @@ func (s *scene) AddMesh(m *geom.Mesh) {
 		return
 	}
 	s.meshes = append(s.meshes, m)
+	s.lods = append(s.lods, nil)
 }
 
 // RemoveMesh removes a mesh from the scene by index
@@ -34,6 +36,7 @@ func (s *scene) RemoveMesh(index int) error {
 	
 	// Remove element by creating new slice without it
 	s.meshes = append(s.meshes[:index], s.meshes[index+1:]...)
+	s.lods = append(s.lods[:index], s.lods[index+1:]...)
 	return nil
 }
 
@@ -48,9 +51,35 @@ func (s *scene) GetMeshes() []*geom.Mesh {
 // Clear removes all meshes from the scene
 func (s *scene) Clear() {
 	s.meshes = make([]*geom.Mesh, 0)
+	s.lods = nil
 }
 
 // MeshCount returns the number of meshes in the scene
 func (s *scene) MeshCount() int {
 	return len(s.meshes)
 }
+
+// SetMeshLODs attaches simplified versions of the mesh at index, ordered from
+// most to least detailed; nil removes them
+func (s *scene) SetMeshLODs(index int, lods []*geom.Mesh) error {
+	if index < 0 || index >= len(s.meshes) {
+		return fmt.Errorf("mesh index out of bounds: %d (scene has %d meshes)", index, len(s.meshes))
+	}
+	for i, lod := range lods {
+		if lod == nil {
+			return fmt.Errorf("level of detail %d is nil", i+1)
+		}
+	}
+	s.lods[index] = append([]*geom.Mesh(nil), lods...)
+	return nil
+}
+
+// GetMeshLODs returns the simplified versions of the mesh at index, or nil
+func (s *scene) GetMeshLODs(index int) []*geom.Mesh {
+	if index < 0 || index >= len(s.meshes) || len(s.lods[index]) == 0 {
+		return nil
+	}
+	result := make([]*geom.Mesh, len(s.lods[index]))
+	copy(result, s.lods[index])
+	return result
+}
@@ -0,0 +1,359 @@
+// SoftwareRenderer.go
+package vis
+
+import (
+	"fmt"
+	"go4/geom"
+	"image"
+	"image/color"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// edgeDepthBias lets edges pass the depth test against the faces they border,
+// as a fraction of their depth
+const edgeDepthBias = 1e-4
+
+// softwareRenderer is the pure-Go implementation of SoftwareRenderer
+type softwareRenderer struct {
+	camera Camera
+	config RendererConfig
+	frame  *image.RGBA
+	depth  []float64 // view-space depth per pixel, +Inf where nothing was drawn
+}
+
+// NewSoftwareRenderer creates a renderer drawing into a width × height framebuffer
+func NewSoftwareRenderer(camera Camera, config RendererConfig, width, height int) (SoftwareRenderer, error) {
+	r := &softwareRenderer{
+		camera: camera,
+		config: config,
+	}
+	if err := r.Resize(width, height); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Resize replaces the framebuffer with a cleared one of the given size
+func (r *softwareRenderer) Resize(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid framebuffer size: %dx%d", width, height)
+	}
+	r.frame = image.NewRGBA(image.Rect(0, 0, width, height))
+	r.depth = make([]float64, width*height)
+	r.Clear()
+	return nil
+}
+
+// Clear fills the framebuffer with the background color and resets the depth buffer
+func (r *softwareRenderer) Clear() {
+	background := toRGBA(r.config.BackgroundColor)
+	for i := 0; i < len(r.frame.Pix); i += 4 {
+		r.frame.Pix[i] = background.R
+		r.frame.Pix[i+1] = background.G
+		r.frame.Pix[i+2] = background.B
+		r.frame.Pix[i+3] = background.A
+	}
+	for i := range r.depth {
+		r.depth[i] = math.Inf(1)
+	}
+}
+
+// Image returns the framebuffer
+func (r *softwareRenderer) Image() *image.RGBA {
+	return r.frame
+}
+
+// Depth returns the view-space depth at the pixel, or +Inf where nothing was drawn
+func (r *softwareRenderer) Depth(x, y int) float64 {
+	bounds := r.frame.Bounds()
+	if x < 0 || y < 0 || x >= bounds.Dx() || y >= bounds.Dy() {
+		return math.Inf(1)
+	}
+	return r.depth[y*bounds.Dx()+x]
+}
+
+// Render draws the meshes of the scene over the current framebuffer contents,
+// skipping meshes whose bounding boxes lie outside the view frustum
+func (r *softwareRenderer) Render(scene Scene) {
+	view := r.frustum()
+	for i, mesh := range scene.GetMeshes() {
+		if level := r.SelectLOD(scene, i); level > 0 {
+			mesh = scene.GetMeshLODs(i)[level-1]
+		}
+		if !view.culls(mesh.BoundingBox()) {
+			r.renderMesh(mesh, view)
+		}
+	}
+}
+
+// RenderScenes draws the meshes of all scenes; the depth buffer keeps the
+// nearest face at each pixel
+func (r *softwareRenderer) RenderScenes(scenes []Scene) {
+	for _, scene := range scenes {
+		r.Render(scene)
+	}
+}
+
+// SelectLOD returns the level of detail drawn for the mesh at index in the
+// scene, chosen by the projected size of its bounding sphere
+func (r *softwareRenderer) SelectLOD(scene Scene, index int) int {
+	return selectLOD(scene, index, r.camera, r.config.LODThresholds)
+}
+
+// SetCamera sets the camera for rendering
+func (r *softwareRenderer) SetCamera(camera Camera) {
+	r.camera = camera
+}
+
+// GetCamera returns the current camera
+func (r *softwareRenderer) GetCamera() Camera {
+	return r.camera
+}
+
+// SetConfig replaces renderer configuration at runtime.
+func (r *softwareRenderer) SetConfig(config RendererConfig) {
+	r.config = config
+}
+
+// GetConfig returns current renderer configuration.
+func (r *softwareRenderer) GetConfig() RendererConfig {
+	return r.config
+}
+
+// rasterVertex is a triangle corner in screen space with its view-space depth,
+// the color interpolated across the face and its barycentric weights in the
+// original face
+type rasterVertex struct {
+	x, y    float64
+	z       float64
+	color   [3]float64
+	weights [3]float64
+}
+
+// frustum returns the view frustum for the framebuffer size
+func (r *softwareRenderer) frustum() frustum {
+	return newFrustum(r.camera, r.frame.Bounds().Dx(), r.frame.Bounds().Dy())
+}
+
+// RenderMesh renders all faces of the mesh, clipped to the view frustum
+func (r *softwareRenderer) RenderMesh(mesh *geom.Mesh) {
+	r.renderMesh(mesh, r.frustum())
+}
+
+func (r *softwareRenderer) renderMesh(mesh *geom.Mesh, view frustum) {
+	cameraPosition := cameraWorldPosition(r.camera)
+	toView := r.camera.ViewMatrix()
+	width, height := r.frame.Bounds().Dx(), r.frame.Bounds().Dy()
+	distance := r.camera.GetDistanceToScreen()
+	toRaster := func(point geom.Vector) rasterVertex {
+		x, y := projectToScreen(point.X(), point.Y(), point.Z(), distance, width, height)
+		return rasterVertex{x: x, y: y, z: point.Z()}
+	}
+
+	for f := 0; f < mesh.FaceNumber(); f++ {
+		indices, err := mesh.FaceIndices(f)
+		if err != nil {
+			continue
+		}
+		var corners [3]geom.Vertex
+		var viewCorners [3]geom.Vertex
+		var points [3]geom.Vector
+		for k, index := range indices {
+			corners[k], _ = mesh.Vertex(index)
+			viewCorners[k] = toView.TransformVertex(corners[k])
+			points[k] = geom.NewVectorFromVertex(viewCorners[k])
+		}
+		if r.config.UseBackfaceCulling && !isFacingCamera(corners[0], corners[1], corners[2], cameraPosition) {
+			continue
+		}
+
+		face, ok := newDepthFace(points[0], points[1], points[2], rl.Color{})
+		if !ok {
+			continue
+		}
+		if face, ok = view.clip(face); !ok {
+			continue
+		}
+		raster := make([]rasterVertex, len(face.points))
+		for k, point := range face.points {
+			raster[k] = toRaster(point)
+			raster[k].weights = face.weights[k]
+		}
+
+		if r.config.DrawFaces {
+			unclipped := [3]rasterVertex{toRaster(points[0]), toRaster(points[1]), toRaster(points[2])}
+			flat := faceColor(r.config,
+				rl.Vector2{X: float32(unclipped[0].x), Y: float32(unclipped[0].y)},
+				rl.Vector2{X: float32(unclipped[1].x), Y: float32(unclipped[1].y)},
+				rl.Vector2{X: float32(unclipped[2].x), Y: float32(unclipped[2].y)},
+				width, height)
+			var colors [3][3]float64
+			for k, index := range indices {
+				colors[k] = [3]float64{float64(flat.R), float64(flat.G), float64(flat.B)}
+				if vertexColor, err := mesh.VertexColor(index); err == nil {
+					colors[k] = [3]float64{vertexColor.R * maxColorValue, vertexColor.G * maxColorValue, vertexColor.B * maxColorValue}
+				}
+			}
+			lighting := newFaceLighting(r.config, mesh, f, flat, cameraPosition)
+
+			// Corners made by clipping take the color at their place on the face
+			for k, weights := range face.weights {
+				if lighting != nil {
+					lit := lighting.colorAt(weights)
+					raster[k].color = [3]float64{lit.R * maxColorValue, lit.G * maxColorValue, lit.B * maxColorValue}
+					continue
+				}
+				for c := range raster[k].color {
+					raster[k].color[c] = weights[0]*colors[0][c] + weights[1]*colors[1][c] + weights[2]*colors[2][c]
+				}
+			}
+			for k := 1; k+1 < len(raster); k++ {
+				r.fillTriangle([3]rasterVertex{raster[0], raster[k], raster[k+1]}, flat.A, lighting)
+			}
+		}
+
+		if r.config.DrawEdges {
+			for k, isOutline := range face.outline {
+				if isOutline {
+					r.drawLine(raster[k], raster[(k+1)%len(raster)], toRGBA(r.config.EdgeColor))
+				}
+			}
+		}
+	}
+}
+
+// edgeFunction is twice the signed area of the triangle a, b, (px, py)
+func edgeFunction(a, b rasterVertex, px, py float64) float64 {
+	return (b.x-a.x)*(py-a.y) - (b.y-a.y)*(px-a.x)
+}
+
+// isTopLeftEdge reports whether the edge from a to b is a top edge (horizontal
+// with the triangle below) or a left edge (the triangle lies to its right).
+// Pixel centers exactly on an edge belong to the triangle only for these edges,
+// so triangles sharing an edge never both draw a pixel.
+func isTopLeftEdge(a, b, opposite rasterVertex) bool {
+	if a.y == b.y {
+		return opposite.y > a.y
+	}
+	edgeX := a.x + (opposite.y-a.y)*(b.x-a.x)/(b.y-a.y)
+	return opposite.x > edgeX
+}
+
+// fillTriangle rasterizes the triangle with a depth test, sampling at pixel
+// centers and interpolating colors perspective-correctly. With Phong shading
+// the lighting is evaluated at every pixel instead.
+func (r *softwareRenderer) fillTriangle(v [3]rasterVertex, alpha uint8, lighting *faceLighting) {
+	area := edgeFunction(v[0], v[1], v[2].x, v[2].y)
+	if area == 0 || math.IsNaN(area) {
+		return
+	}
+
+	width, height := r.frame.Bounds().Dx(), r.frame.Bounds().Dy()
+	minX := max(0, int(math.Floor(min(v[0].x, v[1].x, v[2].x))))
+	maxX := min(width-1, int(math.Ceil(max(v[0].x, v[1].x, v[2].x))))
+	minY := max(0, int(math.Floor(min(v[0].y, v[1].y, v[2].y))))
+	maxY := min(height-1, int(math.Ceil(max(v[0].y, v[1].y, v[2].y))))
+
+	// Weight k belongs to corner k and is measured against the opposite edge
+	topLeft := [3]bool{
+		isTopLeftEdge(v[1], v[2], v[0]),
+		isTopLeftEdge(v[2], v[0], v[1]),
+		isTopLeftEdge(v[0], v[1], v[2]),
+	}
+	covers := func(weight float64, k int) bool {
+		return weight*area > 0 || (weight == 0 && topLeft[k])
+	}
+
+	for y := minY; y <= maxY; y++ {
+		py := float64(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float64(x) + 0.5
+			w := [3]float64{
+				edgeFunction(v[1], v[2], px, py),
+				edgeFunction(v[2], v[0], px, py),
+				edgeFunction(v[0], v[1], px, py),
+			}
+			if !covers(w[0], 0) || !covers(w[1], 1) || !covers(w[2], 2) {
+				continue
+			}
+
+			// Screen-space barycentrics weighted by 1/z give perspective-correct values
+			var inverseDepth float64
+			var perspective [3]float64
+			for k := range v {
+				perspective[k] = w[k] / area / v[k].z
+				inverseDepth += perspective[k]
+			}
+			depth := 1 / inverseDepth
+			pixel := y*width + x
+			if depth >= r.depth[pixel] {
+				continue
+			}
+			r.depth[pixel] = depth
+
+			var shade [3]float64
+			if lighting != nil && lighting.mode == ShadingPhong {
+				var weights [3]float64
+				for k := range v {
+					for c := range weights {
+						weights[c] += perspective[k] * depth * v[k].weights[c]
+					}
+				}
+				lit := lighting.colorAt(weights)
+				shade = [3]float64{lit.R * maxColorValue, lit.G * maxColorValue, lit.B * maxColorValue}
+			} else {
+				for k := range v {
+					for c := range shade {
+						shade[c] += perspective[k] * depth * v[k].color[c]
+					}
+				}
+			}
+			r.blend(x, y, color.RGBA{
+				R: uint8(math.Round(math.Max(0, math.Min(maxColorValue, shade[0])))),
+				G: uint8(math.Round(math.Max(0, math.Min(maxColorValue, shade[1])))),
+				B: uint8(math.Round(math.Max(0, math.Min(maxColorValue, shade[2])))),
+				A: alpha,
+			})
+		}
+	}
+}
+
+// drawLine draws a one pixel wide line that is hidden behind nearer faces
+func (r *softwareRenderer) drawLine(a, b rasterVertex, lineColor color.RGBA) {
+	width, height := r.frame.Bounds().Dx(), r.frame.Bounds().Dy()
+	steps := int(math.Ceil(math.Max(math.Abs(b.x-a.x), math.Abs(b.y-a.y))))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		x := int(math.Floor(a.x + (b.x-a.x)*t))
+		y := int(math.Floor(a.y + (b.y-a.y)*t))
+		if x < 0 || y < 0 || x >= width || y >= height {
+			continue
+		}
+		depth := 1 / ((1-t)/a.z + t/b.z)
+		if depth*(1-edgeDepthBias) > r.depth[y*width+x] {
+			continue
+		}
+		r.blend(x, y, lineColor)
+	}
+}
+
+// blend draws the color over the pixel with source-over alpha blending
+func (r *softwareRenderer) blend(x, y int, c color.RGBA) {
+	offset := r.frame.PixOffset(x, y)
+	pix := r.frame.Pix[offset : offset+4]
+	alpha := float64(c.A) / maxColorValue
+	pix[0] = uint8(math.Round(float64(c.R)*alpha + float64(pix[0])*(1-alpha)))
+	pix[1] = uint8(math.Round(float64(c.G)*alpha + float64(pix[1])*(1-alpha)))
+	pix[2] = uint8(math.Round(float64(c.B)*alpha + float64(pix[2])*(1-alpha)))
+	pix[3] = uint8(math.Round(float64(c.A) + float64(pix[3])*(1-alpha)))
+}
+
+// toRGBA converts a raylib color to the standard library type
+func toRGBA(c rl.Color) color.RGBA {
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
@@ -0,0 +1,111 @@
+package vis
+
+import (
+	"go4/geom"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestCamera_ViewMatrixMatchesSphericalPosition(t *testing.T) {
+	config := DefaultCameraConfig()
+	config.PolarAngle = 0.7
+	config.Azimuth = 1.2
+	cam, err := NewCamera(config)
+	if err != nil {
+		t.Fatalf("NewCamera failed: %v", err)
+	}
+	c := cam.(*camera)
+
+	// The origin lies straight ahead at the orbit radius
+	origin := c.fromWorldToView(geom.NewVertex(0, 0, 0))
+	if math.Abs(origin.X()) > 1e-9 || math.Abs(origin.Y()) > 1e-9 || math.Abs(origin.Z()-config.Radius) > 1e-9 {
+		t.Errorf("Expected origin at (0, 0, %f), got (%f, %f, %f)", config.Radius, origin.X(), origin.Y(), origin.Z())
+	}
+
+	// The camera position maps to the view origin
+	eye := geom.NewVertex(
+		config.Radius*math.Sin(config.Azimuth)*math.Cos(config.PolarAngle),
+		config.Radius*math.Sin(config.Azimuth)*math.Sin(config.PolarAngle),
+		config.Radius*math.Cos(config.Azimuth),
+	)
+	view := c.fromWorldToView(eye)
+	if geom.NewVectorFromVertex(view).Length() > 1e-9 {
+		t.Errorf("Expected camera position at view origin, got (%f, %f, %f)", view.X(), view.Y(), view.Z())
+	}
+
+	// View space keeps X right, Y up and Z forward, so the matrix is an orthonormal mirror
+	if math.Abs(c.ViewMatrix().Determinant3()+1) > 1e-9 {
+		t.Errorf("Expected left-handed orthonormal basis with determinant -1, got %f", c.ViewMatrix().Determinant3())
+	}
+}
+
+func TestCamera_NearAndFarDistances(t *testing.T) {
+	// Unset distances scale with the radius, as configs without them expect
+	cam, err := NewCamera(CameraConfig{Radius: 10, DistanceToScreen: 5})
+	if err != nil {
+		t.Fatalf("NewCamera without clipping distances failed: %v", err)
+	}
+	if math.Abs(cam.GetNearDistance()-0.01) > 1e-12 || math.Abs(cam.GetFarDistance()-1000) > 1e-9 {
+		t.Errorf("Expected near 0.01 and far 1000, got %v and %v", cam.GetNearDistance(), cam.GetFarDistance())
+	}
+
+	config := DefaultCameraConfig()
+	config.NearDistance = -1
+	if _, err := NewCamera(config); err == nil {
+		t.Errorf("Expected an error for a negative near distance")
+	}
+	config.NearDistance = 10
+	config.FarDistance = 10
+	if _, err := NewCamera(config); err == nil {
+		t.Errorf("Expected an error for a far distance not beyond the near distance")
+	}
+
+	// Framing a model scales the clipping distances with the orbit
+	framed := FramedCameraConfig(DefaultCameraConfig(), 1)
+	cam, err = NewCamera(framed)
+	if err != nil {
+		t.Fatalf("NewCamera failed: %v", err)
+	}
+	scale := framed.Radius / DefaultCameraConfig().Radius
+	if math.Abs(cam.GetNearDistance()-scale) > 1e-12 || math.Abs(cam.GetFarDistance()-100000*scale) > 1e-6 {
+		t.Errorf("Expected near %v and far %v, got %v and %v", scale, 100000*scale, cam.GetNearDistance(), cam.GetFarDistance())
+	}
+}
+
+func TestFramedCameraConfig_SizeIndependentOfRadius(t *testing.T) {
+	config := DefaultRendererConfig()
+	background := toRGBA(config.BackgroundColor)
+	coverage := func(radius float64) int {
+		scene := NewScene()
+		scene.AddMesh(geom.CreateIcosphere(radius, 2))
+		cam, err := NewCamera(FramedCameraConfig(DefaultCameraConfig(), radius))
+		if err != nil {
+			t.Fatalf("NewCamera failed for radius %v: %v", radius, err)
+		}
+		img := RenderToImage(scene, cam, config, 400, 300)
+		covered := 0
+		for y := 0; y < 300; y++ {
+			for x := 0; x < 400; x++ {
+				if color.RGBAModel.Convert(img.At(x, y)) != background {
+					covered++
+				}
+			}
+		}
+		return covered
+	}
+
+	expected := coverage(100)
+	for _, radius := range []float64{0.001, 1, 20, 5000} {
+		if got := coverage(radius); math.Abs(float64(got-expected)) > 0.01*float64(expected) {
+			t.Errorf("Radius %v: expected about %d covered pixels as for radius 100, got %d", radius, expected, got)
+		}
+	}
+
+	// Zooming keeps the range of the default camera
+	cam, _ := NewCamera(FramedCameraConfig(DefaultCameraConfig(), 1))
+	cam.ScaleLinear(1e6)
+	if cam.GetDistanceToScreen() != DefaultCameraConfig().Radius {
+		t.Errorf("Expected zooming in to stop at %v, got %v", DefaultCameraConfig().Radius, cam.GetDistanceToScreen())
+	}
+}
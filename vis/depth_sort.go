@@ -0,0 +1,252 @@
+// depth_sort.go
+package vis
+
+import (
+	"go4/geom"
+	"math"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DepthSortMode selects the order in which the renderer paints faces
+type DepthSortMode int
+
+const (
+	// DepthSortCentroid paints faces back to front by the view depth of their
+	// centroids. Faces overlapping in depth can still be painted in the wrong order.
+	DepthSortCentroid DepthSortMode = iota
+	// DepthSortBSP paints faces in exact back-to-front order from a BSP tree
+	// built every frame, cutting faces that intersect or overlap cyclically.
+	// Building costs up to quadratic time in the face count.
+	DepthSortBSP
+	// DepthSortNone paints faces in scene and storage order
+	DepthSortNone
+)
+
+// String returns the name of the depth sort mode
+func (m DepthSortMode) String() string {
+	switch m {
+	case DepthSortCentroid:
+		return "Centroid"
+	case DepthSortBSP:
+		return "BSP"
+	case DepthSortNone:
+		return "None"
+	default:
+		return "Unknown"
+	}
+}
+
+// depthFace is a convex polygon prepared for painting with its corners in view
+// space. outline[i] tells whether the edge from corner i to the next belongs
+// to the outline of the original face rather than to a cut.
+type depthFace struct {
+	points   []geom.Vector
+	weights  [][3]float64 // Barycentric weights of the corners in the original face
+	outline  []bool
+	plane    geom.Plane // The plane the face lies on, facing its front side
+	color    rl.Color
+	lighting *faceLighting // Nil for faces painted in the plain color
+}
+
+// newDepthFace returns the view-space triangle as a depth face, or false when
+// it has no area
+func newDepthFace(v1, v2, v3 geom.Vector, color rl.Color) (depthFace, bool) {
+	normal := v2.Subtracted(v1).Cross(v3.Subtracted(v1))
+	if length := normal.Length(); length == 0 || math.IsNaN(length) || math.IsInf(length, 0) {
+		return depthFace{}, false
+	}
+	return depthFace{
+		points:  []geom.Vector{v1, v2, v3},
+		weights: [][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		outline: []bool{true, true, true},
+		plane:   geom.NewPlane(normal, normal.Dot(v1)),
+		color:   color,
+	}, true
+}
+
+// depth returns the view depth of the centroid
+func (f depthFace) depth() float64 {
+	depth := 0.0
+	for _, point := range f.points {
+		depth += point.Z()
+	}
+	return depth / float64(len(f.points))
+}
+
+// orderFaces returns the faces in the order they are painted
+func orderFaces(faces []depthFace, mode DepthSortMode) []depthFace {
+	switch mode {
+	case DepthSortCentroid:
+		sortByDepth(faces)
+	case DepthSortBSP:
+		return bspOrder(faces)
+	}
+	return faces
+}
+
+// sortByDepth orders the faces from the farthest to the nearest centroid,
+// keeping the storage order of faces at the same depth
+func sortByDepth(faces []depthFace) {
+	depths := make([]float64, len(faces))
+	for i, face := range faces {
+		depths[i] = face.depth()
+	}
+	order := make([]int, len(faces))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return depths[order[i]] > depths[order[j]]
+	})
+
+	sorted := make([]depthFace, len(faces))
+	for i, k := range order {
+		sorted[i] = faces[k]
+	}
+	copy(faces, sorted)
+}
+
+// bspOrder returns the faces, cut where needed, in back-to-front order for a
+// viewer at the view-space origin
+func bspOrder(faces []depthFace) []depthFace {
+	scale := 0.0
+	for _, face := range faces {
+		for _, point := range face.points {
+			scale = max(scale, math.Abs(point.X()), math.Abs(point.Y()), math.Abs(point.Z()))
+		}
+	}
+	tolerance := geom.DefaultTolerance * max(scale, 1)
+	return appendBSPOrder(make([]depthFace, 0, len(faces)), faces, tolerance)
+}
+
+// appendBSPOrder splits the faces by the plane of the first one and appends
+// the side away from the viewer, the faces on the plane and then the near side
+func appendBSPOrder(ordered, faces []depthFace, tolerance float64) []depthFace {
+	if len(faces) == 0 {
+		return ordered
+	}
+	splitter := faces[0].plane
+	coplanar := []depthFace{faces[0]}
+	var front, back []depthFace
+	for _, face := range faces[1:] {
+		partition(splitter, face, tolerance, &coplanar, &front, &back)
+	}
+
+	// The viewer at the origin is behind the plane when its offset is positive,
+	// and then sees the front side first
+	if splitter.Offset() > 0 {
+		front, back = back, front
+	}
+	ordered = appendBSPOrder(ordered, back, tolerance)
+	ordered = append(ordered, coplanar...)
+	return appendBSPOrder(ordered, front, tolerance)
+}
+
+// Sides of a splitting plane, combined bitwise for faces
+const (
+	planeOn       = 0
+	planeFront    = 1
+	planeBack     = 2
+	planeSpanning = planeFront | planeBack
+)
+
+// sides returns the side of the plane of each corner, treating corners closer
+// than the tolerance as on it, and the sides combined
+func (f depthFace) sides(plane geom.Plane, tolerance float64) ([]int, int) {
+	sides := make([]int, len(f.points))
+	side := planeOn
+	for i, point := range f.points {
+		switch distance := plane.Normal().Dot(point) - plane.Offset(); {
+		case distance < -tolerance:
+			sides[i] = planeBack
+		case distance > tolerance:
+			sides[i] = planeFront
+		}
+		side |= sides[i]
+	}
+	return sides, side
+}
+
+// partition sorts the face into the lists by its side of the plane, cutting
+// it in two when it spans the plane
+func partition(plane geom.Plane, face depthFace, tolerance float64, coplanar, front, back *[]depthFace) {
+	sides, side := face.sides(plane, tolerance)
+	switch side {
+	case planeOn:
+		*coplanar = append(*coplanar, face)
+	case planeFront:
+		*front = append(*front, face)
+	case planeBack:
+		*back = append(*back, face)
+	case planeSpanning:
+		frontPart, backPart := face.split(plane, sides)
+		if len(frontPart.points) >= 3 {
+			*front = append(*front, frontPart)
+		}
+		if len(backPart.points) >= 3 {
+			*back = append(*back, backPart)
+		}
+	}
+}
+
+// split cuts the face along the plane, given the side of each corner
+func (f depthFace) split(plane geom.Plane, sides []int) (front, back depthFace) {
+	// Each new corner remembers the original edges it lies on, so that the
+	// outline flags can be carried over to the parts
+	var frontEdges, backEdges [][2]int
+	n := len(f.points)
+	for i, point := range f.points {
+		j := (i + 1) % n
+		edges := [2]int{(i + n - 1) % n, i}
+		if sides[i] != planeBack {
+			front.points = append(front.points, point)
+			front.weights = append(front.weights, f.weights[i])
+			frontEdges = append(frontEdges, edges)
+		}
+		if sides[i] != planeFront {
+			back.points = append(back.points, point)
+			back.weights = append(back.weights, f.weights[i])
+			backEdges = append(backEdges, edges)
+		}
+		if sides[i]|sides[j] == planeSpanning {
+			direction := f.points[j].Subtracted(point)
+			t := (plane.Offset() - plane.Normal().Dot(point)) / plane.Normal().Dot(direction)
+			crossing := direction.Multiplied(t)
+			crossing.Add(point)
+			var weights [3]float64
+			for k := range weights {
+				weights[k] = f.weights[i][k] + t*(f.weights[j][k]-f.weights[i][k])
+			}
+			front.points = append(front.points, crossing)
+			front.weights = append(front.weights, weights)
+			frontEdges = append(frontEdges, [2]int{i, i})
+			back.points = append(back.points, crossing)
+			back.weights = append(back.weights, weights)
+			backEdges = append(backEdges, [2]int{i, i})
+		}
+	}
+
+	front.outline = f.cutOutline(frontEdges)
+	back.outline = f.cutOutline(backEdges)
+	front.plane, front.color, front.lighting = f.plane, f.color, f.lighting
+	back.plane, back.color, back.lighting = f.plane, f.color, f.lighting
+	return front, back
+}
+
+// cutOutline returns the outline flags of a part whose corners lie on the
+// given edges of f: a part edge is outline when both its ends lie on the same
+// outline edge of f
+func (f depthFace) cutOutline(edges [][2]int) []bool {
+	outline := make([]bool, len(edges))
+	for k, current := range edges {
+		next := edges[(k+1)%len(edges)]
+		for _, edge := range current {
+			if (edge == next[0] || edge == next[1]) && f.outline[edge] {
+				outline[k] = true
+			}
+		}
+	}
+	return outline
+}
@@ -0,0 +1,136 @@
+package vis
+
+import (
+	"go4/geom"
+	"math"
+	"math/rand"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// paintedDepth returns the depth, along the view ray through (u, v) on the
+// plane z = 1, of the last face in painting order covering it, or +Inf
+func paintedDepth(faces []depthFace, u, v float64) float64 {
+	depth := math.Inf(1)
+	for _, face := range faces {
+		if covers(face, u, v) {
+			depth = rayDepth(face, u, v)
+		}
+	}
+	return depth
+}
+
+// nearestDepth returns the depth of the nearest face covering (u, v), or +Inf
+func nearestDepth(faces []depthFace, u, v float64) float64 {
+	depth := math.Inf(1)
+	for _, face := range faces {
+		if covers(face, u, v) {
+			depth = math.Min(depth, rayDepth(face, u, v))
+		}
+	}
+	return depth
+}
+
+// covers reports whether the convex face projects over (u, v)
+func covers(face depthFace, u, v float64) bool {
+	sign := 0.0
+	for k, point := range face.points {
+		next := face.points[(k+1)%len(face.points)]
+		ax, ay := point.X()/point.Z(), point.Y()/point.Z()
+		bx, by := next.X()/next.Z(), next.Y()/next.Z()
+		cross := (bx-ax)*(v-ay) - (by-ay)*(u-ax)
+		if cross*sign < 0 {
+			return false
+		}
+		if sign == 0 {
+			sign = cross
+		}
+	}
+	return true
+}
+
+func rayDepth(face depthFace, u, v float64) float64 {
+	return face.plane.Offset() / face.plane.Normal().Dot(geom.NewVector(u, v, 1))
+}
+
+func randomDepthFaces(random *rand.Rand, count int) []depthFace {
+	var faces []depthFace
+	for len(faces) < count {
+		var corners [3]geom.Vector
+		for k := range corners {
+			corners[k] = geom.NewVector(random.Float64()*20-10, random.Float64()*20-10, 20+random.Float64()*10)
+		}
+		if face, ok := newDepthFace(corners[0], corners[1], corners[2], rl.Gray); ok {
+			faces = append(faces, face)
+		}
+	}
+	return faces
+}
+
+func TestSortByDepth(t *testing.T) {
+	near, _ := newDepthFace(geom.NewVector(0, 0, 10), geom.NewVector(1, 0, 10), geom.NewVector(0, 1, 10), rl.Red)
+	far, _ := newDepthFace(geom.NewVector(0, 0, 20), geom.NewVector(1, 0, 20), geom.NewVector(0, 1, 20), rl.Blue)
+	level, _ := newDepthFace(geom.NewVector(0, 0, 10), geom.NewVector(2, 0, 10), geom.NewVector(0, 2, 10), rl.Green)
+
+	faces := orderFaces([]depthFace{near, far, level}, DepthSortCentroid)
+	if faces[0].color != rl.Blue || faces[1].color != rl.Red || faces[2].color != rl.Green {
+		t.Errorf("Expected the far face first and equal depths in storage order, got %v, %v, %v",
+			faces[0].color, faces[1].color, faces[2].color)
+	}
+
+	faces = orderFaces([]depthFace{near, far}, DepthSortNone)
+	if faces[0].color != rl.Red {
+		t.Errorf("Expected storage order without sorting")
+	}
+}
+
+func TestBSPOrder_MatchesNearestFace(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	faces := randomDepthFaces(random, 30)
+	ordered := bspOrder(faces)
+	if len(ordered) <= len(faces) {
+		t.Fatalf("Expected intersecting faces to be cut, got %d parts of %d faces", len(ordered), len(faces))
+	}
+
+	centroidWrong := 0
+	centroid := orderFaces(append([]depthFace(nil), faces...), DepthSortCentroid)
+	for i := 0; i < 2000; i++ {
+		u, v := random.Float64()*1.2-0.6, random.Float64()*1.2-0.6
+		want := nearestDepth(faces, u, v)
+		if got := paintedDepth(ordered, u, v); math.Abs(got-want) > 1e-9*want && !(math.IsInf(got, 1) && math.IsInf(want, 1)) {
+			t.Fatalf("At (%v, %v): expected the nearest face at depth %v on top, got depth %v", u, v, want, got)
+		}
+		if got := paintedDepth(centroid, u, v); math.Abs(got-want) > 1e-9*want {
+			centroidWrong++
+		}
+	}
+	// The random faces intersect, which sorting whole faces cannot resolve
+	if centroidWrong == 0 {
+		t.Errorf("Expected the test faces to defeat centroid sorting")
+	}
+}
+
+func TestBSPOrder_KeepsOutline(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	faces := randomDepthFaces(random, 20)
+
+	outlineLength := func(faces []depthFace) float64 {
+		length := 0.0
+		for _, face := range faces {
+			for k, isOutline := range face.outline {
+				if isOutline {
+					edge := face.points[(k+1)%len(face.points)].Subtracted(face.points[k])
+					length += edge.Length()
+				}
+			}
+		}
+		return length
+	}
+
+	// Cuts add no outline, and the pieces of the original edges are all kept
+	want := outlineLength(faces)
+	if got := outlineLength(bspOrder(faces)); math.Abs(got-want) > 1e-9*want {
+		t.Errorf("Expected an outline length of %v, got %v", want, got)
+	}
+}
@@ -4,7 +4,24 @@
 //   - Application: Main application loop and window management with configurable settings
 //   - Camera interface: 3D camera with perspective projection (implemented by camera)
 //   - Renderer interface: Renders meshes to screen using raylib (implemented by renderer)
-//   - Scene interface: Container for 3D meshes (implemented by scene)
+//   - Painter's algorithm across all scenes: faces are painted back to front, sorted by centroid
+//     depth or in exact order from a BSP tree (RendererConfig.DepthSort)
+//   - View frustum clipping of faces (Sutherland–Hodgman against the near, far and screen edge
+//     planes, with CameraConfig.NearDistance and FarDistance) and culling of whole meshes by
+//     their bounding boxes, in both renderers
+//   - Lighting with an ambient term and colored directional and point lights (RendererConfig.Lighting),
+//     in flat, Gouraud or Phong shading from the vertex normals (RendererConfig.Shading, ParseShadingMode)
+//   - SoftwareRenderer interface: Headless pure-Go Renderer rasterizing into an RGBA framebuffer
+//     with a depth buffer, perspective-correct vertex colors and a top-left fill rule
+//   - Offscreen rendering for batch jobs and tests (RenderToImage, SaveImage, LoadImage) with
+//     PNG and binary PPM output, and golden image checks with diff images (CompareImages, CheckGolden)
+//   - Scene interface: Container for 3D meshes and their levels of detail (implemented by scene)
+//   - Level-of-detail chains built by decimation (BuildLODChain); the renderer draws the level
+//     matching each mesh's projected size against RendererConfig.LODThresholds
+//   - glTF 2.0 import and export of scenes (LoadGLTF, ReadGLTF, ReadGLB, SaveGLTF, WriteGLTF, WriteGLB), also
+//     registered with geom/meshio as the "gltf" and "glb" formats, which report unsupported extensions
+//     and skipped primitives as warnings. The node hierarchy is kept as GLTFImport.Nodes and
+//     written back by SaveGLTFWithNodes, WriteGLTFWithNodes and WriteGLBWithNodes
 //
 // All components can be configured through Config structs and support dependency injection
 // through interfaces, making the codebase flexible and easy to test.
@@ -0,0 +1,65 @@
+// frustum.go
+package vis
+
+import "go4/geom"
+
+// frustum bounds what the camera sees: the space between the near and far
+// distances that projects inside the screen. Its planes face inward.
+type frustum struct {
+	view  []geom.Plane // For clipping faces in view space
+	world []geom.Plane // For culling whole meshes by their bounding boxes
+}
+
+// newFrustum returns the frustum of the camera for a screen of the given size
+func newFrustum(camera Camera, screenWidth, screenHeight int) frustum {
+	distance := camera.GetDistanceToScreen()
+	halfWidth, halfHeight := float64(screenWidth)/2, float64(screenHeight)/2
+	view := []geom.Plane{
+		geom.NewPlane(geom.NewVector(0, 0, 1), camera.GetNearDistance()),
+		geom.NewPlane(geom.NewVector(0, 0, -1), -camera.GetFarDistance()),
+		// A point at depth z projects inside the screen when distance·|x| ≤ halfWidth·z
+		// and distance·|y| ≤ halfHeight·z
+		geom.NewPlane(geom.NewVector(distance, 0, halfWidth), 0),
+		geom.NewPlane(geom.NewVector(-distance, 0, halfWidth), 0),
+		geom.NewPlane(geom.NewVector(0, distance, halfHeight), 0),
+		geom.NewPlane(geom.NewVector(0, -distance, halfHeight), 0),
+	}
+
+	// The view matrix is a rigid motion, so it always has an inverse
+	toWorld, _ := camera.ViewMatrix().Inverted()
+	world := make([]geom.Plane, len(view))
+	for i, plane := range view {
+		world[i] = plane.Transformed(toWorld)
+	}
+	return frustum{view: view, world: world}
+}
+
+// culls reports whether the box lies entirely behind one of the planes. Boxes
+// outside the frustum only across a corner are kept.
+func (f frustum) culls(box geom.AABB) bool {
+	for _, plane := range f.world {
+		if plane.IsAABBBehind(box) {
+			return true
+		}
+	}
+	return false
+}
+
+// clip cuts away the parts of the view-space face outside the frustum with the
+// Sutherland–Hodgman algorithm, or returns false when nothing is left. Cut
+// edges are not part of the outline.
+func (f frustum) clip(face depthFace) (depthFace, bool) {
+	for _, plane := range f.view {
+		sides, side := face.sides(plane, 0)
+		switch side {
+		case planeBack:
+			return depthFace{}, false
+		case planeSpanning:
+			face, _ = face.split(plane, sides)
+			if len(face.points) < 3 {
+				return depthFace{}, false
+			}
+		}
+	}
+	return face, true
+}
@@ -0,0 +1,94 @@
+package vis
+
+import (
+	"go4/geom"
+	"image/color"
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestFrustum_ClipsBehindCamera(t *testing.T) {
+	view := newFrustum(NewCameraWithDefaults(), 200, 100)
+	// One corner lies behind the camera; the near part of the face is off the
+	// left edge of the screen
+	face, _ := newDepthFace(geom.NewVector(0, 0, -50), geom.NewVector(-50, 20, 600), geom.NewVector(-50, -20, 600), rl.Gray)
+	clipped, ok := view.clip(face)
+	if !ok {
+		t.Fatal("Expected part of the face to stay visible")
+	}
+	corners := [3]geom.Vector{face.points[0], face.points[1], face.points[2]}
+	for k, point := range clipped.points {
+		for i, plane := range view.view {
+			if d := plane.Normal().Dot(point) - plane.Offset(); d < -1e-9 {
+				t.Errorf("Expected %v inside the frustum, got distance %v from plane %d", point, d, i)
+			}
+		}
+		// New corners remember where they lie on the original face for shading
+		if at := interpolate(corners, clipped.weights[k]); at.Subtracted(point).Length() > 1e-9*point.Length() {
+			t.Errorf("Expected the weights of corner %d to give %v, got %v", k, point, at)
+		}
+	}
+	outlines := 0
+	for _, isOutline := range clipped.outline {
+		if isOutline {
+			outlines++
+		}
+	}
+	if outlines != 3 || len(clipped.points) != 4 {
+		t.Errorf("Expected a quadrilateral with one edge cut along the left edge of the screen, got %d outline edges of %d", outlines, len(clipped.points))
+	}
+
+	behind, _ := newDepthFace(geom.NewVector(0, 0, -50), geom.NewVector(10, 0, -50), geom.NewVector(0, 10, -60), rl.Gray)
+	if _, ok := view.clip(behind); ok {
+		t.Errorf("Expected a face behind the camera to be clipped away")
+	}
+	inside, _ := newDepthFace(geom.NewVector(0, 0, 500), geom.NewVector(10, 0, 500), geom.NewVector(0, 10, 500), rl.Gray)
+	if kept, ok := view.clip(inside); !ok || len(kept.points) != 3 {
+		t.Errorf("Expected a face inside the frustum to stay whole")
+	}
+}
+
+func TestFrustum_CullsBoxes(t *testing.T) {
+	cam := NewCameraWithDefaults()
+	view := newFrustum(cam, 200, 100)
+	eye := cameraWorldPosition(cam)
+	behindEye := eye.Multiplied(1.5)
+
+	cases := map[string]struct {
+		box    geom.AABB
+		culled bool
+	}{
+		"origin":      {geom.NewAABB(geom.NewVertex(-100, -100, -100), geom.NewVertex(100, 100, 100)), false},
+		"behind":      {geom.NewSphere(geom.NewVertex(behindEye.X(), behindEye.Y(), behindEye.Z()), 50).BoundingBox(), true},
+		"around":      {geom.NewSphere(geom.NewVertex(eye.X(), eye.Y(), eye.Z()), 50).BoundingBox(), false},
+		"beyond far":  {geom.NewSphere(geom.NewVertex(-1e6, -1e6, -1e6), 100).BoundingBox(), true},
+		"off to side": {geom.NewSphere(geom.NewVertex(2000, -2000, 0), 100).BoundingBox(), true},
+		"empty":       {geom.EmptyAABB(), true},
+	}
+	for name, c := range cases {
+		if got := view.culls(c.box); got != c.culled {
+			t.Errorf("%s: expected culled %v, got %v", name, c.culled, got)
+		}
+	}
+}
+
+func TestSoftwareRenderer_ClipsFacesReachingBehindCamera(t *testing.T) {
+	r := newTestSoftwareRenderer(t, 120, 90)
+
+	// The camera looks down on a ground made of two faces that both reach
+	// behind it and fill the whole view
+	scene := NewScene()
+	scene.AddMesh(geom.CreatePlane(20000, 20000, 1, 1))
+	r.Render(scene)
+
+	background := color.RGBA{A: 255}
+	for y := 0; y < 90; y++ {
+		for x := 0; x < 120; x++ {
+			if r.Image().RGBAAt(x, y) == background || math.IsInf(r.Depth(x, y), 1) {
+				t.Fatalf("Expected the ground everywhere, got a hole at (%d, %d)", x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,1042 @@
+// gltf.go
+package vis
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go4/geom"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	glbMagic     = 0x46546C67 // "glTF"
+	glbVersion   = 2
+	glbChunkJSON = 0x4E4F534A // "JSON"
+	glbChunkBIN  = 0x004E4942 // "BIN\x00"
+
+	gltfModeTriangles = 4
+
+	gltfComponentByte          = 5120
+	gltfComponentUnsignedByte  = 5121
+	gltfComponentShort         = 5122
+	gltfComponentUnsignedShort = 5123
+	gltfComponentUnsignedInt   = 5125
+	gltfComponentFloat         = 5126
+
+	gltfTargetArrayBuffer        = 34962
+	gltfTargetElementArrayBuffer = 34963
+)
+
+// GLTFImport is the result of reading a glTF asset
+type GLTFImport struct {
+	Scene Scene
+
+	// UnsupportedExtensions lists extensions the asset uses that the reader ignored
+	UnsupportedExtensions []string
+
+	// Warnings describes other content that was skipped, e.g. non-triangle primitives
+	Warnings []string
+
+	// Nodes is the node hierarchy of the default scene, parents before their
+	// children. Pass it to SaveGLTFWithNodes and friends to write it back.
+	Nodes []GLTFNode
+}
+
+// GLTFNode is a node of a glTF hierarchy with its transform relative to the parent
+type GLTFNode struct {
+	Name string
+	// Parent is the index of an earlier node, or -1 for a root node
+	Parent int
+	// Mesh is the index of the scene mesh placed by the node, or -1 for none.
+	// Scene meshes are in world space, with the transforms of the node and its
+	// ancestors applied.
+	Mesh        int
+	Translation geom.Vector
+	Rotation    geom.Quaternion
+	Scale       geom.Vector
+}
+
+// NewGLTFNode creates a root node without mesh and with an identity transform
+func NewGLTFNode(name string) GLTFNode {
+	return GLTFNode{
+		Name:     name,
+		Parent:   -1,
+		Mesh:     -1,
+		Rotation: geom.IdentityQuaternion(),
+		Scale:    geom.NewVector(1, 1, 1),
+	}
+}
+
+// LoadGLTF reads a .gltf (with external or embedded buffers) or .glb file.
+// Every mesh instance of the default scene is added to the scene with its
+// world transform baked into the vertices, and the node hierarchy is kept in
+// Nodes. Base-color factors become mesh materials.
+func LoadGLTF(path string) (*GLTFImport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isGLB(data) {
+		return decodeGLB(data, filepath.Dir(path))
+	}
+	return decodeGLTF(data, nil, filepath.Dir(path))
+}
+
+// ReadGLTF reads a JSON glTF document; relative buffer URIs are resolved against baseDir.
+func ReadGLTF(r io.Reader, baseDir string) (*GLTFImport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decodeGLTF(data, nil, baseDir)
+}
+
+// ReadGLB reads a binary glTF container. External buffer URIs are not allowed.
+func ReadGLB(r io.Reader) (*GLTFImport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decodeGLB(data, "")
+}
+
+func isGLB(data []byte) bool {
+	return len(data) >= 4 && binary.LittleEndian.Uint32(data) == glbMagic
+}
+
+func decodeGLB(data []byte, baseDir string) (*GLTFImport, error) {
+	if len(data) < 20 {
+		return nil, fmt.Errorf("glb: file too short: %d bytes", len(data))
+	}
+	if binary.LittleEndian.Uint32(data) != glbMagic {
+		return nil, fmt.Errorf("glb: invalid magic")
+	}
+	if version := binary.LittleEndian.Uint32(data[4:]); version != glbVersion {
+		return nil, fmt.Errorf("glb: unsupported container version %d", version)
+	}
+	length := int(binary.LittleEndian.Uint32(data[8:]))
+	if length > len(data) {
+		return nil, fmt.Errorf("glb: header declares %d bytes, got %d", length, len(data))
+	}
+
+	var jsonChunk, binChunk []byte
+	for offset := 12; offset+8 <= length; {
+		chunkLength := int(binary.LittleEndian.Uint32(data[offset:]))
+		chunkType := binary.LittleEndian.Uint32(data[offset+4:])
+		start := offset + 8
+		if start+chunkLength > length {
+			return nil, fmt.Errorf("glb: chunk at offset %d exceeds file length", offset)
+		}
+		switch chunkType {
+		case glbChunkJSON:
+			jsonChunk = data[start : start+chunkLength]
+		case glbChunkBIN:
+			if binChunk == nil {
+				binChunk = data[start : start+chunkLength]
+			}
+		}
+		offset = start + chunkLength
+	}
+	if jsonChunk == nil {
+		return nil, fmt.Errorf("glb: missing JSON chunk")
+	}
+
+	return decodeGLTF(jsonChunk, binChunk, baseDir)
+}
+
+type gltfDocument struct {
+	Asset              gltfAsset        `json:"asset"`
+	Scene              *int             `json:"scene,omitempty"`
+	Scenes             []gltfScene      `json:"scenes,omitempty"`
+	Nodes              []gltfNode       `json:"nodes,omitempty"`
+	Meshes             []gltfMesh       `json:"meshes,omitempty"`
+	Materials          []gltfMaterial   `json:"materials,omitempty"`
+	Accessors          []gltfAccessor   `json:"accessors,omitempty"`
+	BufferViews        []gltfBufferView `json:"bufferViews,omitempty"`
+	Buffers            []gltfBuffer     `json:"buffers,omitempty"`
+	ExtensionsUsed     []string         `json:"extensionsUsed,omitempty"`
+	ExtensionsRequired []string         `json:"extensionsRequired,omitempty"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+type gltfScene struct {
+	Name  string `json:"name,omitempty"`
+	Nodes []int  `json:"nodes,omitempty"`
+}
+
+type gltfNode struct {
+	Name        string    `json:"name,omitempty"`
+	Mesh        *int      `json:"mesh,omitempty"`
+	Children    []int     `json:"children,omitempty"`
+	Matrix      []float64 `json:"matrix,omitempty"`
+	Translation []float64 `json:"translation,omitempty"`
+	Rotation    []float64 `json:"rotation,omitempty"`
+	Scale       []float64 `json:"scale,omitempty"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Material   *int           `json:"material,omitempty"`
+	Mode       *int           `json:"mode,omitempty"`
+}
+
+type gltfMaterial struct {
+	Name                 string                    `json:"name,omitempty"`
+	PbrMetallicRoughness *gltfPbrMetallicRoughness `json:"pbrMetallicRoughness,omitempty"`
+}
+
+type gltfPbrMetallicRoughness struct {
+	BaseColorFactor []float64 `json:"baseColorFactor,omitempty"`
+}
+
+type gltfAccessor struct {
+	BufferView    *int             `json:"bufferView,omitempty"`
+	ByteOffset    int              `json:"byteOffset,omitempty"`
+	ComponentType int              `json:"componentType"`
+	Normalized    bool             `json:"normalized,omitempty"`
+	Count         int              `json:"count"`
+	Type          string           `json:"type"`
+	Min           []float64        `json:"min,omitempty"`
+	Max           []float64        `json:"max,omitempty"`
+	Sparse        *json.RawMessage `json:"sparse,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset,omitempty"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride,omitempty"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri,omitempty"`
+}
+
+// gltfSupportedExtensions lists extensions the reader understands; none so far
+var gltfSupportedExtensions = map[string]bool{}
+
+// gltfDecoder holds the state of one import
+type gltfDecoder struct {
+	document gltfDocument
+	buffers  [][]byte
+	result   *GLTFImport
+}
+
+func decodeGLTF(data []byte, binChunk []byte, baseDir string) (*GLTFImport, error) {
+	decoder := &gltfDecoder{
+		result: &GLTFImport{Scene: NewScene()},
+	}
+	if err := json.Unmarshal(data, &decoder.document); err != nil {
+		return nil, fmt.Errorf("gltf: invalid JSON: %w", err)
+	}
+	document := &decoder.document
+
+	if !strings.HasPrefix(document.Asset.Version, "2.") {
+		return nil, fmt.Errorf("gltf: unsupported asset version %q", document.Asset.Version)
+	}
+
+	var required []string
+	for _, extension := range document.ExtensionsRequired {
+		if !gltfSupportedExtensions[extension] {
+			required = append(required, extension)
+		}
+	}
+	if len(required) > 0 {
+		return nil, fmt.Errorf("gltf: unsupported required extensions: %s", strings.Join(required, ", "))
+	}
+	for _, extension := range document.ExtensionsUsed {
+		if !gltfSupportedExtensions[extension] {
+			decoder.result.UnsupportedExtensions = append(decoder.result.UnsupportedExtensions, extension)
+		}
+	}
+
+	decoder.buffers = make([][]byte, len(document.Buffers))
+	for i, buffer := range document.Buffers {
+		content, err := loadGLTFBuffer(buffer, i, binChunk, baseDir)
+		if err != nil {
+			return nil, err
+		}
+		if len(content) < buffer.ByteLength {
+			return nil, fmt.Errorf("gltf: buffer %d has %d bytes, expected %d", i, len(content), buffer.ByteLength)
+		}
+		decoder.buffers[i] = content
+	}
+
+	roots, err := decoder.rootNodes()
+	if err != nil {
+		return nil, err
+	}
+	visited := make(map[int]bool)
+	for _, root := range roots {
+		if err := decoder.visitNode(root, geom.IdentityMatrix4(), -1, visited); err != nil {
+			return nil, err
+		}
+	}
+
+	return decoder.result, nil
+}
+
+func loadGLTFBuffer(buffer gltfBuffer, index int, binChunk []byte, baseDir string) ([]byte, error) {
+	switch {
+	case buffer.URI == "":
+		if index != 0 || binChunk == nil {
+			return nil, fmt.Errorf("gltf: buffer %d has no uri and no GLB binary chunk", index)
+		}
+		return binChunk, nil
+	case strings.HasPrefix(buffer.URI, "data:"):
+		comma := strings.IndexByte(buffer.URI, ',')
+		if comma < 0 || !strings.HasSuffix(buffer.URI[:comma], ";base64") {
+			return nil, fmt.Errorf("gltf: buffer %d has an unsupported data uri", index)
+		}
+		content, err := base64.StdEncoding.DecodeString(buffer.URI[comma+1:])
+		if err != nil {
+			return nil, fmt.Errorf("gltf: buffer %d: %w", index, err)
+		}
+		return content, nil
+	default:
+		if baseDir == "" {
+			return nil, fmt.Errorf("gltf: buffer %d references external uri %q", index, buffer.URI)
+		}
+		content, err := os.ReadFile(filepath.Join(baseDir, filepath.FromSlash(buffer.URI)))
+		if err != nil {
+			return nil, fmt.Errorf("gltf: buffer %d: %w", index, err)
+		}
+		return content, nil
+	}
+}
+
+// rootNodes returns the nodes of the default scene, or all parentless nodes if the asset has no scenes
+func (d *gltfDecoder) rootNodes() ([]int, error) {
+	document := &d.document
+	if len(document.Scenes) > 0 {
+		scene := 0
+		if document.Scene != nil {
+			scene = *document.Scene
+		}
+		if scene < 0 || scene >= len(document.Scenes) {
+			return nil, fmt.Errorf("gltf: scene index out of bounds: %d", scene)
+		}
+		return document.Scenes[scene].Nodes, nil
+	}
+
+	hasParent := make([]bool, len(document.Nodes))
+	for _, node := range document.Nodes {
+		for _, child := range node.Children {
+			if child >= 0 && child < len(hasParent) {
+				hasParent[child] = true
+			}
+		}
+	}
+	var roots []int
+	for i, parented := range hasParent {
+		if !parented {
+			roots = append(roots, i)
+		}
+	}
+	return roots, nil
+}
+
+// visitNode adds the meshes of the node and its descendants to the scene and
+// records the nodes below the imported node parentNode
+func (d *gltfDecoder) visitNode(index int, parent geom.Matrix4, parentNode int, visited map[int]bool) error {
+	if index < 0 || index >= len(d.document.Nodes) {
+		return fmt.Errorf("gltf: node index out of bounds: %d", index)
+	}
+	if visited[index] {
+		return fmt.Errorf("gltf: node %d is referenced more than once", index)
+	}
+	visited[index] = true
+
+	node := d.document.Nodes[index]
+	local, err := node.localMatrix()
+	if err != nil {
+		return fmt.Errorf("gltf: node %d: %w", index, err)
+	}
+	world := parent.Multiplied(local)
+
+	imported := GLTFNode{Name: node.Name, Parent: parentNode, Mesh: -1}
+	imported.Translation, imported.Rotation, imported.Scale = node.localTRS(local)
+	if !geom.ComposeMatrix4(imported.Translation, imported.Rotation, imported.Scale).Equals(local) {
+		d.result.Warnings = append(d.result.Warnings,
+			fmt.Sprintf("node %d: the shear of its matrix is only kept in the mesh vertices", index))
+	}
+
+	if node.Mesh != nil {
+		mesh, err := d.buildMesh(*node.Mesh, world)
+		if err != nil {
+			return err
+		}
+		if mesh.FaceNumber() > 0 {
+			imported.Mesh = d.result.Scene.MeshCount()
+			d.result.Scene.AddMesh(mesh)
+		}
+	}
+	d.result.Nodes = append(d.result.Nodes, imported)
+	nodeIndex := len(d.result.Nodes) - 1
+
+	for _, child := range node.Children {
+		if err := d.visitNode(child, world, nodeIndex, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *gltfDecoder) buildMesh(index int, world geom.Matrix4) (*geom.Mesh, error) {
+	if index < 0 || index >= len(d.document.Meshes) {
+		return nil, fmt.Errorf("gltf: mesh index out of bounds: %d", index)
+	}
+
+	normalMatrix := world.NormalMatrix()
+	flipWinding := world.Determinant3() < 0
+	materials := make(map[int]int)
+
+	mesh := &geom.Mesh{}
+	for p, primitive := range d.document.Meshes[index].Primitives {
+		if primitive.Mode != nil && *primitive.Mode != gltfModeTriangles {
+			d.result.Warnings = append(d.result.Warnings,
+				fmt.Sprintf("mesh %d primitive %d: mode %d is not supported, skipped", index, p, *primitive.Mode))
+			continue
+		}
+
+		positionAccessor, ok := primitive.Attributes["POSITION"]
+		if !ok {
+			return nil, fmt.Errorf("gltf: mesh %d primitive %d has no POSITION attribute", index, p)
+		}
+		positions, err := d.readAccessor(positionAccessor, "VEC3")
+		if err != nil {
+			return nil, err
+		}
+		count := len(positions)
+
+		var normals, uvs, colors [][]float64
+		if accessor, ok := primitive.Attributes["NORMAL"]; ok {
+			if normals, err = d.readAccessor(accessor, "VEC3"); err != nil {
+				return nil, err
+			}
+		}
+		if accessor, ok := primitive.Attributes["TEXCOORD_0"]; ok {
+			if uvs, err = d.readAccessor(accessor, "VEC2"); err != nil {
+				return nil, err
+			}
+		}
+		if accessor, ok := primitive.Attributes["COLOR_0"]; ok {
+			if colors, err = d.readAccessor(accessor, "VEC3", "VEC4"); err != nil {
+				return nil, err
+			}
+		}
+		for name, values := range map[string][][]float64{"NORMAL": normals, "TEXCOORD_0": uvs, "COLOR_0": colors} {
+			if values != nil && len(values) != count {
+				return nil, fmt.Errorf("gltf: mesh %d primitive %d: %s has %d entries, POSITION has %d", index, p, name, len(values), count)
+			}
+		}
+
+		base := mesh.VertexNumber()
+		for i, position := range positions {
+			vertex := mesh.AddVertex(world.TransformVertex(geom.NewVertex(position[0], position[1], position[2])))
+			if normals != nil {
+				normal := normalMatrix.TransformVector(geom.NewVector(normals[i][0], normals[i][1], normals[i][2]))
+				normal.Normalize()
+				_ = mesh.SetVertexNormal(vertex, normal)
+			}
+			if uvs != nil {
+				// glTF puts the texture origin at the top-left corner, geom follows OBJ (bottom-left)
+				_ = mesh.SetVertexUV(vertex, geom.NewVertex2d(uvs[i][0], 1-uvs[i][1]))
+			}
+			if colors != nil {
+				alpha := 1.0
+				if len(colors[i]) == 4 {
+					alpha = colors[i][3]
+				}
+				_ = mesh.SetVertexColor(vertex, geom.NewColor(colors[i][0], colors[i][1], colors[i][2], alpha))
+			}
+		}
+
+		var indices []int
+		if primitive.Indices != nil {
+			values, err := d.readAccessor(*primitive.Indices, "SCALAR")
+			if err != nil {
+				return nil, err
+			}
+			indices = make([]int, len(values))
+			for i, value := range values {
+				indices[i] = int(value[0])
+			}
+		} else {
+			indices = make([]int, count)
+			for i := range indices {
+				indices[i] = i
+			}
+		}
+		if len(indices)%3 != 0 {
+			return nil, fmt.Errorf("gltf: mesh %d primitive %d: %d indices do not form triangles", index, p, len(indices))
+		}
+
+		material := geom.NoMaterial
+		if primitive.Material != nil {
+			if material, err = d.meshMaterial(mesh, *primitive.Material, materials); err != nil {
+				return nil, err
+			}
+		}
+
+		for i := 0; i < len(indices); i += 3 {
+			a, b, c := indices[i], indices[i+1], indices[i+2]
+			if flipWinding {
+				b, c = c, b
+			}
+			for _, corner := range []int{a, b, c} {
+				if corner < 0 || corner >= count {
+					return nil, fmt.Errorf("gltf: mesh %d primitive %d: index %d out of bounds (%d vertices)", index, p, corner, count)
+				}
+			}
+			face, err := mesh.AddFace(base+a, base+b, base+c)
+			if err != nil {
+				return nil, err
+			}
+			_ = mesh.SetFaceMaterial(face, material)
+		}
+	}
+
+	return mesh, nil
+}
+
+// meshMaterial converts a glTF material into a mesh material once per mesh
+func (d *gltfDecoder) meshMaterial(mesh *geom.Mesh, index int, converted map[int]int) (int, error) {
+	if materialIndex, ok := converted[index]; ok {
+		return materialIndex, nil
+	}
+	if index < 0 || index >= len(d.document.Materials) {
+		return geom.NoMaterial, fmt.Errorf("gltf: material index out of bounds: %d", index)
+	}
+
+	source := d.document.Materials[index]
+	name := source.Name
+	if name == "" {
+		name = fmt.Sprintf("material_%d", index)
+	}
+	material := geom.NewMaterial(name)
+	// The glTF default base color is opaque white
+	material.Diffuse = geom.NewColor(1, 1, 1, 1)
+	if source.PbrMetallicRoughness != nil && len(source.PbrMetallicRoughness.BaseColorFactor) == 4 {
+		factor := source.PbrMetallicRoughness.BaseColorFactor
+		material.Diffuse = geom.NewColor(factor[0], factor[1], factor[2], factor[3])
+	}
+
+	converted[index] = mesh.AddMaterial(material)
+	return converted[index], nil
+}
+
+var gltfTypeComponents = map[string]int{
+	"SCALAR": 1,
+	"VEC2":   2,
+	"VEC3":   3,
+	"VEC4":   4,
+	"MAT4":   16,
+}
+
+// readAccessor decodes an accessor into one slice of components per element
+func (d *gltfDecoder) readAccessor(index int, allowedTypes ...string) ([][]float64, error) {
+	if index < 0 || index >= len(d.document.Accessors) {
+		return nil, fmt.Errorf("gltf: accessor index out of bounds: %d", index)
+	}
+	accessor := d.document.Accessors[index]
+
+	allowed := false
+	for _, accessorType := range allowedTypes {
+		allowed = allowed || accessor.Type == accessorType
+	}
+	if !allowed {
+		return nil, fmt.Errorf("gltf: accessor %d has type %s, expected %s", index, accessor.Type, strings.Join(allowedTypes, " or "))
+	}
+	if accessor.Sparse != nil {
+		return nil, fmt.Errorf("gltf: accessor %d: sparse accessors are not supported", index)
+	}
+
+	components := gltfTypeComponents[accessor.Type]
+	componentSize, err := gltfComponentSize(accessor.ComponentType)
+	if err != nil {
+		return nil, fmt.Errorf("gltf: accessor %d: %w", index, err)
+	}
+
+	if accessor.Count < 0 || accessor.ByteOffset < 0 {
+		return nil, fmt.Errorf("gltf: accessor %d has a negative count or byte offset", index)
+	}
+	// Accessors without a buffer view are all zeros
+	if accessor.BufferView == nil {
+		if accessor.Count > maxGLTFZeroAccessorCount {
+			return nil, fmt.Errorf("gltf: accessor %d: count %d without a buffer view exceeds %d", index, accessor.Count, maxGLTFZeroAccessorCount)
+		}
+		return newGLTFAccessorValues(accessor.Count, components), nil
+	}
+
+	viewIndex := *accessor.BufferView
+	if viewIndex < 0 || viewIndex >= len(d.document.BufferViews) {
+		return nil, fmt.Errorf("gltf: accessor %d: buffer view index out of bounds: %d", index, viewIndex)
+	}
+	view := d.document.BufferViews[viewIndex]
+	if view.Buffer < 0 || view.Buffer >= len(d.buffers) {
+		return nil, fmt.Errorf("gltf: buffer view %d: buffer index out of bounds: %d", viewIndex, view.Buffer)
+	}
+	if view.ByteOffset < 0 || view.ByteLength < 0 || view.ByteStride < 0 {
+		return nil, fmt.Errorf("gltf: buffer view %d has a negative byte offset, length or stride", viewIndex)
+	}
+	buffer := d.buffers[view.Buffer]
+	if view.ByteOffset > len(buffer) || view.ByteLength > len(buffer)-view.ByteOffset {
+		return nil, fmt.Errorf("gltf: buffer view %d exceeds its buffer", viewIndex)
+	}
+	data := buffer[view.ByteOffset : view.ByteOffset+view.ByteLength]
+
+	elementSize := components * componentSize
+	stride := view.ByteStride
+	if stride == 0 {
+		stride = elementSize
+	}
+	if stride < elementSize {
+		return nil, fmt.Errorf("gltf: buffer view %d: byte stride %d is smaller than the %d byte elements of accessor %d", viewIndex, stride, elementSize, index)
+	}
+	// Compared by division so that huge counts cannot overflow
+	if accessor.Count > 0 && (accessor.ByteOffset > len(data)-elementSize ||
+		accessor.Count-1 > (len(data)-elementSize-accessor.ByteOffset)/stride) {
+		return nil, fmt.Errorf("gltf: accessor %d exceeds buffer view %d", index, viewIndex)
+	}
+
+	result := newGLTFAccessorValues(accessor.Count, components)
+	for i := 0; i < accessor.Count; i++ {
+		offset := accessor.ByteOffset + i*stride
+		for c := 0; c < components; c++ {
+			result[i][c] = readGLTFComponent(data[offset+c*componentSize:], accessor.ComponentType, accessor.Normalized)
+		}
+	}
+	return result, nil
+}
+
+// maxGLTFZeroAccessorCount bounds accessors without a buffer view, whose
+// count no data has to back
+const maxGLTFZeroAccessorCount = 1 << 24
+
+// newGLTFAccessorValues returns count zeroed elements of the given size
+func newGLTFAccessorValues(count, components int) [][]float64 {
+	result := make([][]float64, count)
+	values := make([]float64, count*components)
+	for i := range result {
+		result[i] = values[i*components : (i+1)*components]
+	}
+	return result
+}
+
+func gltfComponentSize(componentType int) (int, error) {
+	switch componentType {
+	case gltfComponentByte, gltfComponentUnsignedByte:
+		return 1, nil
+	case gltfComponentShort, gltfComponentUnsignedShort:
+		return 2, nil
+	case gltfComponentUnsignedInt, gltfComponentFloat:
+		return 4, nil
+	default:
+		return 0, fmt.Errorf("unknown component type %d", componentType)
+	}
+}
+
+func readGLTFComponent(data []byte, componentType int, normalized bool) float64 {
+	switch componentType {
+	case gltfComponentByte:
+		value := float64(int8(data[0]))
+		if normalized {
+			return math.Max(value/127, -1)
+		}
+		return value
+	case gltfComponentUnsignedByte:
+		value := float64(data[0])
+		if normalized {
+			return value / 255
+		}
+		return value
+	case gltfComponentShort:
+		value := float64(int16(binary.LittleEndian.Uint16(data)))
+		if normalized {
+			return math.Max(value/32767, -1)
+		}
+		return value
+	case gltfComponentUnsignedShort:
+		value := float64(binary.LittleEndian.Uint16(data))
+		if normalized {
+			return value / 65535
+		}
+		return value
+	case gltfComponentUnsignedInt:
+		return float64(binary.LittleEndian.Uint32(data))
+	default:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data)))
+	}
+}
+
+// SaveGLTF writes the scene to path. A .glb extension produces a binary container,
+// anything else a JSON document with a sibling .bin buffer.
+func SaveGLTF(path string, scene Scene) error {
+	return SaveGLTFWithNodes(path, scene, nil)
+}
+
+// SaveGLTFWithNodes writes the scene to path like SaveGLTF, placing the meshes
+// by the node hierarchy, e.g. the Nodes of a GLTFImport
+func SaveGLTFWithNodes(path string, scene Scene, nodes []GLTFNode) error {
+	if strings.EqualFold(filepath.Ext(path), ".glb") {
+		return geom.WriteFile(path, func(w io.Writer) error {
+			return WriteGLBWithNodes(w, scene, nodes)
+		})
+	}
+
+	binName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + ".bin"
+	document, buffer, err := encodeGLTF(scene, nodes)
+	if err != nil {
+		return err
+	}
+	document.Buffers[0].URI = binName
+
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), binName), buffer, 0o644); err != nil {
+		return err
+	}
+	return geom.WriteFile(path, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(document)
+	})
+}
+
+// WriteGLTF writes the scene as a JSON glTF document with the buffer embedded as a data uri.
+func WriteGLTF(w io.Writer, scene Scene) error {
+	return WriteGLTFWithNodes(w, scene, nil)
+}
+
+// WriteGLTFWithNodes writes the scene like WriteGLTF, placing the meshes by the node hierarchy
+func WriteGLTFWithNodes(w io.Writer, scene Scene, nodes []GLTFNode) error {
+	document, buffer, err := encodeGLTF(scene, nodes)
+	if err != nil {
+		return err
+	}
+	document.Buffers[0].URI = "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(buffer)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// WriteGLB writes the scene as a binary glTF container.
+func WriteGLB(w io.Writer, scene Scene) error {
+	return WriteGLBWithNodes(w, scene, nil)
+}
+
+// WriteGLBWithNodes writes the scene like WriteGLB, placing the meshes by the node hierarchy
+func WriteGLBWithNodes(w io.Writer, scene Scene, nodes []GLTFNode) error {
+	document, buffer, err := encodeGLTF(scene, nodes)
+	if err != nil {
+		return err
+	}
+	jsonData, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	jsonData = padGLBChunk(jsonData, ' ')
+	buffer = padGLBChunk(buffer, 0)
+
+	var out bytes.Buffer
+	put := func(value uint32) {
+		_ = binary.Write(&out, binary.LittleEndian, value)
+	}
+	put(glbMagic)
+	put(glbVersion)
+	put(uint32(12 + 8 + len(jsonData) + 8 + len(buffer)))
+	put(uint32(len(jsonData)))
+	put(glbChunkJSON)
+	out.Write(jsonData)
+	put(uint32(len(buffer)))
+	put(glbChunkBIN)
+	out.Write(buffer)
+
+	_, err = w.Write(out.Bytes())
+	return err
+}
+
+func padGLBChunk(data []byte, padding byte) []byte {
+	for len(data)%4 != 0 {
+		data = append(data, padding)
+	}
+	return data
+}
+
+// gltfEncoder accumulates buffer data and document entries for export
+type gltfEncoder struct {
+	document gltfDocument
+	buffer   bytes.Buffer
+}
+
+// encodeGLTF writes the nodes with their transforms, taking the world
+// transform of each node back out of its mesh. Scene meshes without a node
+// get one with an identity transform. Faces are split into one primitive per material.
+func encodeGLTF(scene Scene, nodes []GLTFNode) (gltfDocument, []byte, error) {
+	encoder := &gltfEncoder{
+		document: gltfDocument{
+			Asset:  gltfAsset{Version: "2.0", Generator: "go4/vis"},
+			Scenes: []gltfScene{{}},
+		},
+	}
+	sceneIndex := 0
+	encoder.document.Scene = &sceneIndex
+	meshes := scene.GetMeshes()
+
+	placed := make([]bool, len(meshes))
+	worlds := make([]geom.Matrix4, len(nodes))
+	for i, node := range nodes {
+		if node.Parent < -1 || node.Parent >= i {
+			return gltfDocument{}, nil, fmt.Errorf("gltf: node %d: parent %d is not an earlier node", i, node.Parent)
+		}
+		if node.Mesh < -1 || node.Mesh >= len(meshes) {
+			return gltfDocument{}, nil, fmt.Errorf("gltf: node %d: mesh index out of bounds: %d", i, node.Mesh)
+		}
+
+		local := geom.ComposeMatrix4(node.Translation, node.Rotation, node.Scale)
+		worlds[i] = local
+		if node.Parent >= 0 {
+			worlds[i] = worlds[node.Parent].Multiplied(local)
+		}
+
+		encoded := gltfNode{Name: node.Name}
+		if !node.Translation.Equals(geom.Vector{}) {
+			encoded.Translation = []float64{node.Translation.X(), node.Translation.Y(), node.Translation.Z()}
+		}
+		if !node.Rotation.Equals(geom.IdentityQuaternion()) {
+			encoded.Rotation = []float64{node.Rotation.X(), node.Rotation.Y(), node.Rotation.Z(), node.Rotation.W()}
+		}
+		if !node.Scale.Equals(geom.NewVector(1, 1, 1)) {
+			encoded.Scale = []float64{node.Scale.X(), node.Scale.Y(), node.Scale.Z()}
+		}
+		if node.Mesh >= 0 && meshes[node.Mesh].FaceNumber() > 0 {
+			inverse, err := worlds[i].Inverted()
+			if err != nil {
+				return gltfDocument{}, nil, fmt.Errorf("gltf: node %d: %w", i, err)
+			}
+			meshIndex := encoder.encodeMesh(meshes[node.Mesh].Transformed(inverse))
+			encoded.Mesh = &meshIndex
+			placed[node.Mesh] = true
+		}
+
+		encoder.document.Nodes = append(encoder.document.Nodes, encoded)
+		if node.Parent >= 0 {
+			parent := &encoder.document.Nodes[node.Parent]
+			parent.Children = append(parent.Children, i)
+		} else {
+			encoder.document.Scenes[0].Nodes = append(encoder.document.Scenes[0].Nodes, i)
+		}
+	}
+
+	for m, mesh := range meshes {
+		if placed[m] || mesh.FaceNumber() == 0 {
+			continue
+		}
+		meshIndex := encoder.encodeMesh(mesh)
+		encoder.document.Nodes = append(encoder.document.Nodes, gltfNode{
+			Name: fmt.Sprintf("node_%d", meshIndex),
+			Mesh: &meshIndex,
+		})
+		encoder.document.Scenes[0].Nodes = append(encoder.document.Scenes[0].Nodes, len(encoder.document.Nodes)-1)
+	}
+
+	encoder.document.Buffers = []gltfBuffer{{ByteLength: encoder.buffer.Len()}}
+	return encoder.document, encoder.buffer.Bytes(), nil
+}
+
+func (e *gltfEncoder) encodeMesh(mesh *geom.Mesh) int {
+	vertexCount := mesh.VertexNumber()
+	attributes := make(map[string]int)
+
+	positions := make([]float32, 0, vertexCount*3)
+	minimum := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	maximum := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for i := 0; i < vertexCount; i++ {
+		v, _ := mesh.Vertex(i)
+		coords := []float64{v.X(), v.Y(), v.Z()}
+		for c, value := range coords {
+			// Bounds must match the stored float32 values exactly
+			stored := float32(value)
+			positions = append(positions, stored)
+			minimum[c] = math.Min(minimum[c], float64(stored))
+			maximum[c] = math.Max(maximum[c], float64(stored))
+		}
+	}
+	attributes["POSITION"] = e.addAccessor(positions, nil, "VEC3", gltfTargetArrayBuffer, minimum, maximum)
+
+	if mesh.HasVertexNormals() {
+		normals := make([]float32, 0, vertexCount*3)
+		for i := 0; i < vertexCount; i++ {
+			n, _ := mesh.VertexNormal(i)
+			normals = append(normals, float32(n.X()), float32(n.Y()), float32(n.Z()))
+		}
+		attributes["NORMAL"] = e.addAccessor(normals, nil, "VEC3", gltfTargetArrayBuffer, nil, nil)
+	}
+	if mesh.HasVertexUVs() {
+		uvs := make([]float32, 0, vertexCount*2)
+		for i := 0; i < vertexCount; i++ {
+			uv, _ := mesh.VertexUV(i)
+			uvs = append(uvs, float32(uv.X()), float32(1-uv.Y()))
+		}
+		attributes["TEXCOORD_0"] = e.addAccessor(uvs, nil, "VEC2", gltfTargetArrayBuffer, nil, nil)
+	}
+	if mesh.HasVertexColors() {
+		colors := make([]float32, 0, vertexCount*4)
+		for i := 0; i < vertexCount; i++ {
+			c, _ := mesh.VertexColor(i)
+			colors = append(colors, float32(c.R), float32(c.G), float32(c.B), float32(c.A))
+		}
+		attributes["COLOR_0"] = e.addAccessor(colors, nil, "VEC4", gltfTargetArrayBuffer, nil, nil)
+	}
+
+	// Group faces by material, keeping the order in which materials first appear
+	facesByMaterial := make(map[int][]uint32)
+	var materialOrder []int
+	for f := 0; f < mesh.FaceNumber(); f++ {
+		material, _ := mesh.FaceMaterial(f)
+		indices, _ := mesh.FaceIndices(f)
+		if _, ok := facesByMaterial[material]; !ok {
+			materialOrder = append(materialOrder, material)
+		}
+		facesByMaterial[material] = append(facesByMaterial[material], uint32(indices[0]), uint32(indices[1]), uint32(indices[2]))
+	}
+
+	materialIndices := make(map[int]int)
+	result := gltfMesh{}
+	for _, material := range materialOrder {
+		indicesAccessor := e.addAccessor(nil, facesByMaterial[material], "SCALAR", gltfTargetElementArrayBuffer, nil, nil)
+		primitive := gltfPrimitive{
+			Attributes: attributes,
+			Indices:    &indicesAccessor,
+		}
+		if material != geom.NoMaterial {
+			if _, ok := materialIndices[material]; !ok {
+				source, _ := mesh.Material(material)
+				diffuse := source.Diffuse
+				e.document.Materials = append(e.document.Materials, gltfMaterial{
+					Name: source.Name,
+					PbrMetallicRoughness: &gltfPbrMetallicRoughness{
+						BaseColorFactor: []float64{diffuse.R, diffuse.G, diffuse.B, diffuse.A},
+					},
+				})
+				materialIndices[material] = len(e.document.Materials) - 1
+			}
+			materialIndex := materialIndices[material]
+			primitive.Material = &materialIndex
+		}
+		result.Primitives = append(result.Primitives, primitive)
+	}
+
+	e.document.Meshes = append(e.document.Meshes, result)
+	return len(e.document.Meshes) - 1
+}
+
+// addAccessor appends either float or index data to the buffer with a dedicated view
+func (e *gltfEncoder) addAccessor(floats []float32, indices []uint32, accessorType string, target int, minimum, maximum []float64) int {
+	// Keep every view 4-byte aligned
+	for e.buffer.Len()%4 != 0 {
+		e.buffer.WriteByte(0)
+	}
+	offset := e.buffer.Len()
+
+	accessor := gltfAccessor{Type: accessorType, Min: minimum, Max: maximum}
+	if indices != nil {
+		_ = binary.Write(&e.buffer, binary.LittleEndian, indices)
+		accessor.ComponentType = gltfComponentUnsignedInt
+		accessor.Count = len(indices)
+	} else {
+		_ = binary.Write(&e.buffer, binary.LittleEndian, floats)
+		accessor.ComponentType = gltfComponentFloat
+		accessor.Count = len(floats) / gltfTypeComponents[accessorType]
+	}
+
+	e.document.BufferViews = append(e.document.BufferViews, gltfBufferView{
+		Buffer:     0,
+		ByteOffset: offset,
+		ByteLength: e.buffer.Len() - offset,
+		Target:     target,
+	})
+	view := len(e.document.BufferViews) - 1
+	accessor.BufferView = &view
+
+	e.document.Accessors = append(e.document.Accessors, accessor)
+	return len(e.document.Accessors) - 1
+}
+
+// localTRS splits the local matrix of the node into translation, rotation and
+// scale, taking them as given unless the node has a matrix
+func (n gltfNode) localTRS(local geom.Matrix4) (geom.Vector, geom.Quaternion, geom.Vector) {
+	if len(n.Matrix) > 0 {
+		return local.Decompose()
+	}
+	translation, rotation, scale := geom.Vector{}, geom.IdentityQuaternion(), geom.NewVector(1, 1, 1)
+	if n.Translation != nil {
+		translation = geom.NewVector(n.Translation[0], n.Translation[1], n.Translation[2])
+	}
+	if n.Rotation != nil {
+		rotation = geom.NewQuaternion(n.Rotation[0], n.Rotation[1], n.Rotation[2], n.Rotation[3])
+	}
+	if n.Scale != nil {
+		scale = geom.NewVector(n.Scale[0], n.Scale[1], n.Scale[2])
+	}
+	return translation, rotation, scale
+}
+
+func (n gltfNode) localMatrix() (geom.Matrix4, error) {
+	if len(n.Matrix) > 0 {
+		if len(n.Matrix) != 16 {
+			return geom.Matrix4{}, errors.New("matrix must have 16 elements")
+		}
+		var values [16]float64
+		copy(values[:], n.Matrix)
+		return geom.NewMatrix4ColumnMajor(values), nil
+	}
+
+	translation := []float64{0, 0, 0}
+	rotation := []float64{0, 0, 0, 1}
+	scale := []float64{1, 1, 1}
+	for _, field := range []struct {
+		name   string
+		values []float64
+		target []float64
+	}{
+		{"translation", n.Translation, translation},
+		{"rotation", n.Rotation, rotation},
+		{"scale", n.Scale, scale},
+	} {
+		if field.values == nil {
+			continue
+		}
+		if len(field.values) != len(field.target) {
+			return geom.Matrix4{}, fmt.Errorf("%s must have %d elements", field.name, len(field.target))
+		}
+		copy(field.target, field.values)
+	}
+
+	return geom.ComposeMatrix4(
+		geom.NewVector(translation[0], translation[1], translation[2]),
+		geom.NewQuaternion(rotation[0], rotation[1], rotation[2], rotation[3]),
+		geom.NewVector(scale[0], scale[1], scale[2]),
+	), nil
+}
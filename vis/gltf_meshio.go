@@ -0,0 +1,91 @@
+// gltf_meshio.go
+package vis
+
+import (
+	"bytes"
+	"fmt"
+	"go4/geom"
+	"go4/geom/meshio"
+	"io"
+)
+
+// The glTF codecs are registered with meshio so that mesh-level callers can
+// open scenes too; every mesh instance of the scene is merged into one mesh.
+func init() {
+	meshio.Register(meshio.Format{
+		Name:             "gltf",
+		Description:      "glTF 2.0",
+		Extensions:       []string{".gltf"},
+		Sniff:            sniffGLTF,
+		ReadWithWarnings: readGLTFMesh,
+		Write: func(w io.Writer, m *geom.Mesh) error {
+			return WriteGLTF(w, sceneOf(m))
+		},
+		OpenWithWarnings: loadGLTFMesh,
+		Save: func(path string, m *geom.Mesh) error {
+			return SaveGLTF(path, sceneOf(m))
+		},
+	})
+	meshio.Register(meshio.Format{
+		Name:             "glb",
+		Description:      "glTF 2.0 binary",
+		Extensions:       []string{".glb"},
+		Sniff:            isGLB,
+		ReadWithWarnings: readGLTFMesh,
+		Write: func(w io.Writer, m *geom.Mesh) error {
+			return WriteGLB(w, sceneOf(m))
+		},
+		OpenWithWarnings: loadGLTFMesh,
+	})
+}
+
+// sniffGLTF accepts JSON documents that mention the mandatory "asset" property early on
+func sniffGLTF(header []byte) bool {
+	trimmed := bytes.TrimLeft(header, " \t\r\n\ufeff")
+	return bytes.HasPrefix(trimmed, []byte("{")) && bytes.Contains(trimmed, []byte(`"asset"`))
+}
+
+func readGLTFMesh(r io.Reader) (*geom.Mesh, []string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var imported *GLTFImport
+	if isGLB(data) {
+		imported, err = decodeGLB(data, "")
+	} else {
+		imported, err = decodeGLTF(data, nil, "")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return mergedGLTFMesh(imported)
+}
+
+func loadGLTFMesh(path string) (*geom.Mesh, []string, error) {
+	imported, err := LoadGLTF(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mergedGLTFMesh(imported)
+}
+
+// mergedGLTFMesh merges the imported scene into one mesh and reports the
+// unsupported extensions and skipped content as warnings
+func mergedGLTFMesh(imported *GLTFImport) (*geom.Mesh, []string, error) {
+	var warnings []string
+	for _, extension := range imported.UnsupportedExtensions {
+		warnings = append(warnings, fmt.Sprintf("gltf: unsupported extension %s was ignored", extension))
+	}
+	for _, warning := range imported.Warnings {
+		warnings = append(warnings, "gltf: "+warning)
+	}
+	return geom.MergeMeshes(imported.Scene.GetMeshes()...), warnings, nil
+}
+
+func sceneOf(m *geom.Mesh) Scene {
+	scene := NewScene()
+	scene.AddMesh(m)
+	return scene
+}
@@ -0,0 +1,311 @@
+package vis
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"go4/geom"
+	"go4/geom/meshio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func buildMaterialScene() Scene {
+	cube := geom.CreateCube(2)
+	red := geom.NewMaterial("red")
+	red.Diffuse = geom.NewColor(1, 0, 0, 1)
+	redIndex := cube.AddMaterial(red)
+	for i := 0; i < 4; i++ {
+		_ = cube.SetFaceMaterial(i, redIndex)
+	}
+
+	scene := NewScene()
+	scene.AddMesh(cube)
+	scene.AddMesh(geom.CreateTetrahedron(1))
+	return scene
+}
+
+func assertSceneGeometry(t *testing.T, expected, actual Scene) {
+	t.Helper()
+
+	if actual.MeshCount() != expected.MeshCount() {
+		t.Fatalf("Expected %d meshes, got %d", expected.MeshCount(), actual.MeshCount())
+	}
+	for m, want := range expected.GetMeshes() {
+		got := actual.GetMeshes()[m]
+		if got.FaceNumber() != want.FaceNumber() {
+			t.Fatalf("Mesh %d: expected %d faces, got %d", m, want.FaceNumber(), got.FaceNumber())
+		}
+		for f := 0; f < want.FaceNumber(); f++ {
+			wantNormal, _ := want.Normal(f)
+			gotNormal, _ := got.Normal(f)
+			if !wantNormal.Equals(gotNormal) {
+				t.Errorf("Mesh %d face %d: expected normal %v, got %v", m, f, wantNormal, gotNormal)
+			}
+		}
+	}
+}
+
+func TestGLB_RoundTrip(t *testing.T) {
+	scene := buildMaterialScene()
+
+	var buf bytes.Buffer
+	if err := WriteGLB(&buf, scene); err != nil {
+		t.Fatalf("WriteGLB failed: %v", err)
+	}
+	if buf.Len()%4 != 0 {
+		t.Errorf("Expected GLB length to be 4-byte aligned, got %d", buf.Len())
+	}
+
+	imported, err := ReadGLB(&buf)
+	if err != nil {
+		t.Fatalf("ReadGLB failed: %v", err)
+	}
+	assertSceneGeometry(t, scene, imported.Scene)
+
+	cube := imported.Scene.GetMeshes()[0]
+	if cube.MaterialNumber() != 1 {
+		t.Fatalf("Expected 1 material, got %d", cube.MaterialNumber())
+	}
+	material, _ := cube.Material(0)
+	if material.Name != "red" || !material.Diffuse.Equals(geom.NewColor(1, 0, 0, 1)) {
+		t.Errorf("Unexpected material %+v", material)
+	}
+	materialIndex, _ := cube.FaceMaterial(0)
+	if materialIndex != 0 {
+		t.Errorf("Expected first face to use the red material, got %d", materialIndex)
+	}
+}
+
+func TestGLTF_SaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	scene := buildMaterialScene()
+
+	for _, name := range []string{"scene.gltf", "scene.glb"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := SaveGLTF(path, scene); err != nil {
+				t.Fatalf("SaveGLTF failed: %v", err)
+			}
+			imported, err := LoadGLTF(path)
+			if err != nil {
+				t.Fatalf("LoadGLTF failed: %v", err)
+			}
+			assertSceneGeometry(t, scene, imported.Scene)
+		})
+	}
+}
+
+// triangleGLTF builds a document with one triangle mesh instanced by the given nodes JSON
+func triangleGLTF(nodes, extra string) string {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, []float32{0, 0, 0, 1, 0, 0, 0, 1, 0})
+	uri := "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	return fmt.Sprintf(`{
+  "asset": {"version": "2.0"},
+  %s
+  "scenes": [{"nodes": [0]}],
+  "nodes": %s,
+  "meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+  "accessors": [{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC3"}],
+  "bufferViews": [{"buffer": 0, "byteLength": 36}],
+  "buffers": [{"byteLength": 36, "uri": %q}]
+}`, extra, nodes, uri)
+}
+
+func TestReadGLTF_BakesNodeHierarchy(t *testing.T) {
+	// Parent translates by (10, 0, 0), child rotates 90° around Z and scales by 2
+	nodes := `[
+    {"translation": [10, 0, 0], "children": [1]},
+    {"mesh": 0, "rotation": [0, 0, 0.7071067811865476, 0.7071067811865476], "scale": [2, 2, 2]}
+  ]`
+	imported, err := ReadGLTF(strings.NewReader(triangleGLTF(nodes, "")), "")
+	if err != nil {
+		t.Fatalf("ReadGLTF failed: %v", err)
+	}
+	if imported.Scene.MeshCount() != 1 {
+		t.Fatalf("Expected 1 mesh, got %d", imported.Scene.MeshCount())
+	}
+
+	mesh := imported.Scene.GetMeshes()[0]
+	expected := []geom.Vector{
+		geom.NewVector(10, 0, 0),
+		geom.NewVector(10, 2, 0),
+		geom.NewVector(8, 0, 0),
+	}
+	for i, want := range expected {
+		v, _ := mesh.VertexInFace(0, i)
+		got := geom.NewVectorFromVertex(v)
+		if got.Subtracted(want).Length() > 1e-6 {
+			t.Errorf("Vertex %d: expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestGLTF_RoundTripsNodeHierarchy(t *testing.T) {
+	nodes := `[
+    {"name": "parent", "translation": [10, 0, 0], "children": [1]},
+    {"name": "child", "mesh": 0, "rotation": [0, 0, 0.7071067811865476, 0.7071067811865476], "scale": [2, 2, 2]}
+  ]`
+	imported, err := ReadGLTF(strings.NewReader(triangleGLTF(nodes, "")), "")
+	if err != nil {
+		t.Fatalf("ReadGLTF failed: %v", err)
+	}
+	if len(imported.Nodes) != 2 || imported.Nodes[0].Parent != -1 || imported.Nodes[1].Parent != 0 ||
+		imported.Nodes[0].Mesh != -1 || imported.Nodes[1].Mesh != 0 {
+		t.Fatalf("Expected a parent node and a child node with the mesh, got %+v", imported.Nodes)
+	}
+	if !imported.Nodes[0].Translation.Equals(geom.NewVector(10, 0, 0)) || !imported.Nodes[1].Scale.Equals(geom.NewVector(2, 2, 2)) {
+		t.Errorf("Expected the node transforms to be kept, got %+v", imported.Nodes)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGLBWithNodes(&buf, imported.Scene, imported.Nodes); err != nil {
+		t.Fatalf("WriteGLBWithNodes failed: %v", err)
+	}
+	reloaded, err := ReadGLB(&buf)
+	if err != nil {
+		t.Fatalf("ReadGLB failed: %v", err)
+	}
+	if len(reloaded.Nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %+v", reloaded.Nodes)
+	}
+	for i, want := range imported.Nodes {
+		got := reloaded.Nodes[i]
+		if got.Name != want.Name || got.Parent != want.Parent || got.Mesh != want.Mesh ||
+			!got.Translation.Equals(want.Translation) || !got.Rotation.Equals(want.Rotation) || !got.Scale.Equals(want.Scale) {
+			t.Errorf("Node %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	// The mesh is written in the space of its node and baked again on import
+	want, got := imported.Scene.GetMeshes()[0], reloaded.Scene.GetMeshes()[0]
+	for k := 0; k < 3; k++ {
+		a, _ := want.VertexInFace(0, k)
+		b, _ := got.VertexInFace(0, k)
+		if a.Distance(b) > 1e-5 {
+			t.Errorf("Vertex %d: expected %v, got %v", k, a, b)
+		}
+	}
+
+	bad := []GLTFNode{NewGLTFNode("a")}
+	bad[0].Parent = 0
+	if err := WriteGLBWithNodes(&buf, imported.Scene, bad); err == nil {
+		t.Errorf("Expected an error for a node that is its own parent")
+	}
+}
+
+func TestReadGLTF_MirroredNodeKeepsOutwardWinding(t *testing.T) {
+	nodes := `[{"mesh": 0, "scale": [-1, 1, 1]}]`
+	imported, err := ReadGLTF(strings.NewReader(triangleGLTF(nodes, "")), "")
+	if err != nil {
+		t.Fatalf("ReadGLTF failed: %v", err)
+	}
+
+	normal, _ := imported.Scene.GetMeshes()[0].Normal(0)
+	if !normal.Equals(geom.NewVector(0, 0, 1)) {
+		t.Errorf("Expected mirrored triangle to keep facing +Z, got %v", normal)
+	}
+}
+
+func TestReadGLTF_ReportsExtensions(t *testing.T) {
+	nodes := `[{"mesh": 0}]`
+
+	used := triangleGLTF(nodes, `"extensionsUsed": ["KHR_materials_clearcoat"],`)
+	imported, err := ReadGLTF(strings.NewReader(used), "")
+	if err != nil {
+		t.Fatalf("ReadGLTF failed: %v", err)
+	}
+	if len(imported.UnsupportedExtensions) != 1 || imported.UnsupportedExtensions[0] != "KHR_materials_clearcoat" {
+		t.Errorf("Expected unsupported extension to be reported, got %v", imported.UnsupportedExtensions)
+	}
+
+	required := triangleGLTF(nodes, `"extensionsUsed": ["KHR_draco_mesh_compression"], "extensionsRequired": ["KHR_draco_mesh_compression"],`)
+	if _, err := ReadGLTF(strings.NewReader(required), ""); err == nil || !strings.Contains(err.Error(), "KHR_draco_mesh_compression") {
+		t.Errorf("Expected error naming the required extension, got %v", err)
+	}
+}
+
+func TestReadGLTF_SkipsNonTrianglePrimitives(t *testing.T) {
+	document := strings.Replace(triangleGLTF(`[{"mesh": 0}]`, ""), `"attributes": {"POSITION": 0}`, `"attributes": {"POSITION": 0}, "mode": 1`, 1)
+	imported, err := ReadGLTF(strings.NewReader(document), "")
+	if err != nil {
+		t.Fatalf("ReadGLTF failed: %v", err)
+	}
+	if imported.Scene.MeshCount() != 0 || len(imported.Warnings) != 1 {
+		t.Errorf("Expected line primitive to be skipped with a warning, got %d meshes and %v", imported.Scene.MeshCount(), imported.Warnings)
+	}
+}
+
+func TestReadGLTF_RejectsMalformedAccessors(t *testing.T) {
+	accessor := `{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC3"}`
+	view := `{"buffer": 0, "byteLength": 36}`
+	cases := map[string]struct{ accessor, view string }{
+		"negative count":            {`{"bufferView": 0, "componentType": 5126, "count": -1, "type": "VEC3"}`, view},
+		"negative accessor offset":  {`{"bufferView": 0, "byteOffset": -12, "componentType": 5126, "count": 3, "type": "VEC3"}`, view},
+		"negative view offset":      {accessor, `{"buffer": 0, "byteOffset": -12, "byteLength": 36}`},
+		"negative view length":      {accessor, `{"buffer": 0, "byteLength": -36}`},
+		"negative stride":           {accessor, `{"buffer": 0, "byteLength": 36, "byteStride": -12}`},
+		"stride below element size": {accessor, `{"buffer": 0, "byteLength": 36, "byteStride": 4}`},
+		"view beyond buffer":        {accessor, `{"buffer": 0, "byteOffset": 9223372036854775807, "byteLength": 36}`},
+		"count beyond view":         {`{"bufferView": 0, "componentType": 5126, "count": 4611686018427387904, "type": "VEC3"}`, view},
+		"huge count without view":   {`{"componentType": 5126, "count": 4611686018427387904, "type": "VEC3"}`, view},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			document := triangleGLTF(`[{"mesh": 0}]`, "")
+			document = strings.Replace(document, accessor, c.accessor, 1)
+			document = strings.Replace(document, view, c.view, 1)
+			if _, err := ReadGLTF(strings.NewReader(document), ""); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestMeshIO_ReadsGLBMerged(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGLB(&buf, buildMaterialScene()); err != nil {
+		t.Fatalf("WriteGLB failed: %v", err)
+	}
+
+	mesh, format, err := meshio.Read(&buf)
+	if err != nil {
+		t.Fatalf("meshio.Read failed: %v", err)
+	}
+	if format.Name != "glb" {
+		t.Errorf("Expected glb format, got %s", format.Name)
+	}
+	if mesh.FaceNumber() != 16 {
+		t.Errorf("Expected cube and tetrahedron faces to be merged into 16, got %d", mesh.FaceNumber())
+	}
+}
+
+func TestMeshIO_ReportsGLTFWarnings(t *testing.T) {
+	document := triangleGLTF(`[{"mesh": 0}]`, `"extensionsUsed": ["KHR_materials_clearcoat"],`)
+	expected := "gltf: unsupported extension KHR_materials_clearcoat was ignored"
+
+	_, _, warnings, err := meshio.ReadWithWarnings(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("meshio.ReadWithWarnings failed: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0] != expected {
+		t.Errorf("Expected the unsupported extension to be reported, got %v", warnings)
+	}
+
+	path := filepath.Join(t.TempDir(), "triangle.gltf")
+	if err := os.WriteFile(path, []byte(document), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	mesh, warnings, err := meshio.LoadWithWarnings(path)
+	if err != nil {
+		t.Fatalf("meshio.LoadWithWarnings failed: %v", err)
+	}
+	if mesh.FaceNumber() != 1 || len(warnings) != 1 || warnings[0] != expected {
+		t.Errorf("Expected one face and the unsupported extension, got %d faces and %v", mesh.FaceNumber(), warnings)
+	}
+}
@@ -0,0 +1,112 @@
+// golden.go
+package vis
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageDiff summarizes the differences between an expected and an actual image
+type ImageDiff struct {
+	// Pixels with a channel differing by more than the tolerance
+	DifferentPixels int
+	// Largest difference of any channel, 0 to 255
+	MaxDifference uint8
+	// The expected image dimmed to gray with the differing pixels in red
+	Diff *image.RGBA
+}
+
+// CompareImages compares the images channel by channel, allowing differences
+// up to tolerance. Images of different sizes cannot be compared.
+func CompareImages(expected, actual image.Image, tolerance uint8) (ImageDiff, error) {
+	bounds := expected.Bounds()
+	if bounds.Size() != actual.Bounds().Size() {
+		return ImageDiff{}, fmt.Errorf("image sizes differ: expected %v, got %v", bounds.Size(), actual.Bounds().Size())
+	}
+
+	offset := actual.Bounds().Min.Sub(bounds.Min)
+	diff := ImageDiff{Diff: image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			want := color.NRGBAModel.Convert(expected.At(x, y)).(color.NRGBA)
+			got := color.NRGBAModel.Convert(actual.At(x+offset.X, y+offset.Y)).(color.NRGBA)
+
+			largest := max(channelDifference(want.R, got.R), channelDifference(want.G, got.G),
+				channelDifference(want.B, got.B), channelDifference(want.A, got.A))
+			diff.MaxDifference = max(diff.MaxDifference, largest)
+
+			pixel := color.RGBA{R: 255, A: 255}
+			if largest <= tolerance {
+				gray := color.GrayModel.Convert(want).(color.Gray).Y / 3
+				pixel = color.RGBA{R: gray, G: gray, B: gray, A: 255}
+			} else {
+				diff.DifferentPixels++
+			}
+			diff.Diff.SetRGBA(x-bounds.Min.X, y-bounds.Min.Y, pixel)
+		}
+	}
+	return diff, nil
+}
+
+func channelDifference(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// GoldenConfig controls CheckGolden
+type GoldenConfig struct {
+	// Largest accepted difference per channel
+	Tolerance uint8
+	// Number of pixels that may exceed the tolerance, e.g. along anti-aliased edges
+	MaxDifferentPixels int
+	// Write the actual image as the new golden file instead of comparing
+	Update bool
+}
+
+// DefaultGoldenConfig returns a configuration that accepts rounding differences only
+func DefaultGoldenConfig() GoldenConfig {
+	return GoldenConfig{
+		Tolerance:          2,
+		MaxDifferentPixels: 0,
+		Update:             false,
+	}
+}
+
+// CheckGolden compares the image with the golden PNG or PPM file at path.
+// When they differ beyond the configuration, the diff image is written next to
+// the golden file with its extension replaced by ".diff.png" and an error is returned. A missing
+// golden file is an error unless config.Update is set.
+func CheckGolden(path string, actual image.Image, config GoldenConfig) (ImageDiff, error) {
+	if config.Update {
+		return ImageDiff{}, SaveImage(path, actual)
+	}
+
+	expected, err := LoadImage(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ImageDiff{}, fmt.Errorf("golden image %s does not exist; rerun with Update set to create it", path)
+	}
+	if err != nil {
+		return ImageDiff{}, err
+	}
+
+	diff, err := CompareImages(expected, actual, config.Tolerance)
+	if err != nil {
+		return diff, err
+	}
+	if diff.DifferentPixels > config.MaxDifferentPixels {
+		diffPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".diff.png"
+		if err := SaveImage(diffPath, diff.Diff); err != nil {
+			return diff, err
+		}
+		return diff, fmt.Errorf("%d pixels differ from %s by up to %d, see %s",
+			diff.DifferentPixels, path, diff.MaxDifference, diffPath)
+	}
+	return diff, nil
+}
@@ -6,10 +6,10 @@
 //   - Label: Text labels for displaying information
 //   - Panel: Container for grouping UI elements
 //   - Manager: Manages all UI elements and their lifecycle
-//   - InfoPanel: Pre-built panel for displaying application info (FPS, camera, etc.)
+//   - InfoPanel: Pre-built panel for displaying application info (FPS, camera, level of detail, etc.)
 //   - NavigationPanel: Basic navigation panel with reset view and zoom controls
 //   - ControlPanel: Pre-built panel with camera control buttons (for demo)
-//   - PrimitiveSelector: Panel for selecting 3D primitives (for demo)
+//   - PrimitiveSelector: Panel for selecting 3D primitives and their parameters (for demo)
 //   - MotionSelector: Panel for selecting camera motion types (for demo)
 //
 // All UI elements are rendered on top of the 3D scene and support mouse interaction.
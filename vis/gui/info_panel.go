@@ -13,6 +13,7 @@ type InfoPanel struct {
 	cameraLabel   Label
 	sceneLabel    Label
 	scenarioLabel Label
+	lodLabel      Label
 }
 
 // InfoPanelConfig holds configuration for creating an info panel
@@ -62,10 +63,19 @@ func NewInfoPanel(config InfoPanelConfig) *InfoPanel {
 		Color:    rl.LightGray,
 	})
 
+	lodLabel := NewLabel(LabelConfig{
+		X:        config.X + 10,
+		Y:        config.Y + 100,
+		Text:     "LOD: n/a",
+		FontSize: 12,
+		Color:    rl.LightGray,
+	})
+
 	panel.AddElement(fpsLabel)
 	panel.AddElement(cameraLabel)
 	panel.AddElement(sceneLabel)
 	panel.AddElement(scenarioLabel)
+	panel.AddElement(lodLabel)
 
 	return &InfoPanel{
 		panel:         panel,
@@ -73,6 +83,7 @@ func NewInfoPanel(config InfoPanelConfig) *InfoPanel {
 		cameraLabel:   cameraLabel,
 		sceneLabel:    sceneLabel,
 		scenarioLabel: scenarioLabel,
+		lodLabel:      lodLabel,
 	}
 }
 
@@ -117,6 +128,16 @@ func (ip *InfoPanel) SetActiveScenario(name string) {
 	}
 }
 
+// SetLODInfo updates the level of detail display: the drawn level out of the
+// available simplified levels and its face count
+func (ip *InfoPanel) SetLODInfo(level, levels, faces int) {
+	if levels == 0 {
+		ip.lodLabel.SetText(fmt.Sprintf("LOD: full mesh, %d faces", faces))
+		return
+	}
+	ip.lodLabel.SetText(fmt.Sprintf("LOD: %d/%d, %d faces", level, levels, faces))
+}
+
 // GetPanel returns the underlying panel
 func (ip *InfoPanel) GetPanel() Panel {
 	return ip.panel
@@ -1,6 +1,11 @@
 package gui
 
-import rl "github.com/gen2brain/raylib-go/raylib"
+import (
+	"go4/geom"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
 
 // PrimitiveType represents the type of 3D primitive
 type PrimitiveType int
@@ -8,6 +13,18 @@ type PrimitiveType int
 const (
 	PrimitiveCube PrimitiveType = iota
 	PrimitiveTetrahedron
+	PrimitiveOctahedron
+	PrimitiveDodecahedron
+	PrimitiveIcosahedron
+	PrimitiveUVSphere
+	PrimitiveIcosphere
+	PrimitiveCylinder
+	PrimitiveCone
+	PrimitiveTorus
+	PrimitivePlane
+	PrimitiveCapsule
+
+	primitiveCount
 )
 
 // String returns the string representation of the primitive type
@@ -17,18 +34,136 @@ func (pt PrimitiveType) String() string {
 		return "Cube"
 	case PrimitiveTetrahedron:
 		return "Tetrahedron"
+	case PrimitiveOctahedron:
+		return "Octahedron"
+	case PrimitiveDodecahedron:
+		return "Dodecahedron"
+	case PrimitiveIcosahedron:
+		return "Icosahedron"
+	case PrimitiveUVSphere:
+		return "UV Sphere"
+	case PrimitiveIcosphere:
+		return "Icosphere"
+	case PrimitiveCylinder:
+		return "Cylinder"
+	case PrimitiveCone:
+		return "Cone"
+	case PrimitiveTorus:
+		return "Torus"
+	case PrimitivePlane:
+		return "Plane"
+	case PrimitiveCapsule:
+		return "Capsule"
 	default:
 		return "Unknown"
 	}
 }
 
-// PrimitiveSelector provides buttons to select different primitives
+// PrimitiveParams holds the parameter slider values. Each primitive uses a subset of them.
+type PrimitiveParams struct {
+	// Overall extent: edge length of the cube, diameter of round shapes, width of the plane
+	Size float64
+	// Divisions around the Z axis, or along X for the plane
+	Segments int
+	// Sphere bands, capsule bands per hemisphere, torus tube divisions, or plane divisions along Y
+	Rings int
+	// Icosphere subdivision level
+	Subdivisions int
+	// Height relative to Size for cylinder, cone and capsule, plane depth relative
+	// to its width, or tube radius relative to the ring radius for the torus,
+	// where it is limited to maxTorusProportion
+	Proportion float64
+}
+
+// maxTorusProportion keeps the torus tube from reaching its axis
+const maxTorusProportion = 0.95
+
+// DefaultPrimitiveParams returns parameters matching the size of the demo cube
+func DefaultPrimitiveParams() PrimitiveParams {
+	return PrimitiveParams{
+		Size:         200,
+		Segments:     24,
+		Rings:        12,
+		Subdivisions: 2,
+		Proportion:   0.5,
+	}
+}
+
+// NewPrimitiveMesh builds the mesh of a primitive from the parameters it uses
+func NewPrimitiveMesh(primitive PrimitiveType, params PrimitiveParams) *geom.Mesh {
+	radius := params.Size / 2
+	switch primitive {
+	case PrimitiveTetrahedron:
+		return geom.CreateTetrahedron(params.Size)
+	case PrimitiveOctahedron:
+		return geom.CreateOctahedron(radius)
+	case PrimitiveDodecahedron:
+		return geom.CreateDodecahedron(radius)
+	case PrimitiveIcosahedron:
+		return geom.CreateIcosahedron(radius)
+	case PrimitiveUVSphere:
+		return geom.CreateUVSphere(radius, params.Segments, params.Rings)
+	case PrimitiveIcosphere:
+		return geom.CreateIcosphere(radius, params.Subdivisions)
+	case PrimitiveCylinder:
+		return geom.CreateCylinder(radius, params.Size*params.Proportion, params.Segments, true)
+	case PrimitiveCone:
+		return geom.CreateCone(radius, params.Size*params.Proportion, params.Segments, true)
+	case PrimitiveTorus:
+		// A tube as thick as the ring would cross the axis and turn inside out
+		return geom.CreateTorus(radius, radius*min(params.Proportion, maxTorusProportion), params.Segments, params.Rings)
+	case PrimitivePlane:
+		return geom.CreatePlane(params.Size, params.Size*params.Proportion, params.Segments, params.Rings)
+	case PrimitiveCapsule:
+		return geom.CreateCapsule(radius, params.Size*params.Proportion, params.Segments, params.Rings)
+	default:
+		return geom.CreateCube(params.Size)
+	}
+}
+
+// primitiveParameter identifies one of the sliders
+type primitiveParameter int
+
+const (
+	parameterSize primitiveParameter = iota
+	parameterSegments
+	parameterRings
+	parameterSubdivisions
+	parameterProportion
+)
+
+// parameters returns the sliders that affect the primitive
+func (pt PrimitiveType) parameters() []primitiveParameter {
+	switch pt {
+	case PrimitiveUVSphere:
+		return []primitiveParameter{parameterSize, parameterSegments, parameterRings}
+	case PrimitiveIcosphere:
+		return []primitiveParameter{parameterSize, parameterSubdivisions}
+	case PrimitiveCylinder, PrimitiveCone:
+		return []primitiveParameter{parameterSize, parameterSegments, parameterProportion}
+	case PrimitiveTorus, PrimitivePlane, PrimitiveCapsule:
+		return []primitiveParameter{parameterSize, parameterSegments, parameterRings, parameterProportion}
+	default:
+		return []primitiveParameter{parameterSize}
+	}
+}
+
+const (
+	primitiveButtonWidth  = 88
+	primitiveButtonHeight = 26
+	primitiveButtonGap    = 4
+	primitiveSliderGap    = 4
+)
+
+// PrimitiveSelector provides buttons to select different primitives and
+// sliders for the parameters of the selected one
 type PrimitiveSelector struct {
 	panel        Panel
-	cubeButton   Button
-	tetraButton  Button
+	buttons      []Button
+	sliders      map[primitiveParameter]*Slider
 	selectedType PrimitiveType
-	onChange     func(PrimitiveType)
+	params       PrimitiveParams
+	onChange     func(PrimitiveType, PrimitiveParams)
 }
 
 // PrimitiveSelectorConfig holds configuration for creating a primitive selector
@@ -36,52 +171,72 @@ type PrimitiveSelectorConfig struct {
 	X, Y float32
 }
 
-// NewPrimitiveSelector creates a new primitive selector
-func NewPrimitiveSelector(config PrimitiveSelectorConfig, onChange func(PrimitiveType)) *PrimitiveSelector {
+// NewPrimitiveSelector creates a new primitive selector. onChange is called
+// with the current selection whenever the primitive or one of its parameters changes.
+func NewPrimitiveSelector(config PrimitiveSelectorConfig, onChange func(PrimitiveType, PrimitiveParams)) *PrimitiveSelector {
+	rows := (int(primitiveCount) + 1) / 2
+	slidersY := config.Y + 10 + float32(rows)*(primitiveButtonHeight+primitiveButtonGap) + 6
+
 	panelConfig := DefaultPanelConfig()
 	panelConfig.X = config.X
 	panelConfig.Y = config.Y
 	panelConfig.Width = 200
-	panelConfig.Height = 100
+	panelConfig.Height = slidersY - config.Y + 4*(defaultSliderHeight+primitiveSliderGap) + 6
 
 	panel := NewPanel(panelConfig).(*panel)
 
-	// Create buttons with better styling
-	cubeButton := NewButton(ButtonConfig{
-		X:           config.X + 10,
-		Y:           config.Y + 10,
-		Width:       180,
-		Height:      30,
-		Text:        "Cube",
-		NormalColor: rl.NewColor(60, 60, 60, 255),
-		HoverColor:  rl.NewColor(80, 80, 80, 255),
-		TextColor:   rl.White,
-		FontSize:    14,
-	})
-
-	tetraButton := NewButton(ButtonConfig{
-		X:           config.X + 10,
-		Y:           config.Y + 50,
-		Width:       180,
-		Height:      30,
-		Text:        "Tetrahedron",
-		NormalColor: rl.NewColor(60, 60, 60, 255),
-		HoverColor:  rl.NewColor(80, 80, 80, 255),
-		TextColor:   rl.White,
-		FontSize:    14,
-	})
-
-	// Add buttons to panel
-	panel.AddElement(cubeButton)
-	panel.AddElement(tetraButton)
-
-	return &PrimitiveSelector{
+	ps := &PrimitiveSelector{
 		panel:        panel,
-		cubeButton:   cubeButton,
-		tetraButton:  tetraButton,
+		sliders:      make(map[primitiveParameter]*Slider),
 		selectedType: PrimitiveCube,
+		params:       DefaultPrimitiveParams(),
 		onChange:     onChange,
 	}
+
+	for i := PrimitiveType(0); i < primitiveCount; i++ {
+		button := NewButton(ButtonConfig{
+			X:           config.X + 10 + float32(int(i)%2)*(primitiveButtonWidth+primitiveButtonGap),
+			Y:           config.Y + 10 + float32(int(i)/2)*(primitiveButtonHeight+primitiveButtonGap),
+			Width:       primitiveButtonWidth,
+			Height:      primitiveButtonHeight,
+			Text:        i.String(),
+			NormalColor: rl.NewColor(60, 60, 60, 255),
+			HoverColor:  rl.NewColor(80, 80, 80, 255),
+			TextColor:   rl.White,
+			FontSize:    12,
+		})
+		ps.buttons = append(ps.buttons, button)
+		panel.AddElement(button)
+	}
+
+	sliderConfigs := []struct {
+		parameter primitiveParameter
+		config    SliderConfig
+	}{
+		{parameterSize, SliderConfig{Label: "Size", Min: 50, Max: 400, Value: ps.params.Size}},
+		{parameterSegments, SliderConfig{Label: "Segments", Min: 3, Max: 64, Value: float64(ps.params.Segments)}},
+		{parameterRings, SliderConfig{Label: "Rings", Min: 2, Max: 32, Value: float64(ps.params.Rings)}},
+		{parameterSubdivisions, SliderConfig{Label: "Subdivisions", Min: 0, Max: 5, Value: float64(ps.params.Subdivisions)}},
+		{parameterProportion, SliderConfig{Label: "Proportion", Min: 0.1, Max: 2, Value: ps.params.Proportion, Precision: 2}},
+	}
+	for _, entry := range sliderConfigs {
+		sliderConfig := entry.config
+		sliderConfig.X = config.X + 10
+		sliderConfig.Y = slidersY
+		sliderConfig.Width = 180
+		sliderConfig.FontSize = 14
+		slider := NewSlider(sliderConfig)
+
+		parameter := entry.parameter
+		ps.sliders[parameter] = slider
+		panel.AddElement(&optionalElement{UIElement: slider, visible: func() bool {
+			return ps.usesParameter(parameter)
+		}})
+	}
+
+	ps.layoutSliders()
+	ps.updateButtonStyles()
+	return ps
 }
 
 // Update updates the primitive selector
@@ -94,19 +249,32 @@ func (ps *PrimitiveSelector) Draw() {
 	ps.panel.Draw()
 }
 
-// HandleInput handles button clicks
+// HandleInput handles button clicks and slider changes
 func (ps *PrimitiveSelector) HandleInput() {
-	if ps.cubeButton.IsClicked() {
-		ps.selectedType = PrimitiveCube
-		if ps.onChange != nil {
-			ps.onChange(PrimitiveCube)
+	changed := false
+	for i, button := range ps.buttons {
+		if button.IsClicked() && PrimitiveType(i) != ps.selectedType {
+			ps.selectedType = PrimitiveType(i)
+			ps.layoutSliders()
+			ps.updateButtonStyles()
+			changed = true
 		}
 	}
-	if ps.tetraButton.IsClicked() {
-		ps.selectedType = PrimitiveTetrahedron
-		if ps.onChange != nil {
-			ps.onChange(PrimitiveTetrahedron)
-		}
+
+	params := PrimitiveParams{
+		Size:         math.Round(ps.sliders[parameterSize].Value()),
+		Segments:     int(math.Round(ps.sliders[parameterSegments].Value())),
+		Rings:        int(math.Round(ps.sliders[parameterRings].Value())),
+		Subdivisions: int(math.Round(ps.sliders[parameterSubdivisions].Value())),
+		Proportion:   math.Round(ps.sliders[parameterProportion].Value()*100) / 100,
+	}
+	if params != ps.params {
+		ps.params = params
+		changed = true
+	}
+
+	if changed && ps.onChange != nil {
+		ps.onChange(ps.selectedType, ps.params)
 	}
 }
 
@@ -115,7 +283,61 @@ func (ps *PrimitiveSelector) GetSelectedType() PrimitiveType {
 	return ps.selectedType
 }
 
+// GetParams returns the current parameter values
+func (ps *PrimitiveSelector) GetParams() PrimitiveParams {
+	return ps.params
+}
+
 // GetPanel returns the underlying panel
 func (ps *PrimitiveSelector) GetPanel() Panel {
 	return ps.panel
 }
+
+func (ps *PrimitiveSelector) usesParameter(parameter primitiveParameter) bool {
+	for _, used := range ps.selectedType.parameters() {
+		if used == parameter {
+			return true
+		}
+	}
+	return false
+}
+
+// layoutSliders stacks the sliders of the selected primitive below the buttons
+func (ps *PrimitiveSelector) layoutSliders() {
+	last := ps.buttons[len(ps.buttons)-1].GetBounds()
+	x := ps.panel.GetBounds().X + 10
+	y := last.Y + last.Height + primitiveButtonGap + 6
+	for _, parameter := range ps.selectedType.parameters() {
+		ps.sliders[parameter].SetPosition(x, y)
+		y += defaultSliderHeight + primitiveSliderGap
+	}
+}
+
+func (ps *PrimitiveSelector) updateButtonStyles() {
+	for i, button := range ps.buttons {
+		if PrimitiveType(i) == ps.selectedType {
+			button.SetColors(rl.NewColor(30, 144, 255, 255), rl.NewColor(60, 164, 255, 255))
+		} else {
+			button.SetColors(rl.NewColor(60, 60, 60, 255), rl.NewColor(80, 80, 80, 255))
+		}
+	}
+}
+
+// optionalElement skips updating and drawing an element while it is hidden
+type optionalElement struct {
+	UIElement
+	visible func() bool
+}
+
+func (e *optionalElement) Update() bool {
+	if !e.visible() {
+		return false
+	}
+	return e.UIElement.Update()
+}
+
+func (e *optionalElement) Draw() {
+	if e.visible() {
+		e.UIElement.Draw()
+	}
+}
@@ -22,6 +22,55 @@ var rendererColorPalette = []rl.Color{
 	rl.SkyBlue,
 }
 
+// DepthSortType mirrors the renderer depth sort modes
+type DepthSortType int
+
+const (
+	DepthSortCentroid DepthSortType = iota
+	DepthSortBSP
+	DepthSortNone
+)
+
+// String returns the string representation of the depth sort type
+func (dt DepthSortType) String() string {
+	switch dt {
+	case DepthSortCentroid:
+		return "Centroid"
+	case DepthSortBSP:
+		return "BSP"
+	case DepthSortNone:
+		return "None"
+	default:
+		return "Unknown"
+	}
+}
+
+// ShadingType mirrors the renderer shading modes
+type ShadingType int
+
+const (
+	ShadingUnlit ShadingType = iota
+	ShadingFlat
+	ShadingGouraud
+	ShadingPhong
+)
+
+// String returns the string representation of the shading type
+func (st ShadingType) String() string {
+	switch st {
+	case ShadingUnlit:
+		return "Unlit"
+	case ShadingFlat:
+		return "Flat"
+	case ShadingGouraud:
+		return "Gouraud"
+	case ShadingPhong:
+		return "Phong"
+	default:
+		return "Unknown"
+	}
+}
+
 // RendererConfigData mirrors renderer visual configuration without introducing package cycles.
 type RendererConfigData struct {
 	BackgroundColor    rl.Color
@@ -32,6 +81,8 @@ type RendererConfigData struct {
 	DrawFaces          bool
 	DrawEdges          bool
 	UseBackfaceCulling bool
+	DepthSort          DepthSortType
+	Shading            ShadingType
 }
 
 // RendererConfigPanel provides interactive controls for renderer configuration.
@@ -42,6 +93,10 @@ type RendererConfigPanel struct {
 	drawEdges         *Toggle
 	randomColors      *Toggle
 	backfaceCull      *Toggle
+	depthSortLabel    Label
+	depthSortButton   Button
+	shadingLabel      Label
+	shadingButton     Button
 	alphaSlider       *Slider
 	faceLabel         Label
 	edgeLabel         Label
@@ -67,7 +122,7 @@ func NewRendererConfigPanel(layout RendererConfigPanelConfig, initial RendererCo
 	panelConfig.X = layout.X
 	panelConfig.Y = layout.Y
 	panelConfig.Width = 340
-	panelConfig.Height = 380
+	panelConfig.Height = 444
 
 	panel := NewPanel(panelConfig).(*panel)
 
@@ -107,11 +162,52 @@ func NewRendererConfigPanel(layout RendererConfigPanelConfig, initial RendererCo
 		Initial: initial.UseBackfaceCulling,
 	})
 
-	colorSectionY := toggleY + 140
 	labelWidth := float32(160)
 	previewX := layout.X + 10 + labelWidth + 8
 	buttonX := layout.X + 10 + labelWidth + 8 + 40 + 8
 
+	depthSortY := toggleY + 132
+	depthSortLabel := NewLabel(LabelConfig{
+		X:        layout.X + 10,
+		Y:        depthSortY,
+		Text:     depthSortLabelText(initial.DepthSort),
+		FontSize: 14,
+		Color:    rl.LightGray,
+	})
+	depthSortButton := NewButton(ButtonConfig{
+		X:           buttonX,
+		Y:           depthSortY - 2,
+		Width:       60,
+		Height:      24,
+		Text:        "Next",
+		NormalColor: rl.NewColor(60, 60, 60, 255),
+		HoverColor:  rl.NewColor(90, 90, 90, 255),
+		TextColor:   rl.White,
+		FontSize:    14,
+	})
+
+	shadingY := toggleY + 168
+	shadingLabel := NewLabel(LabelConfig{
+		X:        layout.X + 10,
+		Y:        shadingY,
+		Text:     shadingLabelText(initial.Shading),
+		FontSize: 14,
+		Color:    rl.LightGray,
+	})
+	shadingButton := NewButton(ButtonConfig{
+		X:           buttonX,
+		Y:           shadingY - 2,
+		Width:       60,
+		Height:      24,
+		Text:        "Next",
+		NormalColor: rl.NewColor(60, 60, 60, 255),
+		HoverColor:  rl.NewColor(90, 90, 90, 255),
+		TextColor:   rl.White,
+		FontSize:    14,
+	})
+
+	colorSectionY := toggleY + 204
+
 	faceLabel := newColorLabel(layout.X+10, colorSectionY, "Face color", initial.FaceColor, initial.AlphaValue)
 	facePreview := NewColorPreview(ColorPreviewConfig{
 		X:      previewX,
@@ -188,6 +284,10 @@ func NewRendererConfigPanel(layout RendererConfigPanelConfig, initial RendererCo
 	panel.AddElement(drawEdges)
 	panel.AddElement(randomColors)
 	panel.AddElement(backfaceCull)
+	panel.AddElement(depthSortLabel)
+	panel.AddElement(depthSortButton)
+	panel.AddElement(shadingLabel)
+	panel.AddElement(shadingButton)
 	panel.AddElement(faceLabel)
 	panel.AddElement(facePreview)
 	panel.AddElement(faceButton)
@@ -206,6 +306,10 @@ func NewRendererConfigPanel(layout RendererConfigPanelConfig, initial RendererCo
 		drawEdges:         drawEdges,
 		randomColors:      randomColors,
 		backfaceCull:      backfaceCull,
+		depthSortLabel:    depthSortLabel,
+		depthSortButton:   depthSortButton,
+		shadingLabel:      shadingLabel,
+		shadingButton:     shadingButton,
 		alphaSlider:       alphaSlider,
 		faceLabel:         faceLabel,
 		edgeLabel:         edgeLabel,
@@ -237,6 +341,14 @@ func (rcp *RendererConfigPanel) Update() {
 		rcp.state.BackgroundColor = nextPaletteColor(rcp.state.BackgroundColor)
 		rcp.backgroundPreview.SetColor(rcp.state.BackgroundColor)
 	}
+	if rcp.depthSortButton.IsClicked() {
+		rcp.state.DepthSort = (rcp.state.DepthSort + 1) % (DepthSortNone + 1)
+		rcp.depthSortLabel.SetText(depthSortLabelText(rcp.state.DepthSort))
+	}
+	if rcp.shadingButton.IsClicked() {
+		rcp.state.Shading = (rcp.state.Shading + 1) % (ShadingPhong + 1)
+		rcp.shadingLabel.SetText(shadingLabelText(rcp.state.Shading))
+	}
 
 	rcp.state.DrawFaces = rcp.drawFaces.Value()
 	rcp.state.DrawEdges = rcp.drawEdges.Value()
@@ -278,7 +390,9 @@ func rendererConfigEqual(a, b RendererConfigData) bool {
 		a.AlphaValue == b.AlphaValue &&
 		a.DrawFaces == b.DrawFaces &&
 		a.DrawEdges == b.DrawEdges &&
-		a.UseBackfaceCulling == b.UseBackfaceCulling
+		a.UseBackfaceCulling == b.UseBackfaceCulling &&
+		a.DepthSort == b.DepthSort &&
+		a.Shading == b.Shading
 }
 
 func nextPaletteColor(current rl.Color) rl.Color {
@@ -293,6 +407,14 @@ func nextPaletteColor(current rl.Color) rl.Color {
 	return rendererColorPalette[0]
 }
 
+func depthSortLabelText(depthSort DepthSortType) string {
+	return fmt.Sprintf("Depth sort: %s", depthSort)
+}
+
+func shadingLabelText(shading ShadingType) string {
+	return fmt.Sprintf("Shading: %s", shading)
+}
+
 func newColorLabel(x, y float32, prefix string, color rl.Color, alpha uint8) Label {
 	return NewLabel(LabelConfig{
 		X:        x,
@@ -26,11 +26,16 @@ type ScenarioPanelConfig struct {
 
 // NewScenarioPanel creates a new panel listing scenarios.
 func NewScenarioPanel(config ScenarioPanelConfig, scenarios []Scenario, onSelect func(int)) *ScenarioPanel {
+	buttonY := config.Y + 48
+	buttonHeight := float32(32)
+	buttonGap := float32(6)
+
+	// Leave room below the buttons for a two-line description
 	panelConfig := DefaultPanelConfig()
 	panelConfig.X = config.X
 	panelConfig.Y = config.Y
 	panelConfig.Width = 300
-	panelConfig.Height = 360
+	panelConfig.Height = max(360, 48+float32(len(scenarios))*(buttonHeight+buttonGap)+64)
 
 	panel := NewPanel(panelConfig).(*panel)
 
@@ -41,9 +46,6 @@ func NewScenarioPanel(config ScenarioPanelConfig, scenarios []Scenario, onSelect
 		FontSize: 18,
 	})
 
-	buttonY := config.Y + 48
-	buttonHeight := float32(32)
-	buttonGap := float32(6)
 	buttons := make([]Button, len(scenarios))
 
 	for i := range scenarios {
@@ -1,6 +1,9 @@
 package vis
 
-import "go4/geom"
+import (
+	"go4/geom"
+	"image"
+)
 
 // Camera defines the interface for camera operations
 type Camera interface {
@@ -27,6 +30,16 @@ type Camera interface {
 
 	// GetDistanceToScreen returns the distance to screen
 	GetDistanceToScreen() float64
+
+	// GetNearDistance returns the view depth below which nothing is drawn
+	GetNearDistance() float64
+
+	// GetFarDistance returns the view depth beyond which nothing is drawn
+	GetFarDistance() float64
+
+	// ViewMatrix maps world space to view space: X to the right, Y up and Z
+	// along the line of sight
+	ViewMatrix() geom.Matrix4
 }
 
 // Renderer defines the interface for rendering operations
@@ -34,6 +47,10 @@ type Renderer interface {
 	// Render renders a scene
 	Render(scene Scene)
 
+	// RenderScenes renders several scenes together, with faces of all of them
+	// ordered by depth
+	RenderScenes(scenes []Scene)
+
 	// SetCamera sets the camera for rendering
 	SetCamera(camera Camera)
 
@@ -45,6 +62,31 @@ type Renderer interface {
 
 	// GetConfig returns current renderer configuration
 	GetConfig() RendererConfig
+
+	// SelectLOD returns the level of detail drawn for the mesh at index in the
+	// scene: 0 for the mesh itself, i for its i-th simplified version
+	SelectLOD(scene Scene, index int) int
+}
+
+// SoftwareRenderer is a Renderer that rasterizes into an in-memory RGBA
+// framebuffer with a depth buffer, without a window or GPU
+type SoftwareRenderer interface {
+	Renderer
+
+	// RenderMesh draws the faces of the mesh into the framebuffer
+	RenderMesh(mesh *geom.Mesh)
+
+	// Clear fills the framebuffer with the background color and resets the depth buffer
+	Clear()
+
+	// Resize replaces the framebuffer with a cleared one of the given size
+	Resize(width, height int) error
+
+	// Image returns the framebuffer, which later frames draw into
+	Image() *image.RGBA
+
+	// Depth returns the view-space depth at the pixel, or +Inf where nothing was drawn
+	Depth(x, y int) float64
 }
 
 // Scene defines the interface for scene management
@@ -63,4 +105,11 @@ type Scene interface {
 
 	// MeshCount returns the number of meshes in the scene
 	MeshCount() int
+
+	// SetMeshLODs attaches simplified versions of the mesh at index, ordered
+	// from most to least detailed; nil removes them
+	SetMeshLODs(index int, lods []*geom.Mesh) error
+
+	// GetMeshLODs returns the simplified versions of the mesh at index, or nil
+	GetMeshLODs(index int) []*geom.Mesh
 }
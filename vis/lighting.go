@@ -0,0 +1,256 @@
+// lighting.go
+package vis
+
+import (
+	"fmt"
+	"go4/geom"
+	"math"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ShadingMode selects how faces are lit
+type ShadingMode int
+
+const (
+	// ShadingUnlit draws faces in their plain face or vertex colors
+	ShadingUnlit ShadingMode = iota
+	// ShadingFlat lights each face once, with its triangle normal at its centroid
+	ShadingFlat
+	// ShadingGouraud lights the corners with their vertex normals and
+	// interpolates the colors across the face
+	ShadingGouraud
+	// ShadingPhong interpolates the vertex normals across the face and lights
+	// every pixel
+	ShadingPhong
+)
+
+// String returns the name of the shading mode
+func (m ShadingMode) String() string {
+	switch m {
+	case ShadingUnlit:
+		return "Unlit"
+	case ShadingFlat:
+		return "Flat"
+	case ShadingGouraud:
+		return "Gouraud"
+	case ShadingPhong:
+		return "Phong"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseShadingMode returns the shading mode with the given name, ignoring case
+func ParseShadingMode(name string) (ShadingMode, error) {
+	for mode := ShadingUnlit; mode <= ShadingPhong; mode++ {
+		if strings.EqualFold(name, mode.String()) {
+			return mode, nil
+		}
+	}
+	return ShadingUnlit, fmt.Errorf("unknown shading mode %q", name)
+}
+
+// DirectionalLight shines in the same direction everywhere, like the sun
+type DirectionalLight struct {
+	Direction geom.Vector // The direction the light travels in, in world space
+	Color     rl.Color
+	Intensity float64
+}
+
+// PointLight shines from a position in all directions
+type PointLight struct {
+	Position  geom.Vertex
+	Color     rl.Color
+	Intensity float64
+	// Distance at which the intensity has fallen to half; 0 keeps it constant
+	Range float64
+}
+
+// Lighting holds the lights of the scene and how surfaces reflect them
+type Lighting struct {
+	AmbientColor     rl.Color
+	AmbientIntensity float64
+	Directional      []DirectionalLight
+	Points           []PointLight
+	// Strength and sharpness of the Blinn–Phong highlights; no specular
+	// strength gives matte surfaces
+	Specular  float64
+	Shininess float64
+}
+
+// DefaultLighting returns a dim ambient light and a white key light from
+// above, on the side of the default camera
+func DefaultLighting() Lighting {
+	return Lighting{
+		AmbientColor:     rl.White,
+		AmbientIntensity: 0.25,
+		Directional: []DirectionalLight{
+			{Direction: geom.NewVector(-0.4, -1, -1.2), Color: rl.White, Intensity: 0.85},
+		},
+		Specular:  0.3,
+		Shininess: 32,
+	}
+}
+
+// lightColor returns the color of the light scaled by its intensity
+func lightColor(c rl.Color, intensity float64) [3]float64 {
+	return [3]float64{
+		float64(c.R) / maxColorValue * intensity,
+		float64(c.G) / maxColorValue * intensity,
+		float64(c.B) / maxColorValue * intensity,
+	}
+}
+
+// shade returns the color of the surface point with the given base color and
+// unit normal, seen from eye, under the lights. Channels are in [0, 1] and the
+// alpha of base is kept.
+func (l Lighting) shade(base geom.Color, position, normal, eye geom.Vector) geom.Color {
+	diffuse := lightColor(l.AmbientColor, l.AmbientIntensity)
+	var specular [3]float64
+	view := eye.Subtracted(position)
+	view.Normalize()
+
+	addLight := func(toLight geom.Vector, color [3]float64) {
+		toLight.Normalize()
+		lambert := normal.Dot(toLight)
+		if lambert <= 0 {
+			return
+		}
+		halfway := toLight.Added(view)
+		halfway.Normalize()
+		highlight := l.Specular * math.Pow(math.Max(0, normal.Dot(halfway)), l.Shininess)
+		for c := range color {
+			diffuse[c] += lambert * color[c]
+			specular[c] += highlight * color[c]
+		}
+	}
+	for _, light := range l.Directional {
+		addLight(light.Direction.Multiplied(-1), lightColor(light.Color, light.Intensity))
+	}
+	for _, light := range l.Points {
+		toLight := geom.NewVectorFromVertex(light.Position)
+		toLight.Subtract(position)
+		intensity := light.Intensity
+		if light.Range > 0 {
+			distance := toLight.Length() / light.Range
+			intensity /= 1 + distance*distance
+		}
+		addLight(toLight, lightColor(light.Color, intensity))
+	}
+
+	channels := [3]float64{base.R, base.G, base.B}
+	for c := range channels {
+		channels[c] = math.Min(1, channels[c]*diffuse[c]+specular[c])
+	}
+	return geom.NewColor(channels[0], channels[1], channels[2], base.A)
+}
+
+// faceLighting lights one face for the shading mode, with its corner data in
+// world space
+type faceLighting struct {
+	mode      ShadingMode
+	lighting  Lighting
+	eye       geom.Vector
+	positions [3]geom.Vector
+	normals   [3]geom.Vector // Turned toward the eye
+	colors    [3]geom.Color  // Base colors before lighting
+	lit       [3]geom.Color  // Lit corner colors for flat and Gouraud shading
+}
+
+// newFaceLighting prepares the lighting of face f of the mesh, whose plain
+// color is flat, or returns nil for unlit faces. Vertex normals fall back to
+// the triangle normal, and vertex colors to flat.
+func newFaceLighting(config RendererConfig, mesh *geom.Mesh, f int, flat rl.Color, eye geom.Vector) *faceLighting {
+	if config.Shading == ShadingUnlit {
+		return nil
+	}
+	indices, err := mesh.FaceIndices(f)
+	if err != nil {
+		return nil
+	}
+	light := &faceLighting{mode: config.Shading, lighting: config.Lighting, eye: eye}
+	var corners [3]geom.Vertex
+	for k, index := range indices {
+		corners[k], _ = mesh.Vertex(index)
+		light.positions[k] = geom.NewVectorFromVertex(corners[k])
+		light.colors[k] = geom.NewColor(float64(flat.R)/maxColorValue, float64(flat.G)/maxColorValue, float64(flat.B)/maxColorValue, 1)
+		if vertexColor, err := mesh.VertexColor(index); err == nil {
+			light.colors[k] = vertexColor
+		}
+	}
+
+	faceNormal, _ := mesh.Normal(f)
+	if faceNormal.Length() == 0 {
+		faceNormal = geom.ComputeNormal(corners[0], corners[1], corners[2])
+	}
+	// Both sides are lit alike, so faces seen from behind use the reversed normals
+	side := 1.0
+	if faceNormal.Dot(eye.Subtracted(light.positions[0])) < 0 {
+		side = -1
+	}
+	for k, index := range indices {
+		light.normals[k] = faceNormal
+		if vertexNormal, err := mesh.VertexNormal(index); err == nil && vertexNormal.Length() > 0 {
+			light.normals[k] = vertexNormal
+		}
+		light.normals[k].Normalize()
+		light.normals[k].Multiply(side)
+	}
+
+	switch config.Shading {
+	case ShadingFlat:
+		centroid := interpolate(light.positions, [3]float64{1.0 / 3, 1.0 / 3, 1.0 / 3})
+		base := mixColors(light.colors, [3]float64{1.0 / 3, 1.0 / 3, 1.0 / 3})
+		faceNormal.Normalize()
+		faceNormal.Multiply(side)
+		color := config.Lighting.shade(base, centroid, faceNormal, eye)
+		light.lit = [3]geom.Color{color, color, color}
+	case ShadingGouraud:
+		for k := range light.lit {
+			light.lit[k] = config.Lighting.shade(light.colors[k], light.positions[k], light.normals[k], eye)
+		}
+	}
+	return light
+}
+
+// interpolate mixes the vectors with the barycentric weights
+func interpolate(vectors [3]geom.Vector, weights [3]float64) geom.Vector {
+	res := geom.Vector{}
+	for k := range vectors {
+		res.Add(vectors[k].Multiplied(weights[k]))
+	}
+	return res
+}
+
+// colorAt returns the lit color at the point of the face with the given
+// barycentric weights
+func (l *faceLighting) colorAt(weights [3]float64) geom.Color {
+	if l.mode != ShadingPhong {
+		return mixColors(l.lit, weights)
+	}
+	normal := interpolate(l.normals, weights)
+	normal.Normalize()
+	return l.lighting.shade(mixColors(l.colors, weights), interpolate(l.positions, weights), normal, l.eye)
+}
+
+// mixColors interpolates the colors with the barycentric weights
+func mixColors(colors [3]geom.Color, weights [3]float64) geom.Color {
+	var res geom.Color
+	for k, c := range colors {
+		res.R += weights[k] * c.R
+		res.G += weights[k] * c.G
+		res.B += weights[k] * c.B
+		res.A += weights[k] * c.A
+	}
+	return res
+}
+
+// toRLColor converts a color with channels in [0, 1] to raylib, with the given alpha
+func toRLColor(c geom.Color, alpha uint8) rl.Color {
+	channel := func(value float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, value)) * maxColorValue))
+	}
+	return rl.Color{R: channel(c.R), G: channel(c.G), B: channel(c.B), A: alpha}
+}
@@ -0,0 +1,113 @@
+package vis
+
+import (
+	"go4/geom"
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestLighting_Shade(t *testing.T) {
+	white := geom.NewColor(1, 1, 1, 1)
+	up := geom.NewVector(0, 0, 1)
+	origin := geom.Vector{}
+	eye := geom.NewVector(0, 0, 100)
+	lighting := Lighting{
+		AmbientColor:     rl.White,
+		AmbientIntensity: 0.2,
+		Directional:      []DirectionalLight{{Direction: geom.NewVector(0, 0, -1), Color: rl.White, Intensity: 0.5}},
+	}
+
+	cases := map[string]struct {
+		lighting Lighting
+		normal   geom.Vector
+		expected [3]float64
+	}{
+		"facing the light": {lighting, up, [3]float64{0.7, 0.7, 0.7}},
+		// Lambert's cosine law
+		"tilted":      {lighting, geom.NewVector(0, math.Sqrt(3)/2, 0.5), [3]float64{0.45, 0.45, 0.45}},
+		"facing away": {lighting, geom.NewVector(0, 0, -1), [3]float64{0.2, 0.2, 0.2}},
+		"red light": {Lighting{
+			Directional: []DirectionalLight{{Direction: geom.NewVector(0, 0, -1), Color: rl.Red, Intensity: 1}},
+		}, up, [3]float64{float64(rl.Red.R) / maxColorValue, float64(rl.Red.G) / maxColorValue, float64(rl.Red.B) / maxColorValue}},
+		// Half the intensity at the range
+		"point light": {Lighting{
+			Points: []PointLight{{Position: geom.NewVertex(0, 0, 10), Color: rl.White, Intensity: 1, Range: 10}},
+		}, up, [3]float64{0.5, 0.5, 0.5}},
+		// The highlight, scaled by the light, adds to the diffuse light when the
+		// light is mirrored into the eye
+		"specular": {Lighting{
+			Directional: []DirectionalLight{{Direction: geom.NewVector(0, 0, -1), Color: rl.White, Intensity: 0.5}},
+			Specular:    0.25,
+			Shininess:   16,
+		}, up, [3]float64{0.625, 0.625, 0.625}},
+	}
+	for name, c := range cases {
+		got := c.lighting.shade(white, origin, c.normal, eye)
+		for k, channel := range [3]float64{got.R, got.G, got.B} {
+			if math.Abs(channel-c.expected[k]) > 1e-9 {
+				t.Errorf("%s: expected %v, got %v", name, c.expected, got)
+				break
+			}
+		}
+		if got.A != 1 {
+			t.Errorf("%s: expected the alpha of the base color, got %v", name, got.A)
+		}
+	}
+}
+
+func TestParseShadingMode(t *testing.T) {
+	for mode := ShadingUnlit; mode <= ShadingPhong; mode++ {
+		if got, err := ParseShadingMode(mode.String()); err != nil || got != mode {
+			t.Errorf("Expected %v, got %v (%v)", mode, got, err)
+		}
+	}
+	if got, err := ParseShadingMode("gouraud"); err != nil || got != ShadingGouraud {
+		t.Errorf("Expected names to ignore case, got %v (%v)", got, err)
+	}
+	if _, err := ParseShadingMode("toon"); err == nil {
+		t.Errorf("Expected an error for an unknown shading mode")
+	}
+}
+
+func TestSoftwareRenderer_Shading(t *testing.T) {
+	// A point light just above the middle of a large ground lights it there
+	// but hardly at its far corners
+	ground := geom.CreatePlane(2000, 2000, 1, 1)
+	for v := 0; v < ground.VertexNumber(); v++ {
+		_ = ground.SetVertexNormal(v, geom.NewVector(0, 0, 1))
+	}
+	scene := NewScene()
+	scene.AddMesh(ground)
+
+	centerAt := func(shading ShadingMode) uint8 {
+		r := newTestSoftwareRenderer(t, 200, 150)
+		config := r.GetConfig()
+		config.FaceColor = rl.White
+		config.UseBackfaceCulling = false
+		config.Shading = shading
+		config.Lighting = Lighting{
+			Points: []PointLight{{Position: geom.NewVertex(0, 0, 100), Color: rl.White, Intensity: 1, Range: 100}},
+		}
+		r.SetConfig(config)
+		r.Render(scene)
+		return r.Image().RGBAAt(100, 75).R
+	}
+
+	if got := centerAt(ShadingUnlit); got != 255 {
+		t.Errorf("Expected the plain face color without shading, got %v", got)
+	}
+	// Lighting the ground right below the light gives half the intensity
+	if got := centerAt(ShadingPhong); math.Abs(float64(got)-127.5) > 2 {
+		t.Errorf("Expected Phong shading to light the middle by the interpolated normal, got %v", got)
+	}
+	// Gouraud shading only interpolates the dark corners, and flat shading
+	// lights each face at its centroid far from the light
+	if got := centerAt(ShadingGouraud); got > 5 {
+		t.Errorf("Expected Gouraud shading to miss the highlight between the corners, got %v", got)
+	}
+	if got := centerAt(ShadingFlat); got > 5 {
+		t.Errorf("Expected flat shading to light the faces at their centroids, got %v", got)
+	}
+}
@@ -0,0 +1,102 @@
+// lod.go
+package vis
+
+import (
+	"fmt"
+	"go4/geom"
+	"math"
+)
+
+// LODChainConfig controls BuildLODChain
+type LODChainConfig struct {
+	// Maximum number of simplified levels
+	Levels int
+	// Face count of each level relative to the previous one, between 0 and 1
+	Ratio float64
+	// No level is simplified below this many faces
+	MinFaces int
+}
+
+// DefaultLODChainConfig returns a chain of up to three levels, each with a quarter of the faces
+func DefaultLODChainConfig() LODChainConfig {
+	return LODChainConfig{
+		Levels:   3,
+		Ratio:    0.25,
+		MinFaces: 32,
+	}
+}
+
+// BuildLODChain returns simplified copies of the mesh for distant views, from
+// most to least detailed, each decimated from the one before. The chain ends
+// early when a level would fall below MinFaces or no more faces can be removed.
+func BuildLODChain(mesh *geom.Mesh, config LODChainConfig) ([]*geom.Mesh, error) {
+	if config.Levels < 0 {
+		return nil, fmt.Errorf("LOD levels must not be negative, got %d", config.Levels)
+	}
+	if !(config.Ratio > 0 && config.Ratio < 1) {
+		return nil, fmt.Errorf("LOD ratio must be between 0 and 1, got %v", config.Ratio)
+	}
+	if config.MinFaces < 0 {
+		return nil, fmt.Errorf("LOD minimum face count must not be negative, got %d", config.MinFaces)
+	}
+
+	var lods []*geom.Mesh
+	previous := mesh
+	for len(lods) < config.Levels {
+		target := int(float64(previous.FaceNumber()) * config.Ratio)
+		if target < max(config.MinFaces, 1) {
+			break
+		}
+
+		level := previous.Clone()
+		decimation := geom.DefaultDecimationConfig()
+		decimation.TargetFaces = target
+		decimation.MaxError = math.Inf(1)
+		removed, err := level.Decimate(decimation)
+		if err != nil {
+			return nil, err
+		}
+		if removed == 0 {
+			break
+		}
+		lods = append(lods, level)
+		previous = level
+	}
+	return lods, nil
+}
+
+// selectLOD returns the level of detail for the mesh at index in the scene,
+// chosen by the projected size of its bounding sphere
+func selectLOD(scene Scene, index int, camera Camera, thresholds []float64) int {
+	lods := scene.GetMeshLODs(index)
+	if len(lods) == 0 || len(thresholds) == 0 {
+		return 0
+	}
+	// The coarsest level has about the same bounds and is the cheapest to measure
+	bounds := lods[len(lods)-1].BoundingSphere()
+	radius := projectedRadius(bounds, cameraWorldPosition(camera), camera.GetDistanceToScreen())
+	return lodLevel(radius, thresholds, len(lods))
+}
+
+// projectedRadius returns the radius in pixels of the sphere on screen, seen
+// from the camera position with the given distance to the screen
+func projectedRadius(sphere geom.Sphere, cameraPosition geom.Vector, distanceToScreen float64) float64 {
+	offset := geom.NewVectorFromVertex(sphere.Center())
+	distance := offset.Subtracted(cameraPosition).Length()
+	if distance <= sphere.Radius() {
+		return math.Inf(1)
+	}
+	return distanceToScreen * sphere.Radius() / math.Sqrt(distance*distance-sphere.Radius()*sphere.Radius())
+}
+
+// lodLevel returns how many thresholds the projected radius falls below,
+// limited to the number of simplified levels
+func lodLevel(radius float64, thresholds []float64, levels int) int {
+	level := 0
+	for _, threshold := range thresholds {
+		if radius < threshold {
+			level++
+		}
+	}
+	return min(level, levels)
+}
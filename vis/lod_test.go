@@ -0,0 +1,130 @@
+package vis
+
+import (
+	"go4/geom"
+	"math"
+	"testing"
+)
+
+func TestBuildLODChain(t *testing.T) {
+	mesh := geom.CreateIcosphere(100, 4)
+	lods, err := BuildLODChain(mesh, DefaultLODChainConfig())
+	if err != nil {
+		t.Fatalf("BuildLODChain failed: %v", err)
+	}
+	if len(lods) != 3 {
+		t.Fatalf("Expected 3 levels, got %d", len(lods))
+	}
+	previous := mesh.FaceNumber()
+	for i, lod := range lods {
+		if lod.FaceNumber() > previous/4 {
+			t.Errorf("Level %d: expected at most %d faces, got %d", i+1, previous/4, lod.FaceNumber())
+		}
+		if report := lod.Validate(); !report.IsValid() {
+			t.Errorf("Level %d: expected a valid closed mesh, got\n%v", i+1, report)
+		}
+		previous = lod.FaceNumber()
+	}
+	if mesh.FaceNumber() != 5120 {
+		t.Errorf("Expected the source mesh to stay unchanged, got %d faces", mesh.FaceNumber())
+	}
+
+	// The chain stops before a level would fall below the minimum
+	short, _ := BuildLODChain(mesh, LODChainConfig{Levels: 5, Ratio: 0.25, MinFaces: 100})
+	if len(short) != 2 {
+		t.Errorf("Expected 2 levels above 100 faces, got %d", len(short))
+	}
+
+	for name, config := range map[string]LODChainConfig{
+		"levels":    {Levels: -1, Ratio: 0.5},
+		"ratio":     {Levels: 1, Ratio: 1},
+		"min faces": {Levels: 1, Ratio: 0.5, MinFaces: -1},
+	} {
+		if _, err := BuildLODChain(mesh, config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestScene_MeshLODs(t *testing.T) {
+	s := NewScene()
+	second := geom.CreateIcosphere(1, 2)
+	s.AddMesh(geom.CreateCube(1))
+	s.AddMesh(second)
+
+	lods := []*geom.Mesh{geom.CreateIcosphere(1, 1), geom.CreateIcosphere(1, 0)}
+	if err := s.SetMeshLODs(1, lods); err != nil {
+		t.Fatalf("SetMeshLODs failed: %v", err)
+	}
+	if err := s.SetMeshLODs(2, lods); err == nil {
+		t.Errorf("Expected an error for an index out of bounds")
+	}
+	if err := s.SetMeshLODs(0, []*geom.Mesh{nil}); err == nil {
+		t.Errorf("Expected an error for a nil level")
+	}
+	if s.GetMeshLODs(0) != nil {
+		t.Errorf("Expected no levels for the first mesh")
+	}
+
+	// Levels follow their mesh when an earlier one is removed
+	_ = s.RemoveMesh(0)
+	if got := s.GetMeshLODs(0); len(got) != 2 || got[0] != lods[0] || got[1] != lods[1] {
+		t.Errorf("Expected the levels to move with their mesh, got %v", got)
+	}
+	s.Clear()
+	s.AddMesh(second)
+	if s.GetMeshLODs(0) != nil {
+		t.Errorf("Expected Clear to drop the levels")
+	}
+}
+
+func TestRenderer_SelectLOD(t *testing.T) {
+	cam := NewCameraWithDefaults()
+	r := NewRenderer(cam, DefaultRendererConfig())
+	s := NewScene()
+	mesh := geom.CreateIcosphere(200, 3)
+	s.AddMesh(mesh)
+	if level := r.SelectLOD(s, 0); level != 0 {
+		t.Errorf("Expected the full mesh without levels, got %d", level)
+	}
+
+	lods, _ := BuildLODChain(mesh, DefaultLODChainConfig())
+	_ = s.SetMeshLODs(0, lods)
+	// 500 * 200 / sqrt(1000² - 200²) is about 102 pixels
+	if level := r.SelectLOD(s, 0); level != 0 {
+		t.Errorf("Expected the full mesh up close, got level %d", level)
+	}
+	cam.ScaleLinear(-400)
+	if level := r.SelectLOD(s, 0); level != 2 {
+		t.Errorf("Expected level 2 at about 20 pixels, got %d", level)
+	}
+	cam.ScaleLinear(-100)
+	if level := r.SelectLOD(s, 0); level != len(lods) {
+		t.Errorf("Expected the coarsest level at zero distance to screen, got %d", level)
+	}
+
+	config := r.GetConfig()
+	config.LODThresholds = nil
+	r.SetConfig(config)
+	cam.ScaleLinear(50)
+	if level := r.SelectLOD(s, 0); level != 0 {
+		t.Errorf("Expected the full mesh without thresholds, got level %d", level)
+	}
+}
+
+func TestProjectedRadius(t *testing.T) {
+	sphere := geom.NewSphere(geom.NewVertex(0, 0, 0), 3)
+	eye := geom.NewVector(0, 0, 5)
+	if radius := projectedRadius(sphere, eye, 100); math.Abs(radius-75) > 1e-9 {
+		t.Errorf("Expected 100 * 3 / 4 = 75 pixels, got %v", radius)
+	}
+	if radius := projectedRadius(sphere, geom.NewVector(1, 0, 0), 100); !math.IsInf(radius, 1) {
+		t.Errorf("Expected an infinite radius from inside the sphere, got %v", radius)
+	}
+	if level := lodLevel(math.Inf(1), []float64{60, 30}, 2); level != 0 {
+		t.Errorf("Expected level 0 for an infinite radius, got %d", level)
+	}
+	if level := lodLevel(1, []float64{60, 30, 15}, 2); level != 2 {
+		t.Errorf("Expected the coarsest level, got %d", level)
+	}
+}
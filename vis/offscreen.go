@@ -0,0 +1,163 @@
+// offscreen.go
+package vis
+
+import (
+	"bufio"
+	"fmt"
+	"go4/geom"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RenderToImage renders the scene with the software renderer, without opening
+// a window. A nil camera uses the default one and a non-positive size gives an
+// empty image.
+func RenderToImage(scene Scene, camera Camera, config RendererConfig, width, height int) image.Image {
+	if camera == nil {
+		camera = NewCameraWithDefaults()
+	}
+	r, err := NewSoftwareRenderer(camera, config, width, height)
+	if err != nil {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+	r.Render(scene)
+	return r.Image()
+}
+
+// WritePNG encodes the image as PNG
+func WritePNG(w io.Writer, img image.Image) error {
+	return png.Encode(w, img)
+}
+
+// WritePPM encodes the image as a binary (P6) portable pixmap; alpha is dropped
+func WritePPM(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "P6\n%d %d\n255\n", bounds.Dx(), bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			buf.Write([]byte{c.R, c.G, c.B})
+		}
+	}
+	return buf.Flush()
+}
+
+// maxPPMPixels bounds the images ReadPPM allocates, 8192 × 8192 pixels
+const maxPPMPixels = 1 << 26
+
+// ReadPPM decodes a binary (P6) portable pixmap with at most 8 bits per channel
+func ReadPPM(r io.Reader) (image.Image, error) {
+	buf := bufio.NewReader(r)
+
+	// The header is four whitespace-separated fields with optional # comments
+	var fields []int
+	magic := ""
+	for len(fields) < 3 {
+		token, err := readPPMToken(buf)
+		if err != nil {
+			return nil, fmt.Errorf("reading PPM header: %w", err)
+		}
+		if magic == "" {
+			if token != "P6" {
+				return nil, fmt.Errorf("unsupported PPM magic %q, expected P6", token)
+			}
+			magic = token
+			continue
+		}
+		var value int
+		if _, err := fmt.Sscanf(token, "%d", &value); err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid PPM header field %q", token)
+		}
+		fields = append(fields, value)
+	}
+	width, height, maxValue := fields[0], fields[1], fields[2]
+	if maxValue > 255 {
+		return nil, fmt.Errorf("unsupported PPM maximum value %d, expected at most 255", maxValue)
+	}
+	// Compared by division so that huge sizes cannot overflow
+	if width > maxPPMPixels/height {
+		return nil, fmt.Errorf("PPM size %dx%d exceeds %d pixels", width, height, maxPPMPixels)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	pixel := make([]byte, 3)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if _, err := io.ReadFull(buf, pixel); err != nil {
+				return nil, fmt.Errorf("reading PPM pixel (%d, %d): %w", x, y, err)
+			}
+			img.SetRGBA(x, y, color.RGBA{
+				R: uint8(int(pixel[0]) * 255 / maxValue),
+				G: uint8(int(pixel[1]) * 255 / maxValue),
+				B: uint8(int(pixel[2]) * 255 / maxValue),
+				A: 255,
+			})
+		}
+	}
+	return img, nil
+}
+
+// readPPMToken returns the next header field, skipping whitespace and comments,
+// and consumes the single whitespace character after it
+func readPPMToken(r *bufio.Reader) (string, error) {
+	var token []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(token) > 0 {
+				return string(token), nil
+			}
+			return "", err
+		}
+		switch {
+		case b == '#' && len(token) == 0:
+			if _, err := r.ReadString('\n'); err != nil {
+				return "", err
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if len(token) > 0 {
+				return string(token), nil
+			}
+		default:
+			token = append(token, b)
+		}
+	}
+}
+
+// SaveImage writes the image to path as PNG or PPM, chosen by the extension
+func SaveImage(path string, img image.Image) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return geom.WriteFile(path, func(w io.Writer) error {
+			return WritePNG(w, img)
+		})
+	case ".ppm":
+		return geom.WriteFile(path, func(w io.Writer) error {
+			return WritePPM(w, img)
+		})
+	}
+	return fmt.Errorf("unsupported image extension %q, expected .png or .ppm", filepath.Ext(path))
+}
+
+// LoadImage reads a PNG or PPM image, chosen by the extension
+func LoadImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return png.Decode(file)
+	case ".ppm":
+		return ReadPPM(file)
+	}
+	return nil, fmt.Errorf("unsupported image extension %q, expected .png or .ppm", filepath.Ext(path))
+}
@@ -0,0 +1,156 @@
+package vis
+
+import (
+	"bytes"
+	"flag"
+	"go4/geom"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden images in testdata")
+
+// goldenScene is a cube with a smaller colored cube in front, rendered by the golden test
+func goldenScene() Scene {
+	front := geom.CreateCube(120)
+	front.Translate(geom.NewVector(120, 120, 60))
+	for v := 0; v < front.VertexNumber(); v++ {
+		vertex, _ := front.Vertex(v)
+		_ = front.SetVertexColor(v, geom.NewColor(0.5+vertex.X()/500, 0.2, 0.5+vertex.Z()/500, 1))
+	}
+
+	scene := NewScene()
+	scene.AddMesh(geom.CreateCube(200))
+	scene.AddMesh(front)
+	return scene
+}
+
+func TestRenderToImage_Golden(t *testing.T) {
+	img := RenderToImage(goldenScene(), nil, DefaultRendererConfig(), 160, 120)
+	if img.Bounds().Dx() != 160 || img.Bounds().Dy() != 120 {
+		t.Fatalf("Expected a 160x120 image, got %v", img.Bounds())
+	}
+
+	config := DefaultGoldenConfig()
+	config.Update = *updateGolden
+	// Rasterization rounding may flip single pixels along edges on other architectures
+	config.MaxDifferentPixels = 8
+	if _, err := CheckGolden(filepath.Join("testdata", "golden_cubes.png"), img, config); err != nil {
+		t.Error(err)
+	}
+
+	if empty := RenderToImage(goldenScene(), nil, DefaultRendererConfig(), 0, 120); !empty.Bounds().Empty() {
+		t.Errorf("Expected an empty image for a zero width, got %v", empty.Bounds())
+	}
+}
+
+func TestCompareImages(t *testing.T) {
+	expected := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	actual := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			expected.SetRGBA(x, y, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+			actual.SetRGBA(x, y, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+		}
+	}
+	actual.SetRGBA(1, 1, color.RGBA{R: 203, G: 200, B: 200, A: 255})
+	actual.SetRGBA(2, 2, color.RGBA{R: 200, G: 100, B: 200, A: 255})
+
+	diff, err := CompareImages(expected, actual, 3)
+	if err != nil {
+		t.Fatalf("CompareImages failed: %v", err)
+	}
+	if diff.DifferentPixels != 1 || diff.MaxDifference != 100 {
+		t.Errorf("Expected one pixel differing by 100, got %d by %d", diff.DifferentPixels, diff.MaxDifference)
+	}
+	if red := diff.Diff.RGBAAt(2, 2); red != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("Expected the differing pixel in red, got %v", red)
+	}
+	if same := diff.Diff.RGBAAt(1, 1); same.R != same.G {
+		t.Errorf("Expected a pixel within tolerance in gray, got %v", same)
+	}
+
+	if _, err := CompareImages(expected, image.NewRGBA(image.Rect(0, 0, 3, 3)), 0); err == nil {
+		t.Errorf("Expected an error for images of different sizes")
+	}
+}
+
+func TestCheckGolden_WritesDiff(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "square.ppm")
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	config := DefaultGoldenConfig()
+	if _, err := CheckGolden(path, img, config); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Expected an error for a missing golden image, got %v", err)
+	}
+	config.Update = true
+	if _, err := CheckGolden(path, img, config); err != nil {
+		t.Fatalf("Writing the golden image failed: %v", err)
+	}
+
+	config.Update = false
+	img.SetRGBA(3, 4, color.RGBA{A: 255})
+	diff, err := CheckGolden(path, img, config)
+	if err == nil || diff.DifferentPixels != 1 {
+		t.Fatalf("Expected one differing pixel and an error, got %d and %v", diff.DifferentPixels, err)
+	}
+	written, err := LoadImage(filepath.Join(dir, "square.diff.png"))
+	if err != nil {
+		t.Fatalf("Expected a diff image: %v", err)
+	}
+	if c := color.RGBAModel.Convert(written.At(3, 4)).(color.RGBA); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("Expected the differing pixel in red in the diff image, got %v", c)
+	}
+}
+
+func TestPPM_RoundTrip(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 10)
+	}
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+
+	var buf bytes.Buffer
+	if err := WritePPM(&buf, img); err != nil {
+		t.Fatalf("WritePPM failed: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("P6\n3 2\n255\n")) {
+		t.Errorf("Expected a P6 header, got %q", buf.Bytes()[:11])
+	}
+	read, err := ReadPPM(&buf)
+	if err != nil {
+		t.Fatalf("ReadPPM failed: %v", err)
+	}
+	if diff, _ := CompareImages(img, read, 0); diff.DifferentPixels != 0 {
+		t.Errorf("Expected the same image back, got %d different pixels", diff.DifferentPixels)
+	}
+
+	commented := "P6 # comment\n2 1\n# another\n255\n\x01\x02\x03\x04\x05\x06"
+	if read, err := ReadPPM(strings.NewReader(commented)); err != nil || read.Bounds().Dx() != 2 {
+		t.Errorf("Expected comments to be skipped, got %v", err)
+	}
+	for _, size := range []string{"3037000500 3037000500", "9223372036854775807 2", "8193 8192"} {
+		if _, err := ReadPPM(strings.NewReader("P6\n" + size + "\n255\n")); err == nil {
+			t.Errorf("Expected an error for the size %s", size)
+		}
+	}
+	if _, err := ReadPPM(strings.NewReader("P3\n1 1\n255\n0 0 0\n")); err == nil {
+		t.Errorf("Expected an error for ASCII pixmaps")
+	}
+	if err := SaveImage(filepath.Join(t.TempDir(), "image.bmp"), img); err == nil {
+		t.Errorf("Expected an error for an unsupported extension")
+	}
+	if _, err := os.Stat(filepath.Join(t.TempDir(), "image.bmp")); err == nil {
+		t.Errorf("Expected no file for an unsupported extension")
+	}
+}
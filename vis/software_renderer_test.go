@@ -0,0 +1,166 @@
+package vis
+
+import (
+	"go4/geom"
+	"image/color"
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// newTestSoftwareRenderer draws opaque faces without edges from the default camera
+func newTestSoftwareRenderer(t *testing.T, width, height int) SoftwareRenderer {
+	t.Helper()
+	config := DefaultRendererConfig()
+	config.DrawEdges = false
+	config.AlphaValue = 255
+	config.BackgroundColor = rl.Color{R: 0, G: 0, B: 0, A: 255}
+	r, err := NewSoftwareRenderer(NewCameraWithDefaults(), config, width, height)
+	if err != nil {
+		t.Fatalf("NewSoftwareRenderer failed: %v", err)
+	}
+	return r
+}
+
+// coloredMesh returns a copy of the mesh with every vertex in the given color
+func coloredMesh(mesh *geom.Mesh, c geom.Color) *geom.Mesh {
+	res := mesh.Clone()
+	for v := 0; v < res.VertexNumber(); v++ {
+		_ = res.SetVertexColor(v, c)
+	}
+	return res
+}
+
+func TestSoftwareRenderer_Cube(t *testing.T) {
+	r := newTestSoftwareRenderer(t, 200, 150)
+	scene := NewScene()
+	scene.AddMesh(geom.CreateCube(200))
+	r.Render(scene)
+
+	gray := toRGBA(rl.Gray)
+	if center := r.Image().RGBAAt(100, 75); center != gray {
+		t.Errorf("Expected the face color at the center, got %v", center)
+	}
+	if corner := r.Image().RGBAAt(0, 0); corner != (color.RGBA{A: 255}) {
+		t.Errorf("Expected the background in the corner, got %v", corner)
+	}
+	// The line of sight, 45° from the Z axis, meets the top at 1000 - 100 / cos 45°
+	if expected := 1000 - 100*math.Sqrt2; math.Abs(r.Depth(100, 75)-expected) > 2 {
+		t.Errorf("Expected the top of the cube at depth %v, got %v", expected, r.Depth(100, 75))
+	}
+	if depth := r.Depth(0, 0); !math.IsInf(depth, 1) {
+		t.Errorf("Expected no depth in the corner, got %v", depth)
+	}
+
+	r.Clear()
+	if center := r.Image().RGBAAt(100, 75); center != (color.RGBA{A: 255}) {
+		t.Errorf("Expected Clear to restore the background, got %v", center)
+	}
+}
+
+func TestSoftwareRenderer_DepthTest(t *testing.T) {
+	near := coloredMesh(geom.CreateCube(100), geom.NewColor(1, 0, 0, 1))
+	near.Translate(geom.NewVector(150, 150, 150))
+	far := coloredMesh(geom.CreateCube(300), geom.NewColor(0, 0, 1, 1))
+
+	// The result must not depend on the drawing order
+	var images [2][]uint8
+	for i, meshes := range [][]*geom.Mesh{{near, far}, {far, near}} {
+		r := newTestSoftwareRenderer(t, 200, 150)
+		config := r.GetConfig()
+		config.UseBackfaceCulling = false
+		r.SetConfig(config)
+		scene := NewScene()
+		for _, mesh := range meshes {
+			scene.AddMesh(mesh)
+		}
+		r.Render(scene)
+		images[i] = r.Image().Pix
+
+		if center := r.Image().RGBAAt(100, 75); center != (color.RGBA{R: 255, A: 255}) {
+			t.Errorf("Order %d: expected the near red cube at the center, got %v", i, center)
+		}
+	}
+	if string(images[0]) != string(images[1]) {
+		t.Errorf("Expected the same image for both drawing orders")
+	}
+}
+
+func TestSoftwareRenderer_FillRule(t *testing.T) {
+	r := newTestSoftwareRenderer(t, 20, 20).(*softwareRenderer)
+	corner := func(x, y float64) rasterVertex {
+		return rasterVertex{x: x, y: y, z: 1, color: [3]float64{255, 255, 255}}
+	}
+	// Pairs of triangles whose shared diagonal, vertical or horizontal edge
+	// passes through pixel centers
+	triangles := [][3]rasterVertex{
+		{corner(2, 2), corner(12, 2), corner(12, 12)},
+		{corner(2, 2), corner(12, 12), corner(2, 12)},
+		{corner(13, 2), corner(16.5, 2), corner(16.5, 12)},
+		{corner(16.5, 2), corner(19.5, 12), corner(16.5, 12)},
+		{corner(2, 16.5), corner(18, 16.5), corner(10, 19.5)},
+		{corner(2, 16.5), corner(10, 13), corner(18, 16.5)},
+	}
+	// Half-transparent white over black turns pixels drawn twice lighter; the
+	// depth buffer is reset so that it does not hide them
+	for _, triangle := range triangles {
+		r.fillTriangle(triangle, 128, nil)
+		for i := range r.depth {
+			r.depth[i] = math.Inf(1)
+		}
+	}
+
+	once := 0
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			switch value := r.Image().RGBAAt(x, y).R; {
+			case value > 128:
+				t.Errorf("Pixel (%d, %d) was drawn twice", x, y)
+			case value == 128 && x >= 2 && x < 12 && y >= 2 && y < 12:
+				once++
+			}
+		}
+	}
+	if once != 100 {
+		t.Errorf("Expected the 10x10 square to cover 100 pixels, got %d", once)
+	}
+}
+
+func TestSoftwareRenderer_PerspectiveCorrect(t *testing.T) {
+	// A triangle reaching far away: its world-space centroid has equal weights
+	// for the three corners, which screen-space interpolation would get wrong
+	mesh := &geom.Mesh{}
+	a := mesh.AddVertex(geom.NewVertex(-200, 100, 0))
+	b := mesh.AddVertex(geom.NewVertex(600, 100, -200))
+	c := mesh.AddVertex(geom.NewVertex(100, -300, 200))
+	_, _ = mesh.AddFace(a, b, c)
+	_ = mesh.SetVertexColor(a, geom.NewColor(1, 0, 0, 1))
+	_ = mesh.SetVertexColor(b, geom.NewColor(0, 1, 0, 1))
+	_ = mesh.SetVertexColor(c, geom.NewColor(0, 0, 1, 1))
+
+	r := newTestSoftwareRenderer(t, 400, 300)
+	config := r.GetConfig()
+	config.UseBackfaceCulling = false
+	r.SetConfig(config)
+	r.RenderMesh(mesh)
+
+	centroid := r.GetCamera().Transform(geom.NewVertex(500.0/3, -100.0/3, 0), 400, 300)
+	pixel := r.Image().RGBAAt(int(centroid.X()), int(centroid.Y()))
+	for _, channel := range []uint8{pixel.R, pixel.G, pixel.B} {
+		if math.Abs(float64(channel)-85) > 4 {
+			t.Errorf("Expected about 85 in every channel at the centroid, got %v", pixel)
+			break
+		}
+	}
+}
+
+func TestSoftwareRenderer_InvalidSize(t *testing.T) {
+	if _, err := NewSoftwareRenderer(NewCameraWithDefaults(), DefaultRendererConfig(), 0, 10); err == nil {
+		t.Errorf("Expected an error for an empty framebuffer")
+	}
+	r := newTestSoftwareRenderer(t, 10, 10)
+	if err := r.Resize(20, 5); err != nil || r.Image().Bounds().Dx() != 20 || r.Image().Bounds().Dy() != 5 {
+		t.Errorf("Expected a 20x5 framebuffer, got %v with error %v", r.Image().Bounds(), err)
+	}
+}